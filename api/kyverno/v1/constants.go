@@ -8,6 +8,12 @@ const (
 	AnnotationPolicyCategory = "policies.kyverno.io/category"
 	AnnotationPolicySeverity = "policies.kyverno.io/severity"
 	AnnotationPolicyScored   = "policies.kyverno.io/scored"
+	// AnnotationPolicyEvents defines the annotation key used to limit the events emitted for a policy
+	AnnotationPolicyEvents = "policies.kyverno.io/events"
+	// AnnotationAdmissionCache defines the annotation key used to opt a policy out of admission response caching
+	AnnotationAdmissionCache = "policies.kyverno.io/admission-cache"
+	// AnnotationSkipUnchangedUpdates defines the annotation key used to reuse the previous outcome of validate rules on updates not changing the fields they reference
+	AnnotationSkipUnchangedUpdates = "policies.kyverno.io/skip-unchanged-updates"
 	// ValueKyvernoApp defines the kyverno application value
 	ValueKyvernoApp = "kyverno"
 )
@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"fmt"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -8,6 +11,8 @@ import (
 const (
 	// PolicyConditionReady means that the policy is ready
 	PolicyConditionReady = "Ready"
+	// PolicyConditionIdempotent means that the mutate rules of the policy don't change their own output
+	PolicyConditionIdempotent = "Idempotent"
 )
 
 const (
@@ -15,6 +20,8 @@ const (
 	PolicyReasonSucceeded = "Succeeded"
 	// PolicyReasonSucceeded is the reason set when the policy is not ready
 	PolicyReasonFailed = "Failed"
+	// PolicyReasonNotIdempotent is the reason set when mutate rules produce patches when applied to their own output
+	PolicyReasonNotIdempotent = "NotIdempotent"
 )
 
 // PolicyStatus mostly contains runtime information related to policy execution.
@@ -63,6 +70,31 @@ func (status *PolicyStatus) SetReady(ready bool) {
 	meta.SetStatusCondition(&status.Conditions, condition)
 }
 
+// SetNotIdempotent records the mutate rules producing patches when applied to their own output,
+// the condition is only valid for the given policy generation
+func (status *PolicyStatus) SetNotIdempotent(generation int64, rules ...string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               PolicyConditionIdempotent,
+		Status:             metav1.ConditionFalse,
+		Reason:             PolicyReasonNotIdempotent,
+		Message:            notIdempotentMessage(rules...),
+		ObservedGeneration: generation,
+	})
+}
+
+// IsNotIdempotent indicates if the given mutate rules are already recorded as not idempotent for the policy generation
+func (status *PolicyStatus) IsNotIdempotent(generation int64, rules ...string) bool {
+	condition := meta.FindStatusCondition(status.Conditions, PolicyConditionIdempotent)
+	return condition != nil &&
+		condition.Status == metav1.ConditionFalse &&
+		condition.ObservedGeneration == generation &&
+		condition.Message == notIdempotentMessage(rules...)
+}
+
+func notIdempotentMessage(rules ...string) string {
+	return fmt.Sprintf("mutate rules %s produce patches when applied to their own output", strings.Join(rules, ", "))
+}
+
 // IsReady indicates if the policy is ready to serve the admission request
 func (status *PolicyStatus) IsReady() bool {
 	condition := meta.FindStatusCondition(status.Conditions, PolicyConditionReady)
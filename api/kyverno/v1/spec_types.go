@@ -106,6 +106,12 @@ type Spec struct {
 	// Defaults to "false" if not specified.
 	// +optional
 	GenerateExistingOnPolicyUpdate bool `json:"generateExistingOnPolicyUpdate,omitempty" yaml:"generateExistingOnPolicyUpdate,omitempty"`
+
+	// Order controls the order in which mutate policies are applied to a resource. Policies with a lower
+	// order are applied first, policies with the same order are applied in the order of their names.
+	// Defaults to 0 if not specified.
+	// +optional
+	Order *int32 `json:"order,omitempty" yaml:"order,omitempty"`
 }
 
 func (s *Spec) SetRules(rules []Rule) {
@@ -212,6 +218,14 @@ func (s *Spec) GetMutateExistingOnPolicyUpdate() bool {
 	return s.MutateExistingOnPolicyUpdate
 }
 
+// GetOrder returns the order in which the policy mutates resources
+func (s *Spec) GetOrder() int32 {
+	if s.Order == nil {
+		return 0
+	}
+	return *s.Order
+}
+
 // IsGenerateExistingOnPolicyUpdate return GenerateExistingOnPolicyUpdate set value
 func (s *Spec) IsGenerateExistingOnPolicyUpdate() bool {
 	return s.GenerateExistingOnPolicyUpdate
@@ -0,0 +1,303 @@
+/*
+Copyright 2023 The Kubernetes authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2alpha1
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// KyvernoConfigurationConditionReady is the condition reporting if the configuration was applied
+	KyvernoConfigurationConditionReady = "Ready"
+	// KyvernoConfigurationReasonApplied is the reason used when the configuration was applied
+	KyvernoConfigurationReasonApplied = "Applied"
+	// KyvernoConfigurationReasonInvalid is the reason used when the configuration is invalid and the ConfigMap is used instead
+	KyvernoConfigurationReasonInvalid = "Invalid"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Cluster,shortName=kycfg,categories=kyverno
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type == "Ready")].status`
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// KyvernoConfiguration declares the runtime configuration of kyverno controllers.
+// It takes precedence over the kyverno ConfigMap unless it is invalid.
+type KyvernoConfiguration struct {
+	metav1.TypeMeta   `json:",inline,omitempty"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec declares the configuration.
+	Spec KyvernoConfigurationSpec `json:"spec"`
+
+	// Status reports if the configuration was applied.
+	// +optional
+	Status KyvernoConfigurationStatus `json:"status,omitempty"`
+}
+
+// GetStatus returns the configuration status
+func (c *KyvernoConfiguration) GetStatus() *KyvernoConfigurationStatus {
+	return &c.Status
+}
+
+// Validate implements programmatic validation
+func (c *KyvernoConfiguration) Validate() (errs field.ErrorList) {
+	return c.Spec.Validate(field.NewPath("spec"))
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KyvernoConfigurationList is a list of KyvernoConfiguration instances.
+type KyvernoConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []KyvernoConfiguration `json:"items"`
+}
+
+// KyvernoConfigurationSpec stores the settings shared by all controllers and the settings of each controller.
+type KyvernoConfigurationSpec struct {
+	// DefaultRegistry is the registry used for images not specifying one.
+	// Defaults to docker.io.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	DefaultRegistry string `json:"defaultRegistry,omitempty"`
+
+	// EnableDefaultRegistryMutation controls if the default registry is added to images not specifying one.
+	// Defaults to true.
+	// +optional
+	EnableDefaultRegistryMutation *bool `json:"enableDefaultRegistryMutation,omitempty"`
+
+	// ResourceFilters declares the resources ignored by kyverno.
+	// +optional
+	ResourceFilters []ResourceFilter `json:"resourceFilters,omitempty"`
+
+	// ExcludeGroups declares the groups whose requests are not processed, in addition to the
+	// kube-system service accounts, the nodes and the scheduler.
+	// +optional
+	ExcludeGroups []string `json:"excludeGroups,omitempty"`
+
+	// Admission declares the settings of the admission controller.
+	// +optional
+	Admission *AdmissionConfiguration `json:"admission,omitempty"`
+
+	// Reports declares the settings of the reports controller.
+	// +optional
+	Reports *ReportsConfiguration `json:"reports,omitempty"`
+
+	// Background declares the settings of the background controller.
+	// +optional
+	Background *BackgroundConfiguration `json:"background,omitempty"`
+
+	// Cleanup declares the settings of the cleanup controller.
+	// +optional
+	Cleanup *CleanupConfiguration `json:"cleanup,omitempty"`
+}
+
+// Validate implements programmatic validation
+func (s *KyvernoConfigurationSpec) Validate(path *field.Path) (errs field.ErrorList) {
+	if s.DefaultRegistry != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(s.DefaultRegistry) {
+			errs = append(errs, field.Invalid(path.Child("defaultRegistry"), s.DefaultRegistry, msg))
+		}
+	}
+	for i, filter := range s.ResourceFilters {
+		errs = append(errs, filter.Validate(path.Child("resourceFilters").Index(i))...)
+	}
+	if s.Admission != nil {
+		errs = append(errs, s.Admission.Validate(path.Child("admission"))...)
+	}
+	if s.Background != nil {
+		for i, filter := range s.Background.ResourceFilters {
+			errs = append(errs, filter.Validate(path.Child("background", "resourceFilters").Index(i))...)
+		}
+	}
+	if s.Cleanup != nil {
+		for i, filter := range s.Cleanup.ResourceFilters {
+			errs = append(errs, filter.Validate(path.Child("cleanup", "resourceFilters").Index(i))...)
+		}
+	}
+	return errs
+}
+
+// ResourceFilter selects resources ignored by kyverno, a resource is ignored when it matches all the fields set.
+// String fields support wildcards and an empty field matches any value.
+// Only the kind, namespace and name can be set in the ConfigMap resourceFilters, the other fields are only supported in a KyvernoConfiguration.
+// +kubebuilder:validation:MinProperties=1
+type ResourceFilter struct {
+	// Group is the resource API group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version is the resource API version.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind is the resource kind.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Subresource is the subresource targeted by the request.
+	// +optional
+	Subresource string `json:"subresource,omitempty"`
+
+	// Namespace is the resource namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the resource name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Selector selects the resource labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// NamespaceSelector selects the labels of the resource namespace.
+	// Cluster scoped resources don't match a namespace selector.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Usernames selects the user making the request.
+	// Resources processed outside of an admission request don't match usernames.
+	// +optional
+	Usernames []string `json:"usernames,omitempty"`
+
+	// UserGroups selects the groups of the user making the request.
+	// Resources processed outside of an admission request don't match user groups.
+	// +optional
+	UserGroups []string `json:"userGroups,omitempty"`
+}
+
+// Validate implements programmatic validation
+func (f *ResourceFilter) Validate(path *field.Path) (errs field.ErrorList) {
+	if reflect.DeepEqual(*f, ResourceFilter{}) {
+		errs = append(errs, field.Required(path, "A resource filter requires at least one field"))
+	}
+	if f.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(f.Selector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("selector"), f.Selector, err.Error()))
+		}
+	}
+	if f.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(f.NamespaceSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("namespaceSelector"), f.NamespaceSelector, err.Error()))
+		}
+	}
+	return errs
+}
+
+// AdmissionConfiguration stores the settings of the admission controller.
+type AdmissionConfiguration struct {
+	// ExcludeUsernames declares the users whose requests are not processed.
+	// +optional
+	ExcludeUsernames []string `json:"excludeUsernames,omitempty"`
+
+	// Webhooks declares the selectors added to the resource webhooks.
+	// +optional
+	Webhooks []WebhookConfiguration `json:"webhooks,omitempty"`
+}
+
+// Validate implements programmatic validation
+func (a *AdmissionConfiguration) Validate(path *field.Path) (errs field.ErrorList) {
+	for i, webhook := range a.Webhooks {
+		webhookPath := path.Child("webhooks").Index(i)
+		if webhook.NamespaceSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(webhook.NamespaceSelector); err != nil {
+				errs = append(errs, field.Invalid(webhookPath.Child("namespaceSelector"), webhook.NamespaceSelector, err.Error()))
+			}
+		}
+		if webhook.ObjectSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(webhook.ObjectSelector); err != nil {
+				errs = append(errs, field.Invalid(webhookPath.Child("objectSelector"), webhook.ObjectSelector, err.Error()))
+			}
+		}
+	}
+	return errs
+}
+
+// WebhookConfiguration stores the selectors of a resource webhook.
+type WebhookConfiguration struct {
+	// NamespaceSelector selects the namespaces of the resources sent to the webhook.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ObjectSelector selects the resources sent to the webhook.
+	// +optional
+	ObjectSelector *metav1.LabelSelector `json:"objectSelector,omitempty"`
+}
+
+// ReportsConfiguration stores the settings of the reports controller.
+type ReportsConfiguration struct {
+	// GenerateSuccessEvents controls if events are generated for successful policy results.
+	// +optional
+	GenerateSuccessEvents bool `json:"generateSuccessEvents,omitempty"`
+}
+
+// BackgroundConfiguration stores the settings of the background controller.
+type BackgroundConfiguration struct {
+	// ResourceFilters declares the resources ignored by the background controller,
+	// in addition to the resource filters shared by all controllers.
+	// +optional
+	ResourceFilters []ResourceFilter `json:"resourceFilters,omitempty"`
+}
+
+// CleanupConfiguration stores the settings of the cleanup controller.
+type CleanupConfiguration struct {
+	// ResourceFilters declares the resources never deleted by cleanup policies,
+	// in addition to the resource filters shared by all controllers.
+	// +optional
+	ResourceFilters []ResourceFilter `json:"resourceFilters,omitempty"`
+}
+
+// KyvernoConfigurationStatus stores the status of the configuration.
+type KyvernoConfigurationStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+// SetApplied sets the Ready condition of a configuration applied by the controllers
+func (s *KyvernoConfigurationStatus) SetApplied(generation int64) {
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               KyvernoConfigurationConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             KyvernoConfigurationReasonApplied,
+		Message:            "configuration applied",
+		ObservedGeneration: generation,
+	})
+}
+
+// SetInvalid sets the Ready condition of a configuration rejected by the controllers,
+// the kyverno ConfigMap is used instead
+func (s *KyvernoConfigurationStatus) SetInvalid(generation int64, errs field.ErrorList) {
+	meta.SetStatusCondition(&s.Conditions, metav1.Condition{
+		Type:               KyvernoConfigurationConditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             KyvernoConfigurationReasonInvalid,
+		Message:            errs.ToAggregate().Error(),
+		ObservedGeneration: generation,
+	})
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/metrics"
 	"github.com/kyverno/kyverno/pkg/policy"
 	"github.com/kyverno/kyverno/pkg/registryclient"
+	configutils "github.com/kyverno/kyverno/pkg/utils/config"
 	kubeinformers "k8s.io/client-go/informers"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	kyamlopenapi "sigs.k8s.io/kustomize/kyaml/openapi"
@@ -39,11 +40,12 @@ const (
 	resyncPeriod = 15 * time.Minute
 )
 
-func setupRegistryClient(ctx context.Context, logger logr.Logger, lister corev1listers.SecretNamespaceLister, imagePullSecrets string, allowInsecureRegistry bool) (registryclient.Client, error) {
+func setupRegistryClient(ctx context.Context, logger logr.Logger, lister corev1listers.SecretNamespaceLister, imagePullSecrets string, allowInsecureRegistry bool, metricsConfig config.MetricsConfiguration) (registryclient.Client, error) {
 	logger = logger.WithName("registry-client")
 	logger.Info("setup registry client...", "secrets", imagePullSecrets, "insecure", allowInsecureRegistry)
 	registryOptions := []registryclient.Option{
 		registryclient.WithTracing(),
+		registryclient.WithMetrics(metricsConfig),
 	}
 	secrets := strings.Split(imagePullSecrets, ",")
 	if imagePullSecrets != "" && len(secrets) > 0 {
@@ -117,6 +119,9 @@ func main() {
 	var (
 		genWorkers                int
 		maxQueuedEvents           int
+		eventsRateLimitQPS        float64
+		eventsRateLimitBurst      int
+		eventsAggregationWindow   time.Duration
 		imagePullSecrets          string
 		imageSignatureRepository  string
 		allowInsecureRegistry     bool
@@ -128,6 +133,9 @@ func main() {
 	flagset.StringVar(&imageSignatureRepository, "imageSignatureRepository", "", "Alternate repository for image signatures. Can be overridden per rule via `verifyImages.Repository`.")
 	flagset.BoolVar(&allowInsecureRegistry, "allowInsecureRegistry", false, "Whether to allow insecure connections to registries. Don't use this for anything but testing.")
 	flagset.IntVar(&maxQueuedEvents, "maxQueuedEvents", 1000, "Maximum events to be queued.")
+	flagset.Float64Var(&eventsRateLimitQPS, "eventsRateLimitQPS", 0, "Maximum events emitted per second for each event reason, rate limiting is disabled if zero.")
+	flagset.IntVar(&eventsRateLimitBurst, "eventsRateLimitBurst", 50, "Maximum burst of events emitted for each event reason.")
+	flagset.DurationVar(&eventsAggregationWindow, "eventsAggregationWindow", 0, "Time window during which identical events are aggregated into a single event, aggregation is disabled if zero.")
 	flagset.DurationVar(&leaderElectionRetryPeriod, "leaderElectionRetryPeriod", leaderelection.DefaultRetryPeriod, "Configure leader election retry period.")
 	// config
 	appConfig := internal.NewConfiguration(
@@ -171,7 +179,7 @@ func main() {
 	}
 	secretLister := kubeKyvernoInformer.Core().V1().Secrets().Lister().Secrets(config.KyvernoNamespace())
 	// setup registry client
-	rclient, err := setupRegistryClient(signalCtx, logger, secretLister, imagePullSecrets, allowInsecureRegistry)
+	rclient, err := setupRegistryClient(signalCtx, logger, secretLister, imagePullSecrets, allowInsecureRegistry, metricsConfig.Config())
 	if err != nil {
 		logger.Error(err, "failed to setup registry client")
 		os.Exit(1)
@@ -198,11 +206,19 @@ func main() {
 		logger.Error(err, "failed to initialize configuration")
 		os.Exit(1)
 	}
+	if err := configutils.LoadFromClient(signalCtx, configuration, kyvernoClient, configutils.Background); err != nil {
+		logger.Error(err, "failed to load kyverno configuration, using the configmap")
+	}
 	eventGenerator := event.NewEventGenerator(
 		dClient,
 		kyvernoInformer.Kyverno().V1().ClusterPolicies(),
 		kyvernoInformer.Kyverno().V1().Policies(),
 		maxQueuedEvents,
+		event.Options{
+			RateLimitQPS:      eventsRateLimitQPS,
+			RateLimitBurst:    eventsRateLimitBurst,
+			AggregationWindow: eventsAggregationWindow,
+		},
 		logging.WithName("EventGenerator"),
 	)
 	// this controller only subscribe to events, nothing is returned...
@@ -213,9 +229,10 @@ func main() {
 	)
 	engine := engine.NewEngine(
 		configuration,
+		metricsConfig.Config(),
 		dClient,
 		rclient,
-		engine.LegacyContextLoaderFactory(configMapResolver),
+		engine.LegacyContextLoaderFactory(configMapResolver, metricsConfig.Config()),
 		// TODO: do we need exceptions here ?
 		nil,
 	)
@@ -97,6 +97,11 @@ func (h *handlers) executePolicy(ctx context.Context, logger logr.Logger, policy
 						}
 						nsLabels = ns.GetLabels()
 					}
+					// resources filtered in the configuration are never deleted
+					if cfg.ToFilterResource(resourceInfo(resource, nsLabels)) {
+						debug.Info("resource is filtered in the configuration")
+						continue
+					}
 					// match namespaces
 					if err := match.CheckNamespace(policy.GetNamespace(), resource); err != nil {
 						debug.Info("resource namespace didn't match policy namespace", "result", err)
@@ -181,6 +186,19 @@ func (h *handlers) executePolicy(ctx context.Context, logger logr.Logger, policy
 	return multierr.Combine(errs...)
 }
 
+func resourceInfo(resource unstructured.Unstructured, nsLabels map[string]string) config.ResourceInfo {
+	gvk := resource.GroupVersionKind()
+	return config.ResourceInfo{
+		Group:           gvk.Group,
+		Version:         gvk.Version,
+		Kind:            gvk.Kind,
+		Namespace:       resource.GetNamespace(),
+		Name:            resource.GetName(),
+		Labels:          resource.GetLabels(),
+		NamespaceLabels: nsLabels,
+	}
+}
+
 func (h *handlers) createEvent(policy kyvernov2alpha1.CleanupPolicyInterface, resource unstructured.Unstructured, err error) {
 	var cleanuppol runtime.Object
 	if policy.GetNamespace() == "" {
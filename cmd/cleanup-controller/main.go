@@ -22,6 +22,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/leaderelection"
 	"github.com/kyverno/kyverno/pkg/metrics"
 	"github.com/kyverno/kyverno/pkg/tls"
+	configutils "github.com/kyverno/kyverno/pkg/utils/config"
 	"github.com/kyverno/kyverno/pkg/webhooks"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -58,6 +59,7 @@ func main() {
 	)
 	flagset := flag.NewFlagSet("cleanup-controller", flag.ExitOnError)
 	flagset.BoolVar(&dumpPayload, "dumpPayload", false, "Set this flag to activate/deactivate debug mode.")
+	dumpOptions := internal.AddDumpFlags(flagset)
 	flagset.DurationVar(&leaderElectionRetryPeriod, "leaderElectionRetryPeriod", leaderelection.DefaultRetryPeriod, "Configure leader election retry period.")
 	flagset.StringVar(&serverIP, "serverIP", "", "IP address where Kyverno controller runs. Only required if out-of-cluster.")
 	flagset.IntVar(&servicePort, "servicePort", 443, "Port used by the Kyverno Service resource and for webhook configurations.")
@@ -191,6 +193,10 @@ func main() {
 	if !internal.StartInformersAndWaitForCacheSync(ctx, kubeKyvernoInformer, kubeInformer, kyvernoInformer) {
 		os.Exit(1)
 	}
+	configuration := config.NewDefaultConfiguration()
+	if err := configutils.LoadFromClient(ctx, configuration, kyvernoClient, configutils.Cleanup); err != nil {
+		logger.Error(err, "failed to load kyverno configuration, using the defaults")
+	}
 	// create handlers
 	admissionHandlers := admissionhandlers.New(dClient)
 	cleanupHandlers := cleanuphandlers.New(dClient, cpolLister, polLister, nsLister)
@@ -208,9 +214,10 @@ func main() {
 		metricsConfig,
 		webhooks.DebugModeOptions{
 			DumpPayload: dumpPayload,
+			Dump:        *dumpOptions,
 		},
 		probes{},
-		config.NewDefaultConfiguration(),
+		configuration,
 	)
 	// start server
 	server.Run(ctx.Done())
@@ -21,6 +21,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/openapi"
 	policy2 "github.com/kyverno/kyverno/pkg/policy"
 	gitutils "github.com/kyverno/kyverno/pkg/utils/git"
+	policyutils "github.com/kyverno/kyverno/pkg/utils/policy"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/dynamic"
@@ -64,6 +65,10 @@ type ApplyCommandConfig struct {
 	ResourcePaths   []string
 	PolicyPaths     []string
 	GitBranch       string
+	SnapshotPath    string
+	HelmCharts      []string
+	HelmValues      []string
+	Kustomizations  []string
 	warnExitCode    int
 }
 
@@ -79,6 +84,13 @@ To apply on a folder of resources:
 To apply on a cluster:
         kyverno apply /path/to/policy.yaml /path/to/folderOfPolicies --cluster
 
+To apply on an offline cluster snapshot (as produced by "kubectl get -A -o yaml"):
+        kyverno apply /path/to/policy.yaml /path/to/folderOfPolicies --snapshot /path/to/dump.yaml
+
+To apply on resources rendered from a Helm chart or a Kustomize overlay:
+        kyverno apply /path/to/policy.yaml --helm-chart /path/to/chart --helm-values /path/to/values.yaml
+        kyverno apply /path/to/policy.yaml --kustomize /path/to/overlay
+
 To apply policies from a gitSourceURL on a cluster:
 	Example: Taking github.com as a gitSourceURL here. Some other standards  gitSourceURL are: gitlab.com , bitbucket.org , etc.
 		kyverno apply https://github.com/kyverno/policies/openshift/ --git-branch main --cluster
@@ -193,6 +205,10 @@ func Command() *cobra.Command {
 	cmd.Flags().StringVarP(&applyCommandConfig.Context, "context", "", "", "The name of the kubeconfig context to use")
 	cmd.Flags().StringVarP(&applyCommandConfig.GitBranch, "git-branch", "b", "", "test git repository branch")
 	cmd.Flags().BoolVarP(&applyCommandConfig.AuditWarn, "audit-warn", "", false, "If set to true, will flag audit policies as warnings instead of failures")
+	cmd.Flags().StringVar(&applyCommandConfig.SnapshotPath, "snapshot", "", "Path to a cluster dump (kubectl get -A -o yaml) used in place of a live cluster")
+	cmd.Flags().StringArrayVar(&applyCommandConfig.HelmCharts, "helm-chart", []string{}, "Path to a Helm chart (directory or archive) rendered to produce resources")
+	cmd.Flags().StringArrayVar(&applyCommandConfig.HelmValues, "helm-values", []string{}, "Path to a values file used when rendering Helm charts")
+	cmd.Flags().StringArrayVar(&applyCommandConfig.Kustomizations, "kustomize", []string{}, "Path to a Kustomize overlay rendered to produce resources")
 	cmd.Flags().IntVar(&applyCommandConfig.warnExitCode, "warn-exit-code", 0, "Set the exit code for warnings; if failures or errors are found, will exit 1")
 	return cmd
 }
@@ -200,7 +216,7 @@ func Command() *cobra.Command {
 func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, resources []*unstructured.Unstructured, skipInvalidPolicies SkippedInvalidPolicies, pvInfos []common.Info, err error) {
 	store.SetMock(true)
 	store.SetRegistryAccess(c.RegistryAccess)
-	if c.Cluster {
+	if c.Cluster || c.SnapshotPath != "" {
 		store.AllowApiCall(true)
 	}
 	fs := memfs.New()
@@ -209,6 +225,10 @@ func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, reso
 		return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("pass the values either using set flag or values_file flag", err)
 	}
 
+	if c.Cluster && c.SnapshotPath != "" {
+		return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("pass either the cluster flag or the snapshot flag, not both", err)
+	}
+
 	variables, globalValMap, valuesMap, namespaceSelectorMap, subresources, err := common.GetVariable(c.VariablesString, c.ValuesFile, fs, false, "")
 	if err != nil {
 		if !sanitizederror.IsErrorSanitized(err) {
@@ -242,6 +262,22 @@ func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, reso
 		}
 	}
 
+	var snapshot *common.Snapshot
+	if c.SnapshotPath != "" {
+		snapshot, err = common.LoadSnapshot(c.SnapshotPath)
+		if err != nil {
+			return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("failed to load cluster snapshot", err)
+		}
+		dClient = snapshot.Client()
+		// namespace labels passed in the values file take precedence over the snapshot
+		for namespace, labels := range snapshot.NamespaceLabels() {
+			if _, ok := namespaceSelectorMap[namespace]; !ok {
+				namespaceSelectorMap[namespace] = labels
+			}
+		}
+	}
+	useCluster := c.Cluster || snapshot != nil
+
 	if len(c.PolicyPaths) == 0 {
 		return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("require policy", err)
 	}
@@ -291,8 +327,9 @@ func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, reso
 		osExit(1)
 	}
 
-	if len(c.ResourcePaths) == 0 && !c.Cluster {
-		return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("resource file(s) or cluster required", err)
+	hasRenderedResources := len(c.HelmCharts) > 0 || len(c.Kustomizations) > 0
+	if len(c.ResourcePaths) == 0 && !useCluster && !hasRenderedResources {
+		return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("resource file(s), cluster or snapshot required", err)
 	}
 
 	mutateLogPathIsDir, err := checkMutateLogPath(c.MutateLogPath)
@@ -322,10 +359,22 @@ func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, reso
 		return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("failed to marshal mutated policy", err)
 	}
 
-	resources, err = common.GetResourceAccordingToResourcePath(fs, c.ResourcePaths, c.Cluster, policies, dClient, c.Namespace, c.PolicyReport, false, "")
+	if len(c.ResourcePaths) > 0 || useCluster {
+		resources, err = common.GetResourceAccordingToResourcePath(fs, c.ResourcePaths, useCluster, policies, dClient, c.Namespace, c.PolicyReport, false, "")
+		if err != nil {
+			fmt.Printf("Error: failed to load resources\nCause: %s\n", err)
+			osExit(1)
+		}
+	}
+
+	renderedResources, err := c.renderResources()
 	if err != nil {
-		fmt.Printf("Error: failed to load resources\nCause: %s\n", err)
-		osExit(1)
+		return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError("failed to render resources", err)
+	}
+	origins := map[*unstructured.Unstructured]string{}
+	for _, rendered := range renderedResources {
+		resources = append(resources, rendered.Resource)
+		origins[rendered.Resource] = rendered.Origin
 	}
 
 	if (len(resources) > 1 || len(policies) > 1) && c.VariablesString != "" {
@@ -385,6 +434,8 @@ func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, reso
 	skipInvalidPolicies.skipped = make([]string, 0)
 	skipInvalidPolicies.invalid = make([]string, 0)
 
+	// policies are applied in the same order as in the admission webhook
+	policyutils.SortByOrder(policies)
 	for _, policy := range policies {
 		_, err := policy2.Validate(policy, nil, true, openApiManager)
 		if err != nil {
@@ -432,8 +483,10 @@ func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, reso
 				Client:               dClient,
 				AuditWarn:            c.AuditWarn,
 				Subresources:         subresources,
+				Snapshot:             snapshot,
+				ResourceOrigin:       origins[resource],
 			}
-			_, info, err := common.ApplyPolicyOnResource(applyPolicyConfig)
+			_, info, _, err := common.ApplyPolicyOnResource(applyPolicyConfig)
 			if err != nil {
 				return rc, resources, skipInvalidPolicies, pvInfos, sanitizederror.NewWithError(fmt.Errorf("failed to apply policy %v on resource %v", policy.GetName(), resource.GetName()).Error(), err)
 			}
@@ -444,6 +497,26 @@ func (c *ApplyCommandConfig) applyCommandHelper() (rc *common.ResultCounts, reso
 	return rc, resources, skipInvalidPolicies, pvInfos, nil
 }
 
+// renderResources renders the Helm charts and Kustomize overlays passed on the command line
+func (c *ApplyCommandConfig) renderResources() ([]common.RenderedResource, error) {
+	var rendered []common.RenderedResource
+	for _, chart := range c.HelmCharts {
+		resources, err := common.RenderHelmChart(chart, c.Namespace, c.HelmValues...)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, resources...)
+	}
+	for _, kustomization := range c.Kustomizations {
+		resources, err := common.RenderKustomization(kustomization)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, resources...)
+	}
+	return rendered, nil
+}
+
 // checkMutateLogPath - checking path for printing mutated resource (-o flag)
 func checkMutateLogPath(mutateLogPath string) (mutateLogPathIsDir bool, err error) {
 	if mutateLogPath != "" {
@@ -112,6 +112,9 @@ func buildPolicyResults(infos []common.Info) map[string][]policyreportv1alpha2.P
 				result.Result = policyreportv1alpha2.PolicyResult(rule.Status)
 				result.Source = kyvernov1.ValueKyvernoApp
 				result.Timestamp = now
+				if info.Origin != "" {
+					result.Properties = map[string]string{"origin": info.Origin}
+				}
 				results[appname] = append(results[appname], result)
 			}
 		}
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/policy/lint"
+	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
+	"github.com/spf13/cobra"
+)
+
+var description = []string{
+	"Lints policies for risky constructs that are not rejected by policy validation.",
+	"Findings are reported with stable check IDs:",
+}
+
+var examples = []string{
+	"  # Lint a folder of policies\n  kyverno lint /path/to/policies",
+	"  # Produce a SARIF report\n  kyverno lint /path/to/policies -o sarif > lint.sarif",
+}
+
+// fileFinding is a finding with the file the policy was loaded from
+type fileFinding struct {
+	lint.Finding
+	File string `json:"file"`
+}
+
+// Command returns lint command
+func Command() *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:          "lint [policy...]",
+		Short:        description[0],
+		Long:         longDescription(),
+		SilenceUsage: true,
+		Example:      strings.Join(examples, "\n\n"),
+		RunE: func(cmd *cobra.Command, paths []string) error {
+			if len(paths) == 0 {
+				return errors.New("at least one policy path is required")
+			}
+			findings, err := lintPaths(cmd.ErrOrStderr(), paths)
+			if err != nil {
+				return err
+			}
+			if err := printFindings(cmd.OutOrStdout(), findings, output); err != nil {
+				return err
+			}
+			for _, finding := range findings {
+				if finding.Severity == lint.SeverityError {
+					return errors.New("policies have lint errors")
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, json or sarif)")
+	return cmd
+}
+
+func longDescription() string {
+	lines := description
+	for _, check := range lint.Checks() {
+		lines = append(lines, fmt.Sprintf("  %s %-32s %s", check.ID, check.Name, check.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lintPaths lints the policies in the given files and folders, then checks the mutate rules of the policies
+// for conflicts with the policies loaded before them. Files found in folders that don't decode as policies are skipped.
+func lintPaths(errOut io.Writer, paths []string) ([]fileFinding, error) {
+	var findings []fileFinding
+	var loaded []kyvernov1.PolicyInterface
+	var files []string
+	for _, path := range paths {
+		err := filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			ext := filepath.Ext(file)
+			if file != path && ext != ".yaml" && ext != ".yml" && ext != ".json" {
+				return nil
+			}
+			// We accept the risk of including a user provided file here.
+			data, err := os.ReadFile(file) // #nosec G304
+			if err != nil {
+				return err
+			}
+			policies, err := yamlutils.GetPolicy(data)
+			if err != nil && file != path {
+				fmt.Fprintf(errOut, "skipping %s: %v\n", file, err)
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to load policies from %s: %w", file, err)
+			}
+			for _, policy := range policies {
+				for _, finding := range lint.Lint(policy) {
+					findings = append(findings, fileFinding{Finding: finding, File: file})
+				}
+				loaded = append(loaded, policy)
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, policy := range loaded {
+		for _, finding := range lint.Conflicts(policy, loaded[:i]...) {
+			findings = append(findings, fileFinding{Finding: finding, File: files[i]})
+		}
+	}
+	return findings, nil
+}
+
+func printFindings(out io.Writer, findings []fileFinding, output string) error {
+	switch output {
+	case "json":
+		if findings == nil {
+			findings = []fileFinding{}
+		}
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	case "sarif":
+		data, err := json.MarshalIndent(newSarifLog(findings), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	case "text":
+		for _, f := range findings {
+			fmt.Fprintf(out, "%s: %s %s [%s] policy %s rule %s at %s: %s\n", f.File, strings.ToUpper(string(f.Severity)), f.CheckID, checkName(f.CheckID), f.Policy, f.Rule, f.Path, f.Message)
+		}
+		fmt.Fprintf(out, "\n%d findings\n", len(findings))
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %s", output)
+}
+
+func checkName(id string) string {
+	for _, check := range lint.Checks() {
+		if check.ID == id {
+			return check.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_LintSarif(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "policy.yaml"), []byte(`
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: deprecated
+spec:
+  rules:
+  - name: check
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    preconditions:
+      any:
+      - key: "{{ request.object.metadata.name }}"
+        operator: Equal
+        value: web
+    validate:
+      deny: {}
+`), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "values.yaml"), []byte(`foo: bar`), 0o600))
+
+	cmd := Command()
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{dir, "-o", "sarif"})
+	assert.NilError(t, cmd.Execute())
+
+	var log sarifLog
+	assert.NilError(t, json.Unmarshal(out.Bytes(), &log))
+	assert.Equal(t, log.Version, "2.1.0")
+	assert.Equal(t, len(log.Runs), 1)
+	assert.Equal(t, len(log.Runs[0].Tool.Driver.Rules), 7)
+	assert.Equal(t, len(log.Runs[0].Results), 1)
+	result := log.Runs[0].Results[0]
+	assert.Equal(t, result.RuleID, "KL004")
+	assert.Equal(t, result.Level, "warning")
+	assert.Equal(t, log.Runs[0].Tool.Driver.Rules[result.RuleIndex].ID, "KL004")
+	assert.Equal(t, result.Locations[0].PhysicalLocation.ArtifactLocation.URI, filepath.ToSlash(filepath.Join(dir, "policy.yaml")))
+	assert.Equal(t, result.Locations[0].LogicalLocations[0].FullyQualifiedName, "deprecated/spec.rules[0].preconditions.any[0].operator")
+}
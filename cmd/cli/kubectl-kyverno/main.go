@@ -7,7 +7,9 @@ import (
 
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/apply"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/jp"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/lint"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/oci"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/replay"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/test"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/version"
 	"github.com/spf13/cobra"
@@ -33,6 +35,8 @@ func main() {
 		apply.Command(),
 		test.Command(),
 		jp.Command(),
+		replay.Command(),
+		lint.Command(),
 	}
 
 	if enableExperimental() {
@@ -0,0 +1,201 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// stageResponseComplete is the audit stage carrying both the request and the response objects
+const stageResponseComplete = "ResponseComplete"
+
+// Event is the subset of an audit.k8s.io/v1 Event needed to rebuild an admission request
+type Event struct {
+	Level            string                     `json:"level"`
+	AuditID          types.UID                  `json:"auditID"`
+	Stage            string                     `json:"stage"`
+	RequestURI       string                     `json:"requestURI"`
+	Verb             string                     `json:"verb"`
+	User             authenticationv1.UserInfo  `json:"user"`
+	ImpersonatedUser *authenticationv1.UserInfo `json:"impersonatedUser,omitempty"`
+	ObjectRef        *ObjectReference           `json:"objectRef,omitempty"`
+	ResponseStatus   *metav1.Status             `json:"responseStatus,omitempty"`
+	RequestObject    json.RawMessage            `json:"requestObject,omitempty"`
+	ResponseObject   json.RawMessage            `json:"responseObject,omitempty"`
+	StageTimestamp   metav1.MicroTime           `json:"stageTimestamp"`
+}
+
+type ObjectReference struct {
+	Resource    string `json:"resource,omitempty"`
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name,omitempty"`
+	APIGroup    string `json:"apiGroup,omitempty"`
+	APIVersion  string `json:"apiVersion,omitempty"`
+	Subresource string `json:"subresource,omitempty"`
+}
+
+// LoadAuditLog reads audit events from a file written by the log backend (one event per line),
+// an audit.k8s.io EventList document is accepted as well
+func LoadAuditLog(file string) ([]Event, error) {
+	// We accept the risk of including a user provided file here.
+	f, err := os.Open(file) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseAuditLog(f)
+}
+
+// ParseAuditLog decodes audit events from the given reader
+func ParseAuditLog(r io.Reader) ([]Event, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	var list struct {
+		Kind  string  `json:"kind"`
+		Items []Event `json:"items"`
+	}
+	if err := json.Unmarshal(trimmed, &list); err == nil && list.Kind == "EventList" {
+		return list.Items, nil
+	}
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	// audit events with RequestResponse level can be large
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(text, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode audit event at line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// objectKey identifies the object an event refers to, it is used to track the last known state of objects
+func (e *Event) objectKey() string {
+	if e.ObjectRef == nil {
+		return ""
+	}
+	return path.Join(e.ObjectRef.APIGroup, e.ObjectRef.Resource, e.ObjectRef.Namespace, e.ObjectRef.Name)
+}
+
+// succeeded returns true if the api server accepted the request
+func (e *Event) succeeded() bool {
+	return e.ResponseStatus == nil || e.ResponseStatus.Code < 300
+}
+
+func admissionOperation(verb string) (admissionv1.Operation, bool) {
+	switch verb {
+	case "create":
+		return admissionv1.Create, true
+	case "update", "patch":
+		return admissionv1.Update, true
+	case "delete":
+		return admissionv1.Delete, true
+	}
+	return "", false
+}
+
+// ToAdmissionRequest rebuilds the admission request the api server sent to the webhooks for this event,
+// oldObject is the last known state of the object, it is required for UPDATE and DELETE requests
+// because audit events don't carry it.
+// It returns nil if the event can't be turned into an admission request.
+func (e *Event) ToAdmissionRequest(oldObject []byte) (*admissionv1.AdmissionRequest, error) {
+	if e.Stage != stageResponseComplete || e.ObjectRef == nil {
+		return nil, nil
+	}
+	operation, ok := admissionOperation(e.Verb)
+	if !ok {
+		return nil, nil
+	}
+	// a patch request object is the patch itself, the response object is the patched object
+	object := e.RequestObject
+	if e.Verb == "patch" || (operation != admissionv1.Delete && len(object) == 0) {
+		object = e.ResponseObject
+	}
+	if operation == admissionv1.Delete {
+		object = nil
+		if len(oldObject) == 0 {
+			return nil, nil
+		}
+	} else if len(object) == 0 {
+		return nil, nil
+	}
+	if operation == admissionv1.Update && len(oldObject) == 0 {
+		// the request can't be replayed faithfully without the old object
+		return nil, nil
+	}
+	kind, err := objectKind(object, oldObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode object of audit event %s: %w", e.AuditID, err)
+	}
+	if kind == "" {
+		return nil, nil
+	}
+	userInfo := e.User
+	if e.ImpersonatedUser != nil {
+		userInfo = *e.ImpersonatedUser
+	}
+	version := e.ObjectRef.APIVersion
+	if e.ObjectRef.APIGroup == "" && version == "" {
+		version = "v1"
+	}
+	gvk := metav1.GroupVersionKind{Group: e.ObjectRef.APIGroup, Version: version, Kind: kind}
+	gvr := metav1.GroupVersionResource{Group: e.ObjectRef.APIGroup, Version: version, Resource: e.ObjectRef.Resource}
+	request := &admissionv1.AdmissionRequest{
+		UID:                e.AuditID,
+		Kind:               gvk,
+		Resource:           gvr,
+		SubResource:        e.ObjectRef.Subresource,
+		RequestKind:        &gvk,
+		RequestResource:    &gvr,
+		RequestSubResource: e.ObjectRef.Subresource,
+		Name:               e.ObjectRef.Name,
+		Namespace:          e.ObjectRef.Namespace,
+		Operation:          operation,
+		UserInfo:           userInfo,
+		Object:             runtime.RawExtension{Raw: object},
+	}
+	if operation != admissionv1.Create {
+		request.OldObject = runtime.RawExtension{Raw: oldObject}
+	}
+	return request, nil
+}
+
+func objectKind(objects ...[]byte) (string, error) {
+	for _, object := range objects {
+		if len(object) == 0 {
+			continue
+		}
+		var meta metav1.TypeMeta
+		if err := json.Unmarshal(object, &meta); err != nil {
+			return "", err
+		}
+		if meta.Kind != "" {
+			return meta.Kind, nil
+		}
+	}
+	return "", nil
+}
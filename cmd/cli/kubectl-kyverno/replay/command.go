@@ -0,0 +1,125 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/common"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+)
+
+var description = []string{
+	"Replays Kubernetes audit logs against policies.",
+	"Audit events must be recorded at the Request or RequestResponse level, create, update, patch and delete requests are",
+	"rebuilt into admission requests and processed by the same mutation and validation handlers as the admission webhooks.",
+	"The old object of UPDATE and DELETE requests is the last state of the object seen in the audit log, requests on",
+	"objects whose previous state is unknown are skipped.",
+}
+
+var examples = []string{
+	"  # Replay an audit log against policies\n  kyverno replay /path/to/policies --audit-log /var/log/kubernetes/audit.log",
+	"  # Use a cluster dump for namespaces, role bindings and configmaps\n  kyverno replay /path/to/policies --audit-log audit.log --snapshot dump.yaml",
+	"  # Print the detailed report\n  kyverno replay /path/to/policies --audit-log audit.log -o yaml",
+}
+
+// Command returns replay command
+func Command() *cobra.Command {
+	var auditLog, snapshotPath, output string
+	var registryAccess bool
+	cmd := &cobra.Command{
+		Use:          "replay [policy...] --audit-log file",
+		Short:        description[0],
+		Long:         strings.Join(description, "\n"),
+		SilenceUsage: true,
+		Example:      strings.Join(examples, "\n\n"),
+		RunE: func(cmd *cobra.Command, policyPaths []string) error {
+			if auditLog == "" {
+				return errors.New("the audit-log flag is required")
+			}
+			if len(policyPaths) == 0 {
+				return errors.New("at least one policy path is required")
+			}
+			if output != "" && output != "yaml" && output != "json" {
+				return fmt.Errorf("unsupported output format %s", output)
+			}
+			policies, err := common.GetPoliciesFromPaths(memfs.New(), policyPaths, false, "")
+			if err != nil {
+				return err
+			}
+			events, err := LoadAuditLog(auditLog)
+			if err != nil {
+				return fmt.Errorf("failed to load audit log: %w", err)
+			}
+			snapshot, err := common.NewSnapshot()
+			if snapshotPath != "" {
+				snapshot, err = common.LoadSnapshot(snapshotPath)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to load snapshot: %w", err)
+			}
+			var options []registryclient.Option
+			if registryAccess {
+				options = append(options, registryclient.WithLocalKeychain())
+			}
+			rclient, err := registryclient.New(options...)
+			if err != nil {
+				return err
+			}
+			replayer, err := NewReplayer(log.Log.WithName("replay"), policies, snapshot, rclient)
+			if err != nil {
+				return err
+			}
+			report := replayer.Replay(context.Background(), events)
+			return printReport(cmd.OutOrStdout(), report, output)
+		},
+	}
+	cmd.Flags().StringVar(&auditLog, "audit-log", "", "Path to the audit log file (one JSON event per line)")
+	cmd.Flags().StringVar(&snapshotPath, "snapshot", "", "Path to a cluster dump (kubectl get -A -o yaml) providing namespaces, role bindings and configmaps")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format of the detailed report (yaml or json)")
+	cmd.Flags().BoolVar(&registryAccess, "registry", false, "If set to true, access the image registry using local docker credentials")
+	return cmd
+}
+
+func printReport(out io.Writer, report Report, output string) error {
+	switch output {
+	case "yaml":
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+	for _, result := range report.Results {
+		resource := result.Kind + "/" + result.Name
+		if result.Namespace != "" {
+			resource = result.Namespace + "/" + resource
+		}
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(out, "ERROR   %s %s %s by %s: %s\n", result.AuditID, result.Operation, resource, result.User, result.Error)
+		case result.Blocked:
+			fmt.Fprintf(out, "BLOCKED %s %s %s by %s: %s\n", result.AuditID, result.Operation, resource, result.User, strings.TrimSpace(result.Message))
+		}
+		if result.Mutated {
+			fmt.Fprintf(out, "MUTATED %s %s %s by %s: %s\n", result.AuditID, result.Operation, resource, result.User, result.Patches)
+		}
+	}
+	fmt.Fprintf(out, "\nreplayed %d of %d audit events: %d blocked, %d mutated, %d errors\n", report.Replayed, report.Events, report.Blocked, report.Mutated, report.Errors)
+	return nil
+}
@@ -0,0 +1,238 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/common"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/engine"
+	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
+	engineutils "github.com/kyverno/kyverno/pkg/engine/utils"
+	"github.com/kyverno/kyverno/pkg/event"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"github.com/kyverno/kyverno/pkg/openapi"
+	"github.com/kyverno/kyverno/pkg/policycache"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+	utilsengine "github.com/kyverno/kyverno/pkg/utils/engine"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/mutation"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/validation"
+	webhookutils "github.com/kyverno/kyverno/pkg/webhooks/utils"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	rbacv1listers "k8s.io/client-go/listers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Result is the outcome of replaying a single admission request
+type Result struct {
+	AuditID   string   `json:"auditID"`
+	Operation string   `json:"operation"`
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	User      string   `json:"user"`
+	Blocked   bool     `json:"blocked"`
+	Message   string   `json:"message,omitempty"`
+	Mutated   bool     `json:"mutated"`
+	Patches   string   `json:"patches,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Report summarizes a replay run
+type Report struct {
+	Events   int      `json:"events"`
+	Replayed int      `json:"replayed"`
+	Blocked  int      `json:"blocked"`
+	Mutated  int      `json:"mutated"`
+	Errors   int      `json:"errors"`
+	Results  []Result `json:"results,omitempty"`
+}
+
+// Replayer runs admission requests through the same mutation and validation handlers the webhooks use
+type Replayer struct {
+	logger        logr.Logger
+	configuration config.Configuration
+	pCache        policycache.Cache
+	pcBuilder     webhookutils.PolicyContextBuilder
+	nsLister      corev1listers.NamespaceLister
+	mutation      mutation.MutationHandler
+	validation    validation.ValidationHandler
+}
+
+// NewReplayer creates a Replayer for the given policies, the snapshot provides namespaces,
+// role bindings and configmaps the webhooks would otherwise read from the cluster
+func NewReplayer(logger logr.Logger, policies []kyvernov1.PolicyInterface, snapshot *common.Snapshot, rclient registryclient.Client) (*Replayer, error) {
+	configuration := config.NewDefaultConfiguration()
+	pCache := policycache.NewCache()
+	for _, policy := range policies {
+		key, err := cache.MetaNamespaceKeyFunc(policy)
+		if err != nil {
+			return nil, err
+		}
+		pCache.Set(key, policy, nil)
+	}
+	nsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	rbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	crbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, object := range snapshot.Objects() {
+		var err error
+		switch object.GroupVersionKind() {
+		case corev1.SchemeGroupVersion.WithKind("Namespace"):
+			err = addTyped(nsIndexer, object, &corev1.Namespace{})
+		case rbacv1.SchemeGroupVersion.WithKind("RoleBinding"):
+			err = addTyped(rbIndexer, object, &rbacv1.RoleBinding{})
+		case rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"):
+			err = addTyped(crbIndexer, object, &rbacv1.ClusterRoleBinding{})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	client := snapshot.Client()
+	nsLister := corev1listers.NewNamespaceLister(nsIndexer)
+	pcBuilder := webhookutils.NewPolicyContextBuilder(
+		configuration,
+		client,
+		rbacv1listers.NewRoleBindingLister(rbIndexer),
+		rbacv1listers.NewClusterRoleBindingLister(crbIndexer),
+	)
+	eng := engine.NewEngine(
+		configuration,
+		config.NewDefaultMetricsConfiguration(),
+		client,
+		rclient,
+		engine.LegacyContextLoaderFactory(snapshot.ConfigMapResolver(), config.NewDefaultMetricsConfiguration()),
+		nil,
+	)
+	metricsConfig := metrics.NewFakeMetricsConfig()
+	eventGen := event.NewFake()
+	return &Replayer{
+		logger:        logger,
+		configuration: configuration,
+		pCache:        pCache,
+		pcBuilder:     pcBuilder,
+		nsLister:      nsLister,
+		mutation:      mutation.NewMutationHandler(logger, eng, eventGen, openapi.NewFake(), nsLister, metricsConfig, false, nil),
+		validation:    validation.NewValidationHandler(logger, nil, eng, pCache, pcBuilder, eventGen, false, metricsConfig, configuration),
+	}, nil
+}
+
+func addTyped(indexer cache.Indexer, object *unstructured.Unstructured, typed runtime.Object) error {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.UnstructuredContent(), typed); err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", object.GetKind(), object.GetName(), err)
+	}
+	return indexer.Add(typed)
+}
+
+// Replay replays the audit events in order, the state of objects is tracked across events
+// so that UPDATE and DELETE requests get their old object
+func (r *Replayer) Replay(ctx context.Context, events []Event) Report {
+	report := Report{Events: len(events)}
+	objects := map[string][]byte{}
+	for i := range events {
+		e := &events[i]
+		key := e.objectKey()
+		request, err := e.ToAdmissionRequest(objects[key])
+		if err != nil {
+			report.Errors++
+			report.Results = append(report.Results, Result{AuditID: string(e.AuditID), Error: err.Error()})
+			continue
+		}
+		if request != nil {
+			result := r.ReplayRequest(ctx, request, e.StageTimestamp.Time)
+			report.Replayed++
+			if result.Blocked {
+				report.Blocked++
+			}
+			if result.Mutated {
+				report.Mutated++
+			}
+			if result.Error != "" {
+				report.Errors++
+			}
+			if result.Blocked || result.Mutated || result.Error != "" {
+				report.Results = append(report.Results, result)
+			}
+		}
+		if e.Stage == stageResponseComplete && e.succeeded() && key != "" {
+			if e.Verb == "delete" {
+				delete(objects, key)
+			} else if len(e.ResponseObject) != 0 {
+				objects[key] = e.ResponseObject
+			}
+		}
+	}
+	return report
+}
+
+// ReplayRequest runs a single admission request through the mutating then the validating handlers
+func (r *Replayer) ReplayRequest(ctx context.Context, request *admissionv1.AdmissionRequest, timestamp time.Time) Result {
+	result := Result{
+		AuditID:   string(request.UID),
+		Operation: string(request.Operation),
+		Kind:      request.Kind.Kind,
+		Namespace: request.Namespace,
+		Name:      request.Name,
+		User:      request.UserInfo.Username,
+	}
+	logger := r.logger.WithValues("auditID", request.UID, "kind", request.Kind.Kind, "operation", request.Operation)
+	kind, namespace := request.Kind.Kind, request.Namespace
+	if request.Operation != admissionv1.Delete {
+		mutatePolicies := r.pCache.GetPolicies(policycache.Mutate, kind, namespace)
+		if len(mutatePolicies) != 0 {
+			policyContext, err := r.pcBuilder.Build(request)
+			if err != nil {
+				result.Error = err.Error()
+				return result
+			}
+			if err := enginectx.MutateResourceWithImageInfo(request.Object.Raw, policyContext.JSONContext()); err != nil {
+				logger.Error(err, "failed to patch images info to resource, policies that mutate images may be impacted")
+			}
+			patches, warnings, _, err := r.mutation.HandleMutation(ctx, request, mutatePolicies, policyContext, timestamp)
+			if err != nil {
+				result.Error = err.Error()
+				return result
+			}
+			result.Warnings = append(result.Warnings, warnings...)
+			if len(patches) != 0 {
+				patched, err := engineutils.ApplyPatchNew(request.Object.Raw, patches)
+				if err != nil {
+					result.Error = fmt.Sprintf("failed to apply mutation patches: %v", err)
+					return result
+				}
+				result.Mutated = true
+				result.Patches = string(patches)
+				request = request.DeepCopy()
+				request.Object.Raw = patched
+			}
+		}
+	}
+	validatePolicies := r.pCache.GetPolicies(policycache.ValidateEnforce, kind, namespace)
+	validatePolicies = append(validatePolicies, r.pCache.GetPolicies(policycache.VerifyImagesValidate, kind, namespace)...)
+	if len(validatePolicies) == 0 {
+		return result
+	}
+	policyContext, err := r.pcBuilder.Build(request)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	namespaceLabels := utilsengine.GetNamespaceSelectorsFromNamespaceLister(kind, namespace, r.nsLister, logger)
+	policyContext = policyContext.WithNamespaceLabels(namespaceLabels)
+	ok, msg, warnings, _ := r.validation.HandleValidation(ctx, request, validatePolicies, policyContext, timestamp)
+	result.Warnings = append(result.Warnings, warnings...)
+	if !ok {
+		result.Blocked = true
+		result.Message = msg
+	}
+	return result
+}
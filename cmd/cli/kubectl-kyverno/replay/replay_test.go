@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/common"
+	"github.com/kyverno/kyverno/pkg/logging"
+	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+var policies = []byte(`
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: add-team
+spec:
+  rules:
+  - name: add-team
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    mutate:
+      patchStrategicMerge:
+        metadata:
+          labels:
+            +(team): platform
+---
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: disallow-latest
+spec:
+  validationFailureAction: Enforce
+  rules:
+  - name: disallow-latest
+    match:
+      any:
+      - resources:
+          kinds:
+          - Pod
+    validate:
+      message: using the latest tag is not allowed
+      pattern:
+        spec:
+          containers:
+          - image: "!*:latest"
+`)
+
+var auditLog = `
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"RequestResponse","auditID":"1","stage":"RequestReceived","verb":"create","user":{"username":"alice"},"objectRef":{"resource":"pods","namespace":"default","name":"web","apiVersion":"v1"}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"RequestResponse","auditID":"1","stage":"ResponseComplete","verb":"create","user":{"username":"alice"},"objectRef":{"resource":"pods","namespace":"default","name":"web","apiVersion":"v1"},"responseStatus":{"code":201},"requestObject":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"default"},"spec":{"containers":[{"name":"nginx","image":"nginx:1.23"}]}},"responseObject":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"default","labels":{"team":"platform"}},"spec":{"containers":[{"name":"nginx","image":"nginx:1.23"}]}}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"RequestResponse","auditID":"2","stage":"ResponseComplete","verb":"update","user":{"username":"bob"},"objectRef":{"resource":"pods","namespace":"default","name":"web","apiVersion":"v1"},"responseStatus":{"code":200},"requestObject":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"default","labels":{"team":"platform"}},"spec":{"containers":[{"name":"nginx","image":"nginx:latest"}]}},"responseObject":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"default","labels":{"team":"platform"}},"spec":{"containers":[{"name":"nginx","image":"nginx:latest"}]}}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"RequestResponse","auditID":"3","stage":"ResponseComplete","verb":"update","user":{"username":"bob"},"objectRef":{"resource":"pods","namespace":"default","name":"unknown","apiVersion":"v1"},"responseStatus":{"code":200},"requestObject":{"apiVersion":"v1","kind":"Pod","metadata":{"name":"unknown","namespace":"default"},"spec":{"containers":[{"name":"nginx","image":"nginx:latest"}]}}}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","level":"Metadata","auditID":"4","stage":"ResponseComplete","verb":"get","user":{"username":"bob"},"objectRef":{"resource":"pods","namespace":"default","name":"web","apiVersion":"v1"},"responseStatus":{"code":200}}
+`
+
+func Test_ToAdmissionRequest(t *testing.T) {
+	events, err := ParseAuditLog(strings.NewReader(auditLog))
+	assert.NilError(t, err)
+	assert.Equal(t, len(events), 5)
+
+	request, err := events[0].ToAdmissionRequest(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, request == nil)
+
+	request, err = events[1].ToAdmissionRequest(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, request.Operation, admissionv1.Create)
+	assert.Equal(t, request.Kind.Kind, "Pod")
+	assert.Equal(t, request.Resource.Resource, "pods")
+	assert.Equal(t, request.UserInfo.Username, "alice")
+
+	request, err = events[2].ToAdmissionRequest(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, request == nil)
+
+	request, err = events[2].ToAdmissionRequest(events[1].ResponseObject)
+	assert.NilError(t, err)
+	assert.Equal(t, request.Operation, admissionv1.Update)
+	assert.Equal(t, string(request.OldObject.Raw), string(events[1].ResponseObject))
+}
+
+func Test_Replay(t *testing.T) {
+	policies, err := yamlutils.GetPolicy(policies)
+	assert.NilError(t, err)
+	events, err := ParseAuditLog(strings.NewReader(auditLog))
+	assert.NilError(t, err)
+	snapshot, err := common.NewSnapshot()
+	assert.NilError(t, err)
+	replayer, err := NewReplayer(logging.GlobalLogger(), policies, snapshot, nil)
+	assert.NilError(t, err)
+
+	report := replayer.Replay(context.TODO(), events)
+	assert.Equal(t, report.Events, 5)
+	assert.Equal(t, report.Replayed, 2)
+	assert.Equal(t, report.Mutated, 1)
+	assert.Equal(t, report.Blocked, 1)
+	assert.Equal(t, report.Errors, 0)
+	assert.Equal(t, len(report.Results), 2)
+
+	assert.Equal(t, report.Results[0].AuditID, "1")
+	assert.Assert(t, report.Results[0].Mutated)
+	assert.Assert(t, !report.Results[0].Blocked)
+	assert.Assert(t, strings.Contains(report.Results[0].Patches, "team"))
+
+	assert.Equal(t, report.Results[1].AuditID, "2")
+	assert.Equal(t, report.Results[1].User, "bob")
+	assert.Assert(t, report.Results[1].Blocked)
+	assert.Assert(t, strings.Contains(report.Results[1].Message, "latest tag is not allowed"))
+}
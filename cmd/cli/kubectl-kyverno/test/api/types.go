@@ -2,6 +2,7 @@ package api
 
 import (
 	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
+	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/store"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -12,6 +13,12 @@ type Test struct {
 	Variables string        `json:"variables"`
 	UserInfo  string        `json:"userinfo"`
 	Results   []TestResults `json:"results"`
+	// APICalls declares mocked responses for apiCall context entries.
+	// They take precedence over the ones declared in the variables file.
+	APICalls []store.APICall `json:"apiCalls"`
+	// Images declares mocked images for imageRegistry context entries.
+	// They take precedence over the ones declared in the variables file.
+	Images []store.Image `json:"images"`
 }
 
 type TestResults struct {
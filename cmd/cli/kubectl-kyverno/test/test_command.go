@@ -35,6 +35,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -122,6 +123,8 @@ results:
   kind: <name>
   patchedResource: <path/to/patched/resource.yaml> (For mutate policies/rules only)
   result: <pass|fail|skip>
+apiCalls: (OPTIONAL, same format as in the variables file)
+images: (OPTIONAL, same format as in the variables file)
 
 **VARIABLES FILE FORMAT**:
 
@@ -152,6 +155,20 @@ subresources:
       kind: <kind of parent resource>
       group: <group of parent resource>
       version: <version of parent resource>
+# Mocked responses for apiCall context entries
+apiCalls:
+  - urlPath: /api/v1/namespaces/foo
+    response: <json object returned to the policy>
+  - service:
+      url: <service url>
+      method: <GET|POST> (OPTIONAL)
+      data: <request body to match> (OPTIONAL)
+    response: <json object returned to the policy>
+# Mocked images for imageRegistry context entries
+images:
+  - reference: <image reference, by tag>
+    manifest: <image manifest> (OPTIONAL)
+    configData: <image config> (OPTIONAL)
 
 **RESULT DESCRIPTIONS**:
 
@@ -167,7 +184,7 @@ func Command() *cobra.Command {
 	var cmd *cobra.Command
 	var testCase string
 	var fileName, gitBranch string
-	var registryAccess, failOnly, removeColor, manifestValidate, manifestMutate bool
+	var registryAccess, failOnly, removeColor, manifestValidate, manifestMutate, checkIdempotency bool
 	cmd = &cobra.Command{
 		Use: "test <path_to_folder_Containing_test.yamls> [flags]\n  kyverno test <path_to_gitRepository_with_dir> --git-branch <branchName>\n  kyverno test --manifest-mutate > kyverno-test.yaml\n  kyverno test --manifest-validate > kyverno-test.yaml",
 		// Args:    cobra.ExactArgs(1),
@@ -189,7 +206,7 @@ func Command() *cobra.Command {
 				manifest.PrintValidate()
 			} else {
 				store.SetRegistryAccess(registryAccess)
-				_, err = testCommandExecute(dirPath, fileName, gitBranch, testCase, failOnly, removeColor)
+				_, err = testCommandExecute(dirPath, fileName, gitBranch, testCase, failOnly, removeColor, checkIdempotency)
 				if err != nil {
 					log.Log.V(3).Info("a directory is required")
 					return err
@@ -206,6 +223,7 @@ func Command() *cobra.Command {
 	cmd.Flags().BoolVarP(&registryAccess, "registry", "", false, "If set to true, access the image registry using local docker credentials to populate external data")
 	cmd.Flags().BoolVarP(&failOnly, "fail-only", "", false, "If set to true, display all the failing test only as output for the test command")
 	cmd.Flags().BoolVarP(&removeColor, "remove-color", "", false, "Remove any color from output")
+	cmd.Flags().BoolVarP(&checkIdempotency, "check-idempotency", "", false, "If set to true, apply mutate policies again to the mutated resources and fail the rules producing further patches")
 	return cmd
 }
 
@@ -232,7 +250,7 @@ type testFilter struct {
 
 var ftable = []Table{}
 
-func testCommandExecute(dirPath []string, fileName string, gitBranch string, testCase string, failOnly bool, removeColor bool) (rc *resultCounts, err error) {
+func testCommandExecute(dirPath []string, fileName string, gitBranch string, testCase string, failOnly bool, removeColor bool, checkIdempotency bool) (rc *resultCounts, err error) {
 	var errors []error
 	fs := memfs.New()
 	rc = &resultCounts{}
@@ -352,7 +370,7 @@ func testCommandExecute(dirPath []string, fileName string, gitBranch string, tes
 					errors = append(errors, sanitizederror.NewWithError("failed to convert to JSON", err))
 					continue
 				}
-				if err := applyPoliciesFromPath(fs, policyBytes, true, policyresoucePath, rc, openApiManager, tf, failOnly, removeColor); err != nil {
+				if err := applyPoliciesFromPath(fs, policyBytes, true, policyresoucePath, rc, openApiManager, tf, failOnly, removeColor, checkIdempotency); err != nil {
 					return rc, sanitizederror.NewWithError("failed to apply test command", err)
 				}
 			}
@@ -364,7 +382,7 @@ func testCommandExecute(dirPath []string, fileName string, gitBranch string, tes
 	} else {
 		var testFiles int
 		path := filepath.Clean(dirPath[0])
-		errors = getLocalDirTestFiles(fs, path, fileName, rc, &testFiles, openApiManager, tf, failOnly, removeColor)
+		errors = getLocalDirTestFiles(fs, path, fileName, rc, &testFiles, openApiManager, tf, failOnly, removeColor, checkIdempotency)
 
 		if testFiles == 0 {
 			fmt.Printf("\n No test files found. Please provide test YAML files named kyverno-test.yaml \n")
@@ -393,7 +411,7 @@ func testCommandExecute(dirPath []string, fileName string, gitBranch string, tes
 	return rc, nil
 }
 
-func getLocalDirTestFiles(fs billy.Filesystem, path, fileName string, rc *resultCounts, testFiles *int, openApiManager openapi.Manager, tf *testFilter, failOnly, removeColor bool) []error {
+func getLocalDirTestFiles(fs billy.Filesystem, path, fileName string, rc *resultCounts, testFiles *int, openApiManager openapi.Manager, tf *testFilter, failOnly, removeColor, checkIdempotency bool) []error {
 	var errors []error
 
 	files, err := os.ReadDir(path)
@@ -402,7 +420,7 @@ func getLocalDirTestFiles(fs billy.Filesystem, path, fileName string, rc *result
 	}
 	for _, file := range files {
 		if file.IsDir() {
-			getLocalDirTestFiles(fs, filepath.Join(path, file.Name()), fileName, rc, testFiles, openApiManager, tf, failOnly, removeColor)
+			getLocalDirTestFiles(fs, filepath.Join(path, file.Name()), fileName, rc, testFiles, openApiManager, tf, failOnly, removeColor, checkIdempotency)
 			continue
 		}
 		if file.Name() == fileName {
@@ -418,7 +436,7 @@ func getLocalDirTestFiles(fs billy.Filesystem, path, fileName string, rc *result
 				errors = append(errors, sanitizederror.NewWithError("failed to convert json", err))
 				continue
 			}
-			if err := applyPoliciesFromPath(fs, valuesBytes, false, path, rc, openApiManager, tf, failOnly, removeColor); err != nil {
+			if err := applyPoliciesFromPath(fs, valuesBytes, false, path, rc, openApiManager, tf, failOnly, removeColor, checkIdempotency); err != nil {
 				errors = append(errors, sanitizederror.NewWithError(fmt.Sprintf("failed to apply test command from file %s", file.Name()), err))
 				continue
 			}
@@ -427,7 +445,7 @@ func getLocalDirTestFiles(fs billy.Filesystem, path, fileName string, rc *result
 	return errors
 }
 
-func buildPolicyResults(engineResponses []*engineapi.EngineResponse, testResults []api.TestResults, infos []common.Info, policyResourcePath string, fs billy.Filesystem, isGit bool) (map[string]policyreportv1alpha2.PolicyReportResult, []api.TestResults) {
+func buildPolicyResults(engineResponses []*engineapi.EngineResponse, testResults []api.TestResults, infos []common.Info, policyResourcePath string, fs billy.Filesystem, isGit bool, nonIdempotentRules sets.Set[string]) (map[string]policyreportv1alpha2.PolicyReportResult, []api.TestResults) {
 	results := make(map[string]policyreportv1alpha2.PolicyReportResult)
 	now := metav1.Timestamp{Seconds: time.Now().Unix()}
 
@@ -596,6 +614,9 @@ func buildPolicyResults(engineResponses []*engineapi.EngineResponse, testResults
 					result.Result = policyreportv1alpha2.StatusSkip
 				} else if rule.Status == engineapi.RuleStatusError {
 					result.Result = policyreportv1alpha2.StatusError
+				} else if nonIdempotentRules.Has(nonIdempotentRuleKey(policyNamespace, policyName, rule.Name, resourceNamespace, resourceKind, resourceName)) {
+					result.Result = policyreportv1alpha2.StatusFail
+					result.Message = "mutate rule is not idempotent, applying it again produced further patches"
 				} else {
 					var x string
 					for _, path := range patchedResourcePath {
@@ -655,6 +676,11 @@ func GetAllPossibleResultsKey(policyNamespace, policy, rule, resourceNamespace,
 	return resultsKey
 }
 
+// nonIdempotentRuleKey returns the key of a mutate rule producing further patches when applied again to a resource
+func nonIdempotentRuleKey(policyNamespace, policy, rule, resourceNamespace, kind, resource string) string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s-%s", policyNamespace, policy, rule, resourceNamespace, kind, resource)
+}
+
 func GetResultKeyAccordingToTestResults(policyNs, policy, rule, resourceNs, kind, resource string) string {
 	var resultKey string
 	resultKey = fmt.Sprintf("%s-%s-%s-%s", policy, rule, kind, resource)
@@ -730,13 +756,14 @@ func getFullPath(paths []string, policyResourcePath string, isGit bool) []string
 	return paths
 }
 
-func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, isGit bool, policyResourcePath string, rc *resultCounts, openApiManager openapi.Manager, tf *testFilter, failOnly, removeColor bool) (err error) {
+func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, isGit bool, policyResourcePath string, rc *resultCounts, openApiManager openapi.Manager, tf *testFilter, failOnly, removeColor, checkIdempotency bool) (err error) {
 	engineResponses := make([]*engineapi.EngineResponse, 0)
 	var dClient dclient.Interface
 	values := &api.Test{}
 	var variablesString string
 	var pvInfos []common.Info
 	var resultCounts common.ResultCounts
+	nonIdempotentRules := sets.New[string]()
 
 	store.SetMock(true)
 	if err := json.Unmarshal(policyBytes, values); err != nil {
@@ -768,6 +795,15 @@ func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, isGit bool,
 		return err
 	}
 
+	if len(values.APICalls) > 0 {
+		store.SetAPICalls(append(values.APICalls, store.GetAPICalls()...)...)
+	}
+	if len(values.Images) > 0 {
+		if err := store.SetImages(append(values.Images, store.GetImages()...)...); err != nil {
+			return sanitizederror.NewWithError("failed to load mocked images", err)
+		}
+	}
+
 	// get the user info as request info from a different file
 	var userInfo v1beta1.RequestInfo
 	var subjectInfo store.Subject
@@ -942,16 +978,20 @@ func applyPoliciesFromPath(fs billy.Filesystem, policyBytes []byte, isGit bool,
 				RuleToCloneSourceResource: ruleToCloneSourceResource,
 				Client:                    dClient,
 				Subresources:              subresources,
+				CheckIdempotency:          checkIdempotency,
 			}
-			ers, info, err := common.ApplyPolicyOnResource(applyPolicyConfig)
+			ers, info, rules, err := common.ApplyPolicyOnResource(applyPolicyConfig)
 			if err != nil {
 				return sanitizederror.NewWithError(fmt.Errorf("failed to apply policy %v on resource %v", policy.GetName(), resource.GetName()).Error(), err)
 			}
 			engineResponses = append(engineResponses, ers...)
 			pvInfos = append(pvInfos, info)
+			for rule := range rules {
+				nonIdempotentRules.Insert(nonIdempotentRuleKey(policy.GetNamespace(), policy.GetName(), rule, resource.GetNamespace(), resource.GetKind(), resource.GetName()))
+			}
 		}
 	}
-	resultsMap, testResults := buildPolicyResults(engineResponses, values.Results, pvInfos, policyResourcePath, fs, isGit)
+	resultsMap, testResults := buildPolicyResults(engineResponses, values.Results, pvInfos, policyResourcePath, fs, isGit, nonIdempotentRules)
 	resultErr := printTestResult(resultsMap, testResults, rc, failOnly, removeColor)
 	if resultErr != nil {
 		return sanitizederror.NewWithError("failed to print test result:", resultErr)
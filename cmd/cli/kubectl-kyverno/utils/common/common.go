@@ -33,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -61,6 +62,8 @@ type Values struct {
 	GlobalValues       map[string]string   `json:"globalValues"`
 	NamespaceSelectors []NamespaceSelector `json:"namespaceSelector"`
 	Subresources       []Subresource       `json:"subresources"`
+	APICalls           []store.APICall     `json:"apiCalls"`
+	Images             []store.Image       `json:"images"`
 }
 
 type Resource struct {
@@ -94,6 +97,9 @@ type ApplyPolicyConfig struct {
 	Client                    dclient.Interface
 	AuditWarn                 bool
 	Subresources              []Subresource
+	Snapshot                  *Snapshot
+	ResourceOrigin            string
+	CheckIdempotency          bool
 }
 
 // HasVariables - check for variables in the policy
@@ -232,6 +238,8 @@ func GetVariable(variablesString, valuesFile string, fs billy.Filesystem, isGit
 	subresources := make([]Subresource, 0)
 	globalValMap := make(map[string]string)
 	reqObjVars := ""
+	var apiCalls []store.APICall
+	var images []store.Image
 
 	var yamlFile []byte
 	var err error
@@ -333,6 +341,8 @@ func GetVariable(variablesString, valuesFile string, fs billy.Filesystem, isGit
 		}
 
 		subresources = values.Subresources
+		apiCalls = values.APICalls
+		images = values.Images
 	}
 
 	if reqObjVars != "" {
@@ -363,12 +373,17 @@ func GetVariable(variablesString, valuesFile string, fs billy.Filesystem, isGit
 	}
 
 	store.SetPolicies(storePolicies...)
+	store.SetAPICalls(apiCalls...)
+	if err := store.SetImages(images...); err != nil {
+		return variables, globalValMap, valuesMapResource, namespaceSelectorMap, subresources, sanitizederror.NewWithError("failed to load mocked images", err)
+	}
 
 	return variables, globalValMap, valuesMapResource, namespaceSelectorMap, subresources, nil
 }
 
-// ApplyPolicyOnResource - function to apply policy on resource
-func ApplyPolicyOnResource(c ApplyPolicyConfig) ([]*engineapi.EngineResponse, Info, error) {
+// ApplyPolicyOnResource - function to apply policy on resource, it also returns the names of the mutate rules
+// producing further patches when applied again to the mutated resource if c.CheckIdempotency is set
+func ApplyPolicyOnResource(c ApplyPolicyConfig) ([]*engineapi.EngineResponse, Info, sets.Set[string], error) {
 	var engineResponses []*engineapi.EngineResponse
 	namespaceLabels := make(map[string]string)
 	operationIsDelete := false
@@ -415,11 +430,14 @@ OuterLoop:
 		resourceNamespace := c.Resource.GetNamespace()
 		namespaceLabels = c.NamespaceSelectorMap[c.Resource.GetNamespace()]
 		if resourceNamespace != "default" && len(namespaceLabels) < 1 {
-			return engineResponses, Info{}, sanitizederror.NewWithError(fmt.Sprintf("failed to get namespace labels for resource %s. use --values-file flag to pass the namespace labels", c.Resource.GetName()), nil)
+			return engineResponses, Info{}, nil, sanitizederror.NewWithError(fmt.Sprintf("failed to get namespace labels for resource %s. use --values-file flag to pass the namespace labels", c.Resource.GetName()), nil)
 		}
 	}
 
 	resPath := fmt.Sprintf("%s/%s/%s", c.Resource.GetNamespace(), c.Resource.GetKind(), c.Resource.GetName())
+	if c.ResourceOrigin != "" {
+		resPath = fmt.Sprintf("%s (%s)", resPath, c.ResourceOrigin)
+	}
 	log.Log.V(3).Info("applying policy on resource", "policy", c.Policy.GetName(), "resource", resPath)
 
 	resourceRaw, err := c.Resource.MarshalJSON()
@@ -474,11 +492,16 @@ OuterLoop:
 			})
 		}
 	}
+	var cmResolver engineapi.ConfigmapResolver
+	if c.Snapshot != nil {
+		cmResolver = c.Snapshot.ConfigMapResolver()
+	}
 	eng := engine.NewEngine(
 		cfg,
+		config.NewDefaultMetricsConfiguration(),
 		c.Client,
 		registryclient.NewOrDie(),
-		engine.LegacyContextLoaderFactory(nil),
+		engine.LegacyContextLoaderFactory(cmResolver, config.NewDefaultMetricsConfiguration()),
 		nil,
 	)
 	policyContext := engine.NewPolicyContextWithJsonContext(ctx).
@@ -492,14 +515,21 @@ OuterLoop:
 		context.Background(),
 		policyContext,
 	)
+	var nonIdempotentRules sets.Set[string]
 	if mutateResponse != nil {
 		engineResponses = append(engineResponses, mutateResponse)
+		if c.CheckIdempotency && len(mutateResponse.GetPatches()) > 0 {
+			nonIdempotentRules, err = checkIdempotency(eng, policyContext.WithNewResource(mutateResponse.PatchedResource), mutateResponse)
+			if err != nil {
+				return engineResponses, Info{}, nil, sanitizederror.NewWithError("failed to verify mutation idempotency", err)
+			}
+		}
 	}
 
 	err = processMutateEngineResponse(c, mutateResponse, resPath)
 	if err != nil {
 		if !sanitizederror.IsErrorSanitized(err) {
-			return engineResponses, Info{}, sanitizederror.NewWithError("failed to print mutated result", err)
+			return engineResponses, Info{}, nil, sanitizederror.NewWithError("failed to print mutated result", err)
 		}
 	}
 
@@ -520,6 +550,7 @@ OuterLoop:
 			policyContext,
 		)
 		info = ProcessValidateEngineResponse(c.Policy, validateResponse, resPath, c.Rc, c.PolicyReport, c.AuditWarn)
+		info.Origin = c.ResourceOrigin
 	}
 
 	if validateResponse != nil && !validateResponse.IsEmpty() {
@@ -530,6 +561,7 @@ OuterLoop:
 	if verifyImageResponse != nil && !verifyImageResponse.IsEmpty() {
 		engineResponses = append(engineResponses, verifyImageResponse)
 		info = ProcessValidateEngineResponse(c.Policy, verifyImageResponse, resPath, c.Rc, c.PolicyReport, c.AuditWarn)
+		info.Origin = c.ResourceOrigin
 	}
 
 	var policyHasGenerate bool
@@ -542,7 +574,7 @@ OuterLoop:
 	if policyHasGenerate {
 		generateResponse := eng.ApplyBackgroundChecks(context.TODO(), policyContext)
 		if generateResponse != nil && !generateResponse.IsEmpty() {
-			newRuleResponse, err := handleGeneratePolicy(generateResponse, *policyContext, c.RuleToCloneSourceResource)
+			newRuleResponse, err := handleGeneratePolicy(generateResponse, *policyContext, c.RuleToCloneSourceResource, c.Snapshot)
 			if err != nil {
 				log.Log.Error(err, "failed to apply generate policy")
 			} else {
@@ -553,7 +585,7 @@ OuterLoop:
 		updateResultCounts(c.Policy, generateResponse, resPath, c.Rc, c.AuditWarn)
 	}
 
-	return engineResponses, info, nil
+	return engineResponses, info, nonIdempotentRules, nil
 }
 
 // PrintMutatedOutput - function to print output in provided file or directory
@@ -866,6 +898,19 @@ func SetInStoreContext(mutatedPolicies []kyvernov1.PolicyInterface, variables ma
 	return variables
 }
 
+// checkIdempotency applies the policy again to the mutated resource and returns the names of the rules producing further patches
+func checkIdempotency(eng engineapi.Engine, policyContext *engine.PolicyContext, mutateResponse *engineapi.EngineResponse) (sets.Set[string], error) {
+	nonIdempotentRules, err := engine.NonIdempotentRules(context.Background(), eng, policyContext, mutateResponse.Policy)
+	if err != nil {
+		return nil, err
+	}
+	rules := sets.New[string]()
+	for _, nonIdempotentRule := range nonIdempotentRules {
+		rules.Insert(nonIdempotentRule.Rule)
+	}
+	return rules, nil
+}
+
 func processMutateEngineResponse(c ApplyPolicyConfig, mutateResponse *engineapi.EngineResponse, resPath string) error {
 	var policyHasMutate bool
 	for _, rule := range autogen.ComputeRules(c.Policy) {
@@ -1075,19 +1120,29 @@ func initializeMockController(objects []runtime.Object) (*generate.GenerateContr
 	client.SetDiscovery(dclient.NewFakeDiscoveryClient(nil))
 	c := generate.NewGenerateControllerWithOnlyClient(client, engine.NewEngine(
 		config.NewDefaultConfiguration(),
+		config.NewDefaultMetricsConfiguration(),
 		client,
 		nil,
-		engine.LegacyContextLoaderFactory(nil),
+		engine.LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()),
 		nil,
 	))
 	return c, nil
 }
 
 // handleGeneratePolicy returns a new RuleResponse with the Kyverno generated resource configuration by applying the generate rule.
-func handleGeneratePolicy(generateResponse *engineapi.EngineResponse, policyContext engine.PolicyContext, ruleToCloneSourceResource map[string]string) ([]engineapi.RuleResponse, error) {
+// When a snapshot is given, its objects are available as clone sources.
+func handleGeneratePolicy(generateResponse *engineapi.EngineResponse, policyContext engine.PolicyContext, ruleToCloneSourceResource map[string]string, snapshot *Snapshot) ([]engineapi.RuleResponse, error) {
 	resource := policyContext.NewResource()
 	objects := []runtime.Object{&resource}
 	resources := []*unstructured.Unstructured{}
+	if snapshot != nil {
+		for _, obj := range snapshot.Objects() {
+			if obj.GroupVersionKind() == resource.GroupVersionKind() && obj.GetNamespace() == resource.GetNamespace() && obj.GetName() == resource.GetName() {
+				continue
+			}
+			objects = append(objects, obj.DeepCopy())
+		}
+	}
 	for _, rule := range generateResponse.PolicyResponse.Rules {
 		if path, ok := ruleToCloneSourceResource[rule.Name]; ok {
 			resourceBytes, err := getFileBytes(path)
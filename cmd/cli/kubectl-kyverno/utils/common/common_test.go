@@ -4,9 +4,11 @@ import (
 	"testing"
 
 	"github.com/kyverno/kyverno/api/kyverno/v1beta1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
 	"gotest.tools/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 var policyNamespaceSelector = []byte(`{
@@ -117,6 +119,59 @@ func Test_NamespaceSelector(t *testing.T) {
 	}
 }
 
+func Test_CheckIdempotency(t *testing.T) {
+	policy := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "add-sidecar"},
+		"spec": {
+			"rules": [{
+				"name": "add-label",
+				"match": {"any": [{"resources": {"kinds": ["Pod"]}}]},
+				"mutate": {"patchStrategicMerge": {"metadata": {"labels": {"sidecar": "injected"}}}}
+			}, {
+				"name": "append-container",
+				"match": {"any": [{"resources": {"kinds": ["Pod"]}}]},
+				"mutate": {"patchesJson6902": "- op: add\n  path: /spec/containers/-\n  value: {\"name\": \"sidecar\", \"image\": \"busybox\"}"}
+			}]
+		}
+	}`)
+	resource := []byte(`{"apiVersion":"v1","kind":"Pod","metadata":{"name":"nginx","namespace":"default"},"spec":{"containers":[{"image":"nginx","name":"nginx"}]}}`)
+	tests := []struct {
+		name             string
+		checkIdempotency bool
+		want             sets.Set[string]
+	}{{
+		name:             "enabled",
+		checkIdempotency: true,
+		want:             sets.New("append-container"),
+	}, {
+		name: "disabled",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := yamlutils.GetPolicy(policy)
+			assert.NilError(t, err)
+			resources, err := GetResource(resource)
+			assert.NilError(t, err)
+			responses, _, nonIdempotentRules, err := ApplyPolicyOnResource(ApplyPolicyConfig{
+				Policy:           policies[0],
+				Resource:         resources[0],
+				Rc:               &ResultCounts{},
+				CheckIdempotency: tt.checkIdempotency,
+			})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, nonIdempotentRules, tt.want)
+			// the responses are not changed by the check
+			for _, response := range responses {
+				for _, rule := range response.PolicyResponse.Rules {
+					assert.Equal(t, rule.Status, engineapi.RuleStatusPass)
+				}
+			}
+		})
+	}
+}
+
 func Test_IsGitSourcePath(t *testing.T) {
 	type TestCase struct {
 		path    []string
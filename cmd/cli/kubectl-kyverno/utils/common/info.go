@@ -7,9 +7,11 @@ import (
 
 // Info stores the policy application results for all matched resources
 // Namespace is set to empty "" if resource is cluster wide resource
+// Origin is set when the resource was rendered from a Helm chart or a Kustomize overlay
 type Info struct {
 	PolicyName string
 	Namespace  string
+	Origin     string
 	Results    []EngineResponseResult
 }
 
@@ -0,0 +1,142 @@
+package common
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// helmReleaseName is the release name used to render charts, the same as `helm template` uses by default
+const helmReleaseName = "release-name"
+
+// RenderedResource is a resource rendered from a Helm chart or a Kustomize overlay,
+// Origin describes where it comes from
+type RenderedResource struct {
+	Resource *unstructured.Unstructured
+	Origin   string
+}
+
+// RenderKustomization builds the kustomization in the given directory in-process
+func RenderKustomization(dir string) ([]RenderedResource, error) {
+	kustomizer := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resMap, err := kustomizer.Run(filesys.MakeFsOnDisk(), dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization %s: %w", dir, err)
+	}
+	var rendered []RenderedResource
+	origin := "kustomize:" + dir
+	for _, res := range resMap.Resources() {
+		res.RemoveBuildAnnotations()
+		data, err := res.AsYAML()
+		if err != nil {
+			return nil, err
+		}
+		resources, err := GetResource(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode resource rendered from %s: %w", origin, err)
+		}
+		for _, resource := range resources {
+			rendered = append(rendered, RenderedResource{Resource: resource, Origin: origin})
+		}
+	}
+	return rendered, nil
+}
+
+// RenderHelmChart renders the chart in the given directory or archive in-process, the same way `helm template` does,
+// each resource origin is the template it was rendered from
+func RenderHelmChart(chartPath string, namespace string, valuesFiles ...string) ([]RenderedResource, error) {
+	chart, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load helm chart %s: %w", chartPath, err)
+	}
+	values := map[string]interface{}{}
+	for _, valuesFile := range valuesFiles {
+		fileValues, err := chartutil.ReadValuesFile(valuesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read helm values %s: %w", valuesFile, err)
+		}
+		values = mergeValues(values, fileValues)
+	}
+	if err := chartutil.ProcessDependencies(chart, values); err != nil {
+		return nil, fmt.Errorf("failed to process helm chart %s dependencies: %w", chartPath, err)
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      helmReleaseName,
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValues(chart, values, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute helm chart %s values: %w", chartPath, err)
+	}
+	files, err := engine.Render(chart, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm chart %s: %w", chartPath, err)
+	}
+	return collectHelmTemplates(files)
+}
+
+// collectHelmTemplates decodes the rendered templates in a stable order, notes and partials are ignored
+func collectHelmTemplates(files map[string]string) ([]RenderedResource, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		base := path.Base(name)
+		if strings.HasPrefix(base, "_") || strings.HasSuffix(name, "NOTES.txt") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var rendered []RenderedResource
+	for _, name := range names {
+		origin := "helm:" + name
+		documents, err := yamlutils.SplitDocuments([]byte(files[name]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to split resources rendered from %s: %w", origin, err)
+		}
+		for _, document := range documents {
+			if len(strings.TrimSpace(string(document))) == 0 {
+				continue
+			}
+			resources, err := GetResource(document)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode resource rendered from %s: %w", origin, err)
+			}
+			for _, resource := range resources {
+				rendered = append(rendered, RenderedResource{Resource: resource, Origin: origin})
+			}
+		}
+	}
+	return rendered, nil
+}
+
+// mergeValues merges the values of a file into the values of the previous files, the last file wins
+func mergeValues(dst, src map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(dst))
+	for key, value := range dst {
+		out[key] = value
+	}
+	for key, value := range src {
+		if value, ok := value.(map[string]interface{}); ok {
+			if existing, ok := out[key].(map[string]interface{}); ok {
+				out[key] = mergeValues(existing, value)
+				continue
+			}
+		}
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		assert.NilError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func Test_RenderKustomization(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base/kustomization.yaml": `
+resources:
+- pod.yaml
+`,
+		"base/pod.yaml": `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: nginx
+    image: nginx
+`,
+		"overlays/prod/kustomization.yaml": `
+namespace: prod
+commonLabels:
+  team: blue
+resources:
+- ../../base
+images:
+- name: nginx
+  newTag: "1.23"
+`,
+	})
+	overlay := filepath.Join(dir, "overlays", "prod")
+
+	rendered, err := RenderKustomization(overlay)
+	assert.NilError(t, err)
+	assert.Equal(t, len(rendered), 1)
+	assert.Equal(t, rendered[0].Origin, "kustomize:"+overlay)
+	assert.Equal(t, rendered[0].Resource.GetNamespace(), "prod")
+	assert.Equal(t, rendered[0].Resource.GetLabels()["team"], "blue")
+	containers, _, err := unstructured.NestedSlice(rendered[0].Resource.Object, "spec", "containers")
+	assert.NilError(t, err)
+	assert.Equal(t, containers[0].(map[string]interface{})["image"], "nginx:1.23")
+}
+
+func Test_RenderHelmChart(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"web/Chart.yaml": `
+apiVersion: v2
+name: web
+version: 0.1.0
+`,
+		"web/values.yaml": `
+image:
+  repository: nginx
+  tag: latest
+service:
+  enabled: false
+`,
+		"web/templates/_helpers.tpl": `{{- define "web.name" -}}{{ .Release.Name }}-web{{- end -}}`,
+		"web/templates/NOTES.txt":    `Installed {{ include "web.name" . }}`,
+		"web/templates/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "web.name" . }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: {{ .Values.image.repository }}:{{ .Values.image.tag }}
+`,
+		"web/templates/service.yaml": `
+{{- if .Values.service.enabled }}
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "web.name" . }}
+{{- end }}
+`,
+		"values.yaml": `
+image:
+  tag: "1.23"
+service:
+  enabled: true
+`,
+	})
+
+	rendered, err := RenderHelmChart(filepath.Join(dir, "web"), "prod", filepath.Join(dir, "values.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(rendered), 2)
+	assert.Equal(t, rendered[0].Origin, "helm:web/templates/deployment.yaml")
+	assert.Equal(t, rendered[0].Resource.GetKind(), "Deployment")
+	assert.Equal(t, rendered[0].Resource.GetName(), "release-name-web")
+	assert.Equal(t, rendered[0].Resource.GetNamespace(), "prod")
+	containers, _, err := unstructured.NestedSlice(rendered[0].Resource.Object, "spec", "template", "spec", "containers")
+	assert.NilError(t, err)
+	assert.Equal(t, containers[0].(map[string]interface{})["image"], "nginx:1.23")
+	assert.Equal(t, rendered[1].Origin, "helm:web/templates/service.yaml")
+	assert.Equal(t, rendered[1].Resource.GetKind(), "Service")
+
+	// the chart defaults disable the service
+	rendered, err = RenderHelmChart(filepath.Join(dir, "web"), "")
+	assert.NilError(t, err)
+	assert.Equal(t, len(rendered), 1)
+	assert.Equal(t, rendered[0].Resource.GetNamespace(), "default")
+}
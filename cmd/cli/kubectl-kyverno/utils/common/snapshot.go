@@ -0,0 +1,298 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	openapiv2 "github.com/google/gnostic/openapiv2"
+	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// Snapshot is an offline copy of cluster resources, loaded from a dump
+// produced with `kubectl get -A -o yaml`, that can stand in for a live cluster
+type Snapshot struct {
+	objects []*unstructured.Unstructured
+	client  dclient.Interface
+}
+
+// LoadSnapshot reads a cluster dump from the given path (or URL) and builds a fake client serving its content
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := getFileBytes(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	objects, err := ParseSnapshot(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	return NewSnapshot(objects...)
+}
+
+// ParseSnapshot decodes a multi document yaml dump, expanding `List` documents into their items
+func ParseSnapshot(data []byte) ([]*unstructured.Unstructured, error) {
+	documents, err := yamlutils.SplitDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+	var objects []*unstructured.Unstructured
+	for _, document := range documents {
+		jsonBytes, err := yaml.YAMLToJSON(document)
+		if err != nil {
+			return nil, err
+		}
+		if len(jsonBytes) == 0 || string(jsonBytes) == "null" {
+			continue
+		}
+		obj, err := kubeutils.BytesToUnstructured(jsonBytes)
+		if err != nil {
+			return nil, err
+		}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, err
+			}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+		} else if obj.GetKind() != "" {
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// NewSnapshot creates a snapshot from the given objects
+func NewSnapshot(objects ...*unstructured.Unstructured) (*Snapshot, error) {
+	disco := newSnapshotDiscovery(objects)
+	gvrToListKind := map[schema.GroupVersionResource]string{}
+	for gvr, resource := range disco.resources {
+		gvrToListKind[gvr] = resource.Kind + "List"
+	}
+	runtimeObjects := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		runtimeObjects = append(runtimeObjects, obj.DeepCopy())
+	}
+	client, err := dclient.NewFakeClient(runtime.NewScheme(), gvrToListKind, runtimeObjects...)
+	if err != nil {
+		return nil, err
+	}
+	client.SetDiscovery(disco)
+	return &Snapshot{
+		objects: objects,
+		client:  &snapshotClient{Interface: client},
+	}, nil
+}
+
+// Client returns a dclient.Interface serving the snapshot content
+func (s *Snapshot) Client() dclient.Interface {
+	return s.client
+}
+
+// Objects returns the objects contained in the snapshot
+func (s *Snapshot) Objects() []*unstructured.Unstructured {
+	return s.objects
+}
+
+// NamespaceLabels returns the labels of every namespace in the snapshot, keyed by namespace name
+func (s *Snapshot) NamespaceLabels() map[string]map[string]string {
+	labels := map[string]map[string]string{}
+	for _, obj := range s.objects {
+		if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Namespace" {
+			labels[obj.GetName()] = obj.GetLabels()
+		}
+	}
+	return labels
+}
+
+// ConfigMapResolver returns a resolver looking up configmaps in the snapshot
+func (s *Snapshot) ConfigMapResolver() engineapi.ConfigmapResolver {
+	return snapshotConfigMapResolver{client: s.client}
+}
+
+type snapshotConfigMapResolver struct {
+	client dclient.Interface
+}
+
+func (r snapshotConfigMapResolver) Get(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	obj, err := r.client.GetResource(ctx, "v1", "ConfigMap", namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	var cm corev1.ConfigMap
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), &cm); err != nil {
+		return nil, err
+	}
+	return &cm, nil
+}
+
+// snapshotClient serves raw API paths (as used by apiCall context entries) from the snapshot content
+type snapshotClient struct {
+	dclient.Interface
+}
+
+func (c *snapshotClient) RawAbsPath(ctx context.Context, path string) ([]byte, error) {
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	gv, namespace, resource, name, err := parseAPIPath(u.Path)
+	if err != nil {
+		return nil, err
+	}
+	gvr := gv.WithResource(resource)
+	var ri dynamic.ResourceInterface
+	if namespace != "" {
+		ri = c.GetDynamicInterface().Resource(gvr).Namespace(namespace)
+	} else {
+		ri = c.GetDynamicInterface().Resource(gvr)
+	}
+	if name != "" {
+		obj, err := ri.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return obj.MarshalJSON()
+	}
+	list, err := ri.List(ctx, metav1.ListOptions{LabelSelector: u.Query().Get("labelSelector")})
+	if err != nil {
+		return nil, err
+	}
+	kind, err := c.Discovery().GetGVKFromGVR(gv.String(), resource)
+	if err == nil {
+		list.SetAPIVersion(gv.String())
+		list.SetKind(kind.Kind + "List")
+	}
+	return json.Marshal(list)
+}
+
+// parseAPIPath splits paths like /api/v1/namespaces/{ns}/{resource}/{name} or /apis/{group}/{version}/{resource}
+func parseAPIPath(path string) (gv schema.GroupVersion, namespace, resource, name string, err error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		gv = schema.GroupVersion{Version: parts[1]}
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		gv = schema.GroupVersion{Group: parts[1], Version: parts[2]}
+		parts = parts[3:]
+	default:
+		return gv, "", "", "", fmt.Errorf("unsupported api path %s", path)
+	}
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		namespace = parts[1]
+		parts = parts[2:]
+	}
+	switch len(parts) {
+	case 1:
+		resource = parts[0]
+	case 2:
+		resource, name = parts[0], parts[1]
+	default:
+		return gv, "", "", "", fmt.Errorf("unsupported api path %s", path)
+	}
+	return gv, namespace, resource, name, nil
+}
+
+// snapshotDiscovery resolves kinds and resources from the content of the snapshot
+type snapshotDiscovery struct {
+	resources map[schema.GroupVersionResource]metav1.APIResource
+}
+
+func newSnapshotDiscovery(objects []*unstructured.Unstructured) *snapshotDiscovery {
+	disco := &snapshotDiscovery{
+		resources: map[schema.GroupVersionResource]metav1.APIResource{},
+	}
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		resource, ok := disco.resources[gvr]
+		if !ok {
+			resource = metav1.APIResource{
+				Name:    gvr.Resource,
+				Group:   gvk.Group,
+				Version: gvk.Version,
+				Kind:    gvk.Kind,
+			}
+		}
+		if obj.GetNamespace() != "" {
+			resource.Namespaced = true
+		}
+		disco.resources[gvr] = resource
+	}
+	return disco
+}
+
+func (d *snapshotDiscovery) FindResource(groupVersion string, kind string) (*metav1.APIResource, *metav1.APIResource, schema.GroupVersionResource, error) {
+	for gvr, resource := range d.resources {
+		if resource.Kind != kind {
+			continue
+		}
+		if groupVersion != "" && groupVersion != "*" && groupVersion != gvr.GroupVersion().String() && groupVersion != gvr.Group {
+			continue
+		}
+		resource := resource
+		return &resource, nil, gvr, nil
+	}
+	return nil, nil, schema.GroupVersionResource{}, fmt.Errorf("kind %s not found in snapshot", kind)
+}
+
+func (d *snapshotDiscovery) GetGVRFromKind(kind string) (schema.GroupVersionResource, error) {
+	_, _, gvr, err := d.FindResource("", kind)
+	return gvr, err
+}
+
+func (d *snapshotDiscovery) GetGVRFromAPIVersionKind(groupVersion string, kind string) schema.GroupVersionResource {
+	if _, _, gvr, err := d.FindResource(groupVersion, kind); err == nil {
+		return gvr
+	}
+	gv, err := schema.ParseGroupVersion(groupVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(kind))
+	return gvr
+}
+
+func (d *snapshotDiscovery) GetGVKFromGVR(apiVersion, resourceName string) (schema.GroupVersionKind, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	if resource, ok := d.resources[gv.WithResource(resourceName)]; ok {
+		return gv.WithKind(resource.Kind), nil
+	}
+	return schema.GroupVersionKind{}, fmt.Errorf("resource %s not found in snapshot", resourceName)
+}
+
+func (d *snapshotDiscovery) GetServerVersion() (*version.Info, error) {
+	return nil, nil
+}
+
+func (d *snapshotDiscovery) OpenAPISchema() (*openapiv2.Document, error) {
+	return nil, nil
+}
+
+func (d *snapshotDiscovery) DiscoveryCache() discovery.CachedDiscoveryInterface {
+	return nil
+}
+
+func (d *snapshotDiscovery) DiscoveryInterface() discovery.DiscoveryInterface {
+	return nil
+}
@@ -0,0 +1,87 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+var snapshotDump = []byte(`
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: foo
+    labels:
+      team: blue
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: settings
+    namespace: foo
+  data:
+    allowed: "true"
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+    namespace: foo
+  spec:
+    replicas: 1
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-1
+  namespace: foo
+`)
+
+func Test_Snapshot(t *testing.T) {
+	objects, err := ParseSnapshot(snapshotDump)
+	assert.NilError(t, err)
+	assert.Equal(t, len(objects), 4)
+
+	snapshot, err := NewSnapshot(objects...)
+	assert.NilError(t, err)
+
+	labels := snapshot.NamespaceLabels()
+	assert.Equal(t, labels["foo"]["team"], "blue")
+
+	cm, err := snapshot.ConfigMapResolver().Get(context.TODO(), "foo", "settings")
+	assert.NilError(t, err)
+	assert.Equal(t, cm.Data["allowed"], "true")
+
+	client := snapshot.Client()
+	deployments, err := client.ListResource(context.TODO(), "apps/v1", "Deployment", "", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, len(deployments.Items), 1)
+
+	apiResource, _, _, err := client.Discovery().FindResource("", "Pod")
+	assert.NilError(t, err)
+	assert.Equal(t, apiResource.Name, "pods")
+	assert.Equal(t, apiResource.Namespaced, true)
+
+	data, err := client.RawAbsPath(context.TODO(), "/api/v1/namespaces/foo/configmaps/settings")
+	assert.NilError(t, err)
+	var obj map[string]interface{}
+	assert.NilError(t, json.Unmarshal(data, &obj))
+	assert.Equal(t, obj["kind"], "ConfigMap")
+
+	data, err = client.RawAbsPath(context.TODO(), "/apis/apps/v1/namespaces/foo/deployments")
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(data, &obj))
+	assert.Equal(t, obj["kind"], "DeploymentList")
+	assert.Equal(t, len(obj["items"].([]interface{})), 1)
+
+	data, err = client.RawAbsPath(context.TODO(), "/api/v1/namespaces/foo")
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(data, &obj))
+	assert.Equal(t, obj["kind"], "Namespace")
+
+	_, err = client.RawAbsPath(context.TODO(), "/version")
+	assert.ErrorContains(t, err, "unsupported api path")
+}
@@ -0,0 +1,268 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+)
+
+// APICall is a mocked response for an apiCall context entry,
+// keyed by urlPath or by service url, method and body
+type APICall struct {
+	URLPath  string       `json:"urlPath,omitempty"`
+	Service  *ServiceCall `json:"service,omitempty"`
+	Response interface{}  `json:"response"`
+}
+
+type ServiceCall struct {
+	URL    string                 `json:"url"`
+	Method string                 `json:"method,omitempty"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+// Image is a mocked image served to imageRegistry context entries
+type Image struct {
+	Reference  string      `json:"reference"`
+	Manifest   interface{} `json:"manifest,omitempty"`
+	ConfigData interface{} `json:"configData,omitempty"`
+}
+
+var (
+	apiCalls           []APICall
+	images             []Image
+	mockRegistryClient registryclient.Client
+)
+
+func SetAPICalls(calls ...APICall) {
+	apiCalls = calls
+}
+
+func GetAPICalls() []APICall {
+	return apiCalls
+}
+
+func HasAPICalls() bool {
+	return len(apiCalls) != 0
+}
+
+func SetImages(i ...Image) error {
+	images = i
+	mockRegistryClient = nil
+	if len(images) == 0 {
+		return nil
+	}
+	transport, err := newRegistryTransport(images)
+	if err != nil {
+		return err
+	}
+	mockRegistryClient, err = registryclient.New(registryclient.WithLocalKeychain(), registryclient.WithTransport(transport))
+	return err
+}
+
+func GetImages() []Image {
+	return images
+}
+
+// GetClient wraps the given client so that mocked urlPath api calls are served from the store
+func GetClient(client dclient.Interface) dclient.Interface {
+	for _, call := range apiCalls {
+		if call.URLPath != "" {
+			return &mockClient{Interface: client}
+		}
+	}
+	return client
+}
+
+// GetServiceTransport returns a transport serving mocked service api calls, or nil if there are none
+func GetServiceTransport() http.RoundTripper {
+	for _, call := range apiCalls {
+		if call.Service != nil {
+			return serviceTransport{}
+		}
+	}
+	return nil
+}
+
+type mockClient struct {
+	dclient.Interface
+}
+
+func (c *mockClient) RawAbsPath(ctx context.Context, path string) ([]byte, error) {
+	for _, call := range apiCalls {
+		if call.URLPath == path {
+			return json.Marshal(call.Response)
+		}
+	}
+	if c.Interface != nil && IsApiCallAllowed() {
+		return c.Interface.RawAbsPath(ctx, path)
+	}
+	return nil, fmt.Errorf("no mocked response for api call %s", path)
+}
+
+type serviceTransport struct{}
+
+func (serviceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var data map[string]interface{}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) != 0 {
+			if err := json.Unmarshal(body, &data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, call := range apiCalls {
+		if call.Service == nil || call.Service.URL != req.URL.String() {
+			continue
+		}
+		if call.Service.Method != "" && !strings.EqualFold(call.Service.Method, req.Method) {
+			continue
+		}
+		if call.Service.Data != nil && !reflect.DeepEqual(call.Service.Data, data) {
+			continue
+		}
+		body, err := json.Marshal(call.Response)
+		if err != nil {
+			return nil, err
+		}
+		return newResponse(req, http.StatusOK, "application/json", body), nil
+	}
+	return nil, fmt.Errorf("no mocked response for service call %s %s", req.Method, req.URL)
+}
+
+const (
+	defaultManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	defaultConfigMediaType   = "application/vnd.docker.container.image.v1+json"
+)
+
+type registryImage struct {
+	registry     string
+	repo         string
+	tag          string
+	mediaType    string
+	manifest     []byte
+	digest       string
+	config       []byte
+	configDigest string
+}
+
+// registryTransport implements the subset of the registry API needed to fetch manifests and configs
+type registryTransport struct {
+	images []registryImage
+}
+
+func newRegistryTransport(images []Image) (*registryTransport, error) {
+	t := &registryTransport{}
+	for _, image := range images {
+		ref, err := name.ParseReference(image.Reference)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mocked image reference %s: %w", image.Reference, err)
+		}
+		configData := image.ConfigData
+		if configData == nil {
+			configData = map[string]interface{}{}
+		}
+		config, err := json.Marshal(configData)
+		if err != nil {
+			return nil, err
+		}
+		configDigest := digestOf(config)
+		manifest := map[string]interface{}{}
+		if m, ok := image.Manifest.(map[string]interface{}); ok {
+			for k, v := range m {
+				manifest[k] = v
+			}
+		} else if image.Manifest != nil {
+			return nil, fmt.Errorf("mocked manifest for image %s must be an object", image.Reference)
+		}
+		if _, ok := manifest["schemaVersion"]; !ok {
+			manifest["schemaVersion"] = 2
+		}
+		if _, ok := manifest["mediaType"]; !ok {
+			manifest["mediaType"] = defaultManifestMediaType
+		}
+		if _, ok := manifest["layers"]; !ok {
+			manifest["layers"] = []interface{}{}
+		}
+		// the config descriptor must match the served config blob
+		configDescriptor := map[string]interface{}{"mediaType": defaultConfigMediaType}
+		if c, ok := manifest["config"].(map[string]interface{}); ok {
+			for k, v := range c {
+				configDescriptor[k] = v
+			}
+		}
+		configDescriptor["digest"] = configDigest
+		configDescriptor["size"] = len(config)
+		manifest["config"] = configDescriptor
+		manifestBytes, err := json.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+		t.images = append(t.images, registryImage{
+			registry:     ref.Context().RegistryStr(),
+			repo:         ref.Context().RepositoryStr(),
+			tag:          ref.Identifier(),
+			mediaType:    fmt.Sprint(manifest["mediaType"]),
+			manifest:     manifestBytes,
+			digest:       digestOf(manifestBytes),
+			config:       config,
+			configDigest: configDigest,
+		})
+	}
+	return t, nil
+}
+
+func (t *registryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	if path == "" || path == req.URL.Path {
+		return newResponse(req, http.StatusOK, "application/json", []byte("{}")), nil
+	}
+	for _, image := range t.images {
+		if image.registry != req.URL.Host || !strings.HasPrefix(path, image.repo+"/") {
+			continue
+		}
+		switch strings.TrimPrefix(path, image.repo+"/") {
+		case "manifests/" + image.tag, "manifests/" + image.digest:
+			resp := newResponse(req, http.StatusOK, image.mediaType, image.manifest)
+			resp.Header.Set("Docker-Content-Digest", image.digest)
+			return resp, nil
+		case "blobs/" + image.configDigest:
+			return newResponse(req, http.StatusOK, "application/octet-stream", image.config), nil
+		}
+	}
+	body := fmt.Sprintf(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"image %s%s is not mocked"}]}`, req.URL.Host, req.URL.Path)
+	return newResponse(req, http.StatusNotFound, "application/json", []byte(body)), nil
+}
+
+func digestOf(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+func newResponse(req *http.Request, status int, contentType string, body []byte) *http.Response {
+	resp := &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Header:        http.Header{},
+		Request:       req,
+		ContentLength: int64(len(body)),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+	}
+	resp.Header.Set("Content-Type", contentType)
+	if req.Method == http.MethodHead {
+		resp.Body = io.NopCloser(bytes.NewReader(nil))
+	}
+	return resp
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func Test_MockedImages(t *testing.T) {
+	assert.NilError(t, SetImages(Image{
+		Reference: "ghcr.io/kyverno/test:v1",
+		ConfigData: map[string]interface{}{
+			"config": map[string]interface{}{"User": "nobody"},
+		},
+	}))
+	defer func() { assert.NilError(t, SetImages()) }()
+	assert.Assert(t, GetRegistryAccess())
+
+	desc, err := GetRegistryClient().FetchImageDescriptor(context.TODO(), "ghcr.io/kyverno/test:v1")
+	assert.NilError(t, err)
+	image, err := desc.Image()
+	assert.NilError(t, err)
+	config, err := image.ConfigFile()
+	assert.NilError(t, err)
+	assert.Equal(t, config.Config.User, "nobody")
+
+	_, err = GetRegistryClient().FetchImageDescriptor(context.TODO(), "ghcr.io/kyverno/other:v1")
+	assert.ErrorContains(t, err, "failed to fetch image reference")
+}
+
+func Test_MockedAPICalls(t *testing.T) {
+	SetAPICalls(
+		APICall{URLPath: "/api/v1/namespaces/foo", Response: map[string]interface{}{"kind": "Namespace"}},
+		APICall{
+			Service:  &ServiceCall{URL: "https://svc.ns/check", Method: "POST", Data: map[string]interface{}{"image": "nginx"}},
+			Response: map[string]interface{}{"allowed": true},
+		},
+	)
+	defer SetAPICalls()
+	assert.Assert(t, HasAPICalls())
+
+	data, err := GetClient(nil).RawAbsPath(context.TODO(), "/api/v1/namespaces/foo")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"kind":"Namespace"}`)
+	_, err = GetClient(nil).RawAbsPath(context.TODO(), "/api/v1/namespaces/bar")
+	assert.ErrorContains(t, err, "no mocked response")
+
+	client := &http.Client{Transport: GetServiceTransport()}
+	body, _ := json.Marshal(map[string]interface{}{"image": "nginx"})
+	resp, err := client.Post("https://svc.ns/check", "application/json", bytes.NewReader(body))
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	data, err = io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"allowed":true}`)
+
+	body, _ = json.Marshal(map[string]interface{}{"image": "busybox"})
+	_, err = client.Post("https://svc.ns/check", "application/json", bytes.NewReader(body))
+	assert.ErrorContains(t, err, "no mocked response")
+}
@@ -57,10 +57,13 @@ func SetRegistryAccess(access bool) {
 }
 
 func GetRegistryAccess() bool {
-	return registryClient != nil
+	return registryClient != nil || mockRegistryClient != nil
 }
 
 func GetRegistryClient() registryclient.Client {
+	if mockRegistryClient != nil {
+		return mockRegistryClient
+	}
 	return registryClient
 }
 
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/kyverno/kyverno/pkg/webhooks/handlers"
+)
+
+// AddDumpFlags registers the flags configuring the admission payloads dumped with the dumpPayload flag
+func AddDumpFlags(flagset *flag.FlagSet) *handlers.DumpOptions {
+	var options handlers.DumpOptions
+	flagset.Func("dumpPayloadRedactedPaths", "Comma separated list of dot separated paths redacted from dumped admission requests, e.g. 'userInfo.extra,object.spec.template.spec.containers.*.env'.", appendList(&options.RedactedPaths))
+	flagset.Float64Var(&options.SampleRatio, "dumpPayloadSampleRatio", 1, "Ratio of admission requests being dumped (between 0 and 1), zero disables dumps.")
+	flagset.Func("dumpPayloadKinds", "Comma separated list of kinds for which admission requests are dumped, wildcards are supported. All kinds if empty.", appendList(&options.Kinds))
+	flagset.Func("dumpPayloadNamespaces", "Comma separated list of namespaces for which admission requests are dumped, wildcards are supported. All namespaces if empty.", appendList(&options.Namespaces))
+	return &options
+}
+
+func appendList(list *[]string) func(string) error {
+	return func(value string) error {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*list = append(*list, item)
+			}
+		}
+		return nil
+	}
+}
@@ -18,6 +18,7 @@ var (
 	tracingAddress string
 	tracingPort    string
 	tracingCreds   string
+	tracingSample  float64
 	// metrics
 	otel                 string
 	otelCollector        string
@@ -47,6 +48,7 @@ func initTracingFlags() {
 	flag.StringVar(&tracingPort, "tracingPort", "4317", "Tracing receiver port, defaults to '4317'.")
 	flag.StringVar(&tracingAddress, "tracingAddress", "", "Tracing receiver address, defaults to ''.")
 	flag.StringVar(&tracingCreds, "tracingCreds", "", "Set this flag to the CA secret containing the certificate which is used by our Opentelemetry Tracing Client. If empty string is set, means an insecure connection will be used")
+	flag.Float64Var(&tracingSample, "tracingSampleRatio", 1, "Ratio of traces sampled when no parent span decided it (between 0 and 1), defaults to 1.")
 }
 
 func initMetricsFlags() {
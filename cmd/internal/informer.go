@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 )
 
@@ -38,6 +39,23 @@ func CheckCacheSync[T comparable](status map[T]bool) bool {
 	return ret
 }
 
+// CheckInformersSynced returns a readiness check failing until the informers caches are synced
+func CheckInformersSynced(informers ...informer) func() error {
+	return func() error {
+		// with a closed channel the current sync state is returned without waiting
+		stopCh := make(chan struct{})
+		close(stopCh)
+		for i := range informers {
+			for informerType, synced := range informers[i].WaitForCacheSync(stopCh) {
+				if !synced {
+					return fmt.Errorf("informer cache not synced: %s", informerType)
+				}
+			}
+		}
+		return nil
+	}
+}
+
 func StartInformersAndWaitForCacheSync(ctx context.Context, informers ...informer) bool {
 	StartInformers(ctx, informers...)
 	return WaitForCacheSync(ctx, informers...)
@@ -10,7 +10,7 @@ import (
 )
 
 func SetupTracing(logger logr.Logger, name string, kubeClient kubernetes.Interface) context.CancelFunc {
-	logger = logger.WithName("tracing").WithValues("enabled", tracingEnabled, "name", name, "address", tracingAddress, "port", tracingPort, "creds", tracingCreds)
+	logger = logger.WithName("tracing").WithValues("enabled", tracingEnabled, "name", name, "address", tracingAddress, "port", tracingPort, "creds", tracingCreds, "sampleRatio", tracingSample)
 	if tracingEnabled {
 		logger.Info("setup tracing...")
 		shutdown, err := tracing.NewTraceConfig(
@@ -18,6 +18,7 @@ func SetupTracing(logger logr.Logger, name string, kubeClient kubernetes.Interfa
 			name,
 			net.JoinHostPort(tracingAddress, tracingPort),
 			tracingCreds,
+			tracingSample,
 			kubeClient,
 		)
 		checkError(logger, err, "failed to setup tracing")
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync"
@@ -23,6 +24,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/controllers/certmanager"
 	configcontroller "github.com/kyverno/kyverno/pkg/controllers/config"
 	genericwebhookcontroller "github.com/kyverno/kyverno/pkg/controllers/generic/webhook"
+	idempotencycontroller "github.com/kyverno/kyverno/pkg/controllers/idempotency"
 	policymetricscontroller "github.com/kyverno/kyverno/pkg/controllers/metrics/policy"
 	openapicontroller "github.com/kyverno/kyverno/pkg/controllers/openapi"
 	policycachecontroller "github.com/kyverno/kyverno/pkg/controllers/policycache"
@@ -41,12 +43,15 @@ import (
 	"github.com/kyverno/kyverno/pkg/tls"
 	"github.com/kyverno/kyverno/pkg/toggle"
 	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	osutils "github.com/kyverno/kyverno/pkg/utils/os"
 	runtimeutils "github.com/kyverno/kyverno/pkg/utils/runtime"
 	"github.com/kyverno/kyverno/pkg/validation/exception"
 	"github.com/kyverno/kyverno/pkg/webhooks"
 	webhooksexception "github.com/kyverno/kyverno/pkg/webhooks/exception"
 	webhookspolicy "github.com/kyverno/kyverno/pkg/webhooks/policy"
 	webhooksresource "github.com/kyverno/kyverno/pkg/webhooks/resource"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/decisionlog"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/responsecache"
 	webhookgenerate "github.com/kyverno/kyverno/pkg/webhooks/updaterequest"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -61,11 +66,12 @@ const (
 	exceptionWebhookControllerName = "exception-webhook-controller"
 )
 
-func setupRegistryClient(ctx context.Context, logger logr.Logger, lister corev1listers.SecretNamespaceLister, imagePullSecrets string, allowInsecureRegistry bool) (registryclient.Client, error) {
+func setupRegistryClient(ctx context.Context, logger logr.Logger, lister corev1listers.SecretNamespaceLister, imagePullSecrets string, allowInsecureRegistry bool, metricsConfig config.MetricsConfiguration) (registryclient.Client, error) {
 	logger = logger.WithName("registry-client")
 	logger.Info("setup registry client...", "secrets", imagePullSecrets, "insecure", allowInsecureRegistry)
 	registryOptions := []registryclient.Option{
 		registryclient.WithTracing(),
+		registryclient.WithMetrics(metricsConfig),
 	}
 	secrets := strings.Split(imagePullSecrets, ",")
 	if imagePullSecrets != "" && len(secrets) > 0 {
@@ -85,6 +91,54 @@ func setupCosign(logger logr.Logger, imageSignatureRepository string) {
 	}
 }
 
+func setupDecisionLog(logger logr.Logger, path string, maxSize int, maxBackups int, redactedFields string) decisionlog.Logger {
+	if path == "" {
+		return nil
+	}
+	logger = logger.WithName("decision-log")
+	logger.Info("setup decision log...", "path", path, "maxSize", maxSize, "maxBackups", maxBackups, "redactedFields", redactedFields)
+	var writer io.Writer = os.Stdout
+	if path != "stdout" {
+		writer = osutils.NewRotatingFile(path, int64(maxSize)*1024*1024, maxBackups)
+	}
+	var fields []string
+	for _, field := range strings.Split(redactedFields, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return decisionlog.NewLogger(logger, writer, fields...)
+}
+
+func setupResponseCache(
+	logger logr.Logger,
+	size int,
+	ttl time.Duration,
+	configuration config.Configuration,
+	exceptions engineapi.PolicyExceptionSelector,
+) responsecache.Cache {
+	logger = logger.WithName("response-cache")
+	logger.Info("setup admission response cache...", "size", size, "ttl", ttl)
+	if ttl <= 0 || size <= 0 {
+		return nil
+	}
+	return responsecache.NewCache(logger, size, ttl, configuration, exceptions)
+}
+
+func setupReadinessChecks(
+	runtime runtimeutils.Runtime,
+	kubeInformer kubeinformers.SharedInformerFactory,
+	kubeKyvernoInformer kubeinformers.SharedInformerFactory,
+	kyvernoInformer kyvernoinformer.SharedInformerFactory,
+	cacheInformer kubeinformers.SharedInformerFactory,
+) {
+	mwcLister := kubeInformer.Admissionregistration().V1().MutatingWebhookConfigurations().Lister()
+	vwcLister := kubeInformer.Admissionregistration().V1().ValidatingWebhookConfigurations().Lister()
+	runtime.AddReadinessCheck("informers", internal.CheckInformersSynced(kubeInformer, kubeKyvernoInformer, kyvernoInformer, cacheInformer))
+	// resource webhook configurations are registered by the webhook controller running in the leader
+	runtime.AddReadinessCheck("webhooks", webhookcontroller.CheckResourceWebhooks(mwcLister, vwcLister))
+}
+
 func showWarnings(logger logr.Logger) {
 	logger = logger.WithName("warnings")
 	// log if `forceFailurePolicyIgnore` flag has been set or not
@@ -111,6 +165,7 @@ func createNonLeaderControllers(
 	configuration config.Configuration,
 	policyCache policycache.Cache,
 	manager openapi.Manager,
+	runtime runtimeutils.Runtime,
 ) ([]internal.Controller, func() error) {
 	policyCacheController := policycachecontroller.NewController(
 		dynamicClient,
@@ -118,13 +173,16 @@ func createNonLeaderControllers(
 		kyvernoInformer.Kyverno().V1().ClusterPolicies(),
 		kyvernoInformer.Kyverno().V1().Policies(),
 	)
+	runtime.AddReadinessCheck("policycache", policyCacheController.CheckReady)
 	openApiController := openapicontroller.NewController(
 		dynamicClient,
 		manager,
 	)
 	configurationController := configcontroller.NewController(
 		configuration,
+		kyvernoClient,
 		kubeKyvernoInformer.Core().V1().ConfigMaps(),
+		kyvernoInformer.Kyverno().V2alpha1().KyvernoConfigurations(),
 	)
 	return []internal.Controller{
 			internal.NewController(policycachecontroller.ControllerName, policyCacheController, policycachecontroller.Workers),
@@ -141,6 +199,8 @@ func createrLeaderControllers(
 	serverIP string,
 	webhookTimeout int,
 	autoUpdateWebhooks bool,
+	perPolicyWebhooks bool,
+	webhookMatchConditions bool,
 	kubeInformer kubeinformers.SharedInformerFactory,
 	kubeKyvernoInformer kubeinformers.SharedInformerFactory,
 	kyvernoInformer kyvernoinformer.SharedInformerFactory,
@@ -167,6 +227,7 @@ func createrLeaderControllers(
 		kyvernoInformer.Kyverno().V1().Policies(),
 		kubeKyvernoInformer.Core().V1().Secrets(),
 		kubeKyvernoInformer.Core().V1().ConfigMaps(),
+		kyvernoInformer.Kyverno().V2alpha1().KyvernoConfigurations(),
 		kubeKyvernoInformer.Coordination().V1().Leases(),
 		kubeInformer.Rbac().V1().ClusterRoles(),
 		serverIP,
@@ -174,6 +235,8 @@ func createrLeaderControllers(
 		servicePort,
 		autoUpdateWebhooks,
 		admissionReports,
+		perPolicyWebhooks,
+		webhookMatchConditions,
 		runtime,
 	)
 	exceptionWebhookController := genericwebhookcontroller.NewController(
@@ -216,13 +279,25 @@ func main() {
 		webhookTimeout             int
 		genWorkers                 int
 		maxQueuedEvents            int
+		eventsRateLimitQPS         float64
+		eventsRateLimitBurst       int
+		eventsAggregationWindow    time.Duration
 		autoUpdateWebhooks         bool
+		perPolicyWebhooks          bool
+		webhookMatchConditions     bool
 		imagePullSecrets           string
 		imageSignatureRepository   string
 		allowInsecureRegistry      bool
 		webhookRegistrationTimeout time.Duration
 		admissionReports           bool
 		dumpPayload                bool
+		decisionLog                string
+		decisionLogMaxSize         int
+		decisionLogMaxBackups      int
+		decisionLogRedactedFields  string
+		responseCacheSize          int
+		responseCacheTTL           time.Duration
+		verifyMutationIdempotency  bool
 		leaderElectionRetryPeriod  time.Duration
 		enablePolicyException      bool
 		exceptionNamespace         string
@@ -230,14 +305,27 @@ func main() {
 	)
 	flagset := flag.NewFlagSet("kyverno", flag.ExitOnError)
 	flagset.BoolVar(&dumpPayload, "dumpPayload", false, "Set this flag to activate/deactivate debug mode.")
+	dumpOptions := internal.AddDumpFlags(flagset)
+	flagset.StringVar(&decisionLog, "decisionLog", "", "Path of a file receiving admission decisions as JSON lines, 'stdout' writes them to the standard output, disabled if empty.")
+	flagset.IntVar(&decisionLogMaxSize, "decisionLogMaxSize", 100, "Size in megabytes after which the decision log file is rotated.")
+	flagset.IntVar(&decisionLogMaxBackups, "decisionLogMaxBackups", 3, "Number of rotated decision log files to keep.")
+	flagset.StringVar(&decisionLogRedactedFields, "decisionLogRedactedFields", "", "Comma separated list of dot separated decision log fields to redact, e.g. 'user.extra,patches.*.value'.")
+	flagset.IntVar(&responseCacheSize, "admissionResponseCacheSize", 10000, "Maximum number of admission responses kept in the response cache.")
+	flagset.DurationVar(&responseCacheTTL, "admissionResponseCacheTTL", 0, "Time during which the response of an admission request is reused for identical requests, caching is disabled if zero.")
+	flagset.BoolVar(&verifyMutationIdempotency, "verifyMutationIdempotency", false, "Set this flag to apply mutate policies again to the mutated resource and report rules producing further patches.")
 	flagset.IntVar(&webhookTimeout, "webhookTimeout", webhookcontroller.DefaultWebhookTimeout, "Timeout for webhook configurations.")
 	flagset.IntVar(&genWorkers, "genWorkers", 10, "Workers for generate controller.")
 	flagset.IntVar(&maxQueuedEvents, "maxQueuedEvents", 1000, "Maximum events to be queued.")
+	flagset.Float64Var(&eventsRateLimitQPS, "eventsRateLimitQPS", 0, "Maximum events emitted per second for each event reason, rate limiting is disabled if zero.")
+	flagset.IntVar(&eventsRateLimitBurst, "eventsRateLimitBurst", 50, "Maximum burst of events emitted for each event reason.")
+	flagset.DurationVar(&eventsAggregationWindow, "eventsAggregationWindow", 0, "Time window during which identical events are aggregated into a single event, aggregation is disabled if zero.")
 	flagset.StringVar(&serverIP, "serverIP", "", "IP address where Kyverno controller runs. Only required if out-of-cluster.")
 	flagset.StringVar(&imagePullSecrets, "imagePullSecrets", "", "Secret resource names for image registry access credentials.")
 	flagset.StringVar(&imageSignatureRepository, "imageSignatureRepository", "", "Alternate repository for image signatures. Can be overridden per rule via `verifyImages.Repository`.")
 	flagset.BoolVar(&allowInsecureRegistry, "allowInsecureRegistry", false, "Whether to allow insecure connections to registries. Don't use this for anything but testing.")
 	flagset.BoolVar(&autoUpdateWebhooks, "autoUpdateWebhooks", true, "Set this flag to 'false' to disable auto-configuration of the webhook.")
+	flagset.BoolVar(&perPolicyWebhooks, "perPolicyWebhooks", false, "Set this flag to 'true' to build a dedicated resource webhook per policy (or group of policies sharing the same scope) with namespace and object selectors derived from the policy match and exclude blocks.")
+	flagset.BoolVar(&webhookMatchConditions, "webhookMatchConditions", false, "Set this flag to 'true' to translate simple policy preconditions and exclude blocks into resource webhooks match conditions (requires Kubernetes 1.28 or the AdmissionWebhookMatchConditions feature gate).")
 	flagset.DurationVar(&webhookRegistrationTimeout, "webhookRegistrationTimeout", 120*time.Second, "Timeout for webhook registration, e.g., 30s, 1m, 5m.")
 	flagset.Func(toggle.ProtectManagedResourcesFlagName, toggle.ProtectManagedResourcesDescription, toggle.ProtectManagedResources.Parse)
 	flagset.Func(toggle.ForceFailurePolicyIgnoreFlagName, toggle.ForceFailurePolicyIgnoreDescription, toggle.ForceFailurePolicyIgnore.Parse)
@@ -295,7 +383,7 @@ func main() {
 	}
 	secretLister := kubeKyvernoInformer.Core().V1().Secrets().Lister().Secrets(config.KyvernoNamespace())
 	// setup registry client
-	rclient, err := setupRegistryClient(signalCtx, logger, secretLister, imagePullSecrets, allowInsecureRegistry)
+	rclient, err := setupRegistryClient(signalCtx, logger, secretLister, imagePullSecrets, allowInsecureRegistry, metricsConfig.Config())
 	if err != nil {
 		logger.Error(err, "failed to setup registry client")
 		os.Exit(1)
@@ -341,6 +429,11 @@ func main() {
 		kyvernoInformer.Kyverno().V1().ClusterPolicies(),
 		kyvernoInformer.Kyverno().V1().Policies(),
 		maxQueuedEvents,
+		event.Options{
+			RateLimitQPS:      eventsRateLimitQPS,
+			RateLimitBurst:    eventsRateLimitBurst,
+			AggregationWindow: eventsAggregationWindow,
+		},
 		logging.WithName("EventGenerator"),
 	)
 	// this controller only subscribe to events, nothing is returned...
@@ -366,9 +459,10 @@ func main() {
 	}
 	eng := engine.NewEngine(
 		configuration,
+		metricsConfig.Config(),
 		dClient,
 		rclient,
-		engine.LegacyContextLoaderFactory(configMapResolver),
+		engine.LegacyContextLoaderFactory(configMapResolver, metricsConfig.Config()),
 		exceptionsLister,
 	)
 	// create non leader controllers
@@ -383,7 +477,19 @@ func main() {
 		configuration,
 		policyCache,
 		openApiManager,
+		runtime,
 	)
+	// non idempotent mutate rules are reported in the policy status through a rate limited queue
+	var idempotencyController idempotencycontroller.Controller
+	if verifyMutationIdempotency {
+		idempotencyController = idempotencycontroller.NewController(
+			kyvernoClient,
+			kyvernoInformer.Kyverno().V1().ClusterPolicies(),
+			kyvernoInformer.Kyverno().V1().Policies(),
+		)
+		nonLeaderControllers = append(nonLeaderControllers, internal.NewController(idempotencycontroller.ControllerName, idempotencyController, idempotencycontroller.Workers))
+	}
+	setupReadinessChecks(runtime, kubeInformer, kubeKyvernoInformer, kyvernoInformer, cacheInformer)
 	// start informers and wait for cache sync
 	if !internal.StartInformersAndWaitForCacheSync(signalCtx, kyvernoInformer, kubeInformer, kubeKyvernoInformer, cacheInformer) {
 		logger.Error(errors.New("failed to wait for cache sync"), "failed to wait for cache sync")
@@ -418,6 +524,8 @@ func main() {
 				serverIP,
 				webhookTimeout,
 				autoUpdateWebhooks,
+				perPolicyWebhooks,
+				webhookMatchConditions,
 				kubeInformer,
 				kubeKyvernoInformer,
 				kyvernoInformer,
@@ -479,6 +587,8 @@ func main() {
 	policyHandlers := webhookspolicy.NewHandlers(
 		dClient,
 		openApiManager,
+		kyvernoInformer.Kyverno().V1().ClusterPolicies().Lister(),
+		kyvernoInformer.Kyverno().V1().Policies().Lister(),
 	)
 	resourceHandlers := webhooksresource.NewHandlers(
 		eng,
@@ -496,6 +606,10 @@ func main() {
 		eventGenerator,
 		openApiManager,
 		admissionReports,
+		setupDecisionLog(logger, decisionLog, decisionLogMaxSize, decisionLogMaxBackups, decisionLogRedactedFields),
+		setupResponseCache(logger, responseCacheSize, responseCacheTTL, configuration, exceptionsLister),
+		verifyMutationIdempotency,
+		idempotencyController,
 	)
 	exceptionHandlers := webhooksexception.NewHandlers(exception.ValidationOptions{
 		Enabled:   enablePolicyException,
@@ -506,9 +620,11 @@ func main() {
 		resourceHandlers,
 		exceptionHandlers,
 		configuration,
+		kubeInformer.Core().V1().Namespaces().Lister(),
 		metricsConfig,
 		webhooks.DebugModeOptions{
 			DumpPayload: dumpPayload,
+			Dump:        *dumpOptions,
 		},
 		func() ([]byte, []byte, error) {
 			secret, err := secretLister.Get(tls.GenerateTLSPairSecretName())
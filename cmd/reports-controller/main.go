@@ -19,10 +19,12 @@ import (
 	kyvernoclient "github.com/kyverno/kyverno/pkg/clients/kyverno"
 	metadataclient "github.com/kyverno/kyverno/pkg/clients/metadata"
 	"github.com/kyverno/kyverno/pkg/config"
+	reportmetricscontroller "github.com/kyverno/kyverno/pkg/controllers/metrics/report"
 	admissionreportcontroller "github.com/kyverno/kyverno/pkg/controllers/report/admission"
 	aggregatereportcontroller "github.com/kyverno/kyverno/pkg/controllers/report/aggregate"
 	backgroundscancontroller "github.com/kyverno/kyverno/pkg/controllers/report/background"
 	resourcereportcontroller "github.com/kyverno/kyverno/pkg/controllers/report/resource"
+	reportutils "github.com/kyverno/kyverno/pkg/controllers/report/utils"
 	"github.com/kyverno/kyverno/pkg/cosign"
 	"github.com/kyverno/kyverno/pkg/engine"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
@@ -32,6 +34,8 @@ import (
 	"github.com/kyverno/kyverno/pkg/logging"
 	"github.com/kyverno/kyverno/pkg/metrics"
 	"github.com/kyverno/kyverno/pkg/registryclient"
+	"github.com/kyverno/kyverno/pkg/reportsink"
+	configutils "github.com/kyverno/kyverno/pkg/utils/config"
 	kubeinformers "k8s.io/client-go/informers"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	metadatainformers "k8s.io/client-go/metadata/metadatainformer"
@@ -42,11 +46,12 @@ const (
 	resyncPeriod = 15 * time.Minute
 )
 
-func setupRegistryClient(ctx context.Context, logger logr.Logger, lister corev1listers.SecretNamespaceLister, imagePullSecrets string, allowInsecureRegistry bool) (registryclient.Client, error) {
+func setupRegistryClient(ctx context.Context, logger logr.Logger, lister corev1listers.SecretNamespaceLister, imagePullSecrets string, allowInsecureRegistry bool, metricsConfig config.MetricsConfiguration) (registryclient.Client, error) {
 	logger = logger.WithName("registry-client")
 	logger.Info("setup registry client...", "secrets", imagePullSecrets, "insecure", allowInsecureRegistry)
 	registryOptions := []registryclient.Option{
 		registryclient.WithTracing(),
+		registryclient.WithMetrics(metricsConfig),
 	}
 	secrets := strings.Split(imagePullSecrets, ",")
 	if imagePullSecrets != "" && len(secrets) > 0 {
@@ -66,11 +71,47 @@ func setupCosign(logger logr.Logger, imageSignatureRepository string) {
 	}
 }
 
+func setupReportSink(
+	logger logr.Logger,
+	webhookURL string,
+	webhookHeaders string,
+	cloudEventsURL string,
+	cloudEventsSource string,
+	file string,
+	fileMaxSize int,
+	fileMaxBackups int,
+) reportsink.Dispatcher {
+	logger = logger.WithName("report-sink")
+	var sinks []reportsink.Sink
+	if webhookURL != "" {
+		headers := map[string]string{}
+		for _, header := range strings.Split(webhookHeaders, ",") {
+			if key, value, ok := strings.Cut(header, "="); ok {
+				headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+		sinks = append(sinks, reportsink.NewWebhookSink(webhookURL, headers, nil))
+	}
+	if cloudEventsURL != "" {
+		sinks = append(sinks, reportsink.NewCloudEventsSink(cloudEventsURL, cloudEventsSource, nil))
+	}
+	if file != "" {
+		sinks = append(sinks, reportsink.NewFileSink(file, int64(fileMaxSize)*1024*1024, fileMaxBackups))
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+	logger.Info("setup report sinks...", "webhook", webhookURL, "cloudevents", cloudEventsURL, "file", file)
+	return reportsink.NewDispatcher(logger, reportsink.DefaultBackoff, 100, sinks...)
+}
+
 func createReportControllers(
 	eng engineapi.Engine,
 	backgroundScan bool,
 	admissionReports bool,
 	reportsChunkSize int,
+	reportsPerResource bool,
+	reportsRollUp bool,
 	backgroundScanWorkers int,
 	client dclient.Interface,
 	kyvernoClient versioned.Interface,
@@ -82,6 +123,7 @@ func createReportControllers(
 	backgroundScanInterval time.Duration,
 	configuration config.Configuration,
 	eventGenerator event.Interface,
+	reportSink reportsink.Interface,
 ) ([]internal.Controller, func(context.Context) error) {
 	var ctrls []internal.Controller
 	var warmups []func(context.Context) error
@@ -100,6 +142,10 @@ func createReportControllers(
 			resourceReportController,
 			resourcereportcontroller.Workers,
 		))
+		var owners reportutils.OwnerResolver
+		if reportsRollUp {
+			owners = reportutils.NewOwnerResolver(logging.WithName("OwnerResolver"), client)
+		}
 		ctrls = append(ctrls, internal.NewController(
 			aggregatereportcontroller.ControllerName,
 			aggregatereportcontroller.NewController(
@@ -109,6 +155,9 @@ func createReportControllers(
 				kyvernoV1.ClusterPolicies(),
 				resourceReportController,
 				reportsChunkSize,
+				reportsPerResource,
+				owners,
+				reportSink,
 			),
 			aggregatereportcontroller.Workers,
 		))
@@ -159,6 +208,8 @@ func createrLeaderControllers(
 	backgroundScan bool,
 	admissionReports bool,
 	reportsChunkSize int,
+	reportsPerResource bool,
+	reportsRollUp bool,
 	backgroundScanWorkers int,
 	kubeInformer kubeinformers.SharedInformerFactory,
 	kyvernoInformer kyvernoinformer.SharedInformerFactory,
@@ -170,12 +221,15 @@ func createrLeaderControllers(
 	eventGenerator event.Interface,
 	configMapResolver engineapi.ConfigmapResolver,
 	backgroundScanInterval time.Duration,
+	reportSink reportsink.Interface,
 ) ([]internal.Controller, func(context.Context) error, error) {
 	reportControllers, warmup := createReportControllers(
 		eng,
 		backgroundScan,
 		admissionReports,
 		reportsChunkSize,
+		reportsPerResource,
+		reportsRollUp,
 		backgroundScanWorkers,
 		dynamicClient,
 		kyvernoClient,
@@ -187,6 +241,7 @@ func createrLeaderControllers(
 		backgroundScanInterval,
 		configuration,
 		eventGenerator,
+		reportSink,
 	)
 	return reportControllers, warmup, nil
 }
@@ -200,11 +255,24 @@ func main() {
 		backgroundScan            bool
 		admissionReports          bool
 		reportsChunkSize          int
+		reportsPerResource        bool
+		reportsRollUp             bool
+		reportMetrics             bool
 		backgroundScanWorkers     int
 		backgroundScanInterval    time.Duration
 		maxQueuedEvents           int
+		eventsRateLimitQPS        float64
+		eventsRateLimitBurst      int
+		eventsAggregationWindow   time.Duration
 		enablePolicyException     bool
 		exceptionNamespace        string
+		reportSinkWebhookURL      string
+		reportSinkWebhookHeaders  string
+		reportSinkCloudEventsURL  string
+		reportSinkCloudEventsSrc  string
+		reportSinkFile            string
+		reportSinkFileMaxSize     int
+		reportSinkFileMaxBackups  int
 	)
 	flagset := flag.NewFlagSet("reports-controller", flag.ExitOnError)
 	flagset.DurationVar(&leaderElectionRetryPeriod, "leaderElectionRetryPeriod", leaderelection.DefaultRetryPeriod, "Configure leader election retry period.")
@@ -214,11 +282,24 @@ func main() {
 	flagset.BoolVar(&backgroundScan, "backgroundScan", true, "Enable or disable backgound scan.")
 	flagset.BoolVar(&admissionReports, "admissionReports", true, "Enable or disable admission reports.")
 	flagset.IntVar(&reportsChunkSize, "reportsChunkSize", 1000, "Max number of results in generated reports, reports will be split accordingly if there are more results to be stored.")
+	flagset.BoolVar(&reportsPerResource, "reportsPerResource", false, "Generate one policy report per resource (owned by the resource) instead of reports per namespace and policy.")
+	flagset.BoolVar(&reportMetrics, "reportMetrics", false, "Export the number of results stored in policy reports as metrics.")
+	flagset.BoolVar(&reportsRollUp, "reportsRollUp", false, "Attribute results of Pods, ReplicaSets and Jobs to their top level Deployment, StatefulSet or CronJob owner and merge identical results.")
 	flagset.IntVar(&backgroundScanWorkers, "backgroundScanWorkers", backgroundscancontroller.Workers, "Configure the number of background scan workers.")
 	flagset.DurationVar(&backgroundScanInterval, "backgroundScanInterval", time.Hour, "Configure background scan interval.")
 	flagset.IntVar(&maxQueuedEvents, "maxQueuedEvents", 1000, "Maximum events to be queued.")
+	flagset.Float64Var(&eventsRateLimitQPS, "eventsRateLimitQPS", 0, "Maximum events emitted per second for each event reason, rate limiting is disabled if zero.")
+	flagset.IntVar(&eventsRateLimitBurst, "eventsRateLimitBurst", 50, "Maximum burst of events emitted for each event reason.")
+	flagset.DurationVar(&eventsAggregationWindow, "eventsAggregationWindow", 0, "Time window during which identical events are aggregated into a single event, aggregation is disabled if zero.")
 	flagset.StringVar(&exceptionNamespace, "exceptionNamespace", "", "Configure the namespace to accept PolicyExceptions.")
 	flagset.BoolVar(&enablePolicyException, "enablePolicyException", false, "Enable PolicyException feature.")
+	flagset.StringVar(&reportSinkWebhookURL, "reportSinkWebhookURL", "", "URL receiving changes of policy results (new and resolved results) as JSON.")
+	flagset.StringVar(&reportSinkWebhookHeaders, "reportSinkWebhookHeaders", "", "Comma separated key=value headers added to the requests sent to the report sink webhook.")
+	flagset.StringVar(&reportSinkCloudEventsURL, "reportSinkCloudEventsURL", "", "URL receiving changes of policy results as cloud events.")
+	flagset.StringVar(&reportSinkCloudEventsSrc, "reportSinkCloudEventsSource", reportsink.DefaultCloudEventSource, "Source attribute of the cloud events sent to the report sink.")
+	flagset.StringVar(&reportSinkFile, "reportSinkFile", "", "Path of a file receiving changes of policy results as JSON lines.")
+	flagset.IntVar(&reportSinkFileMaxSize, "reportSinkFileMaxSize", 100, "Size in megabytes after which the report sink file is rotated.")
+	flagset.IntVar(&reportSinkFileMaxBackups, "reportSinkFileMaxBackups", 3, "Number of rotated report sink files to keep.")
 	// config
 	appConfig := internal.NewConfiguration(
 		internal.WithProfiling(),
@@ -261,7 +342,7 @@ func main() {
 	}
 	secretLister := kubeKyvernoInformer.Core().V1().Secrets().Lister().Secrets(config.KyvernoNamespace())
 	// setup registry client
-	rclient, err := setupRegistryClient(ctx, logger, secretLister, imagePullSecrets, allowInsecureRegistry)
+	rclient, err := setupRegistryClient(ctx, logger, secretLister, imagePullSecrets, allowInsecureRegistry, metricsConfig.Config())
 	if err != nil {
 		logger.Error(err, "failed to setup registry client")
 		os.Exit(1)
@@ -288,13 +369,29 @@ func main() {
 		logger.Error(err, "failed to initialize configuration")
 		os.Exit(1)
 	}
+	if err := configutils.LoadFromClient(ctx, configuration, kyvernoClient, configutils.Reports); err != nil {
+		logger.Error(err, "failed to load kyverno configuration, using the configmap")
+	}
 	eventGenerator := event.NewEventGenerator(
 		dClient,
 		kyvernoInformer.Kyverno().V1().ClusterPolicies(),
 		kyvernoInformer.Kyverno().V1().Policies(),
 		maxQueuedEvents,
+		event.Options{
+			RateLimitQPS:      eventsRateLimitQPS,
+			RateLimitBurst:    eventsRateLimitBurst,
+			AggregationWindow: eventsAggregationWindow,
+		},
 		logging.WithName("EventGenerator"),
 	)
+	if reportMetrics {
+		// this controller only registers a metrics callback, nothing is returned...
+		reportmetricscontroller.NewController(
+			metricsConfig,
+			kyvernoInformer.Wgpolicyk8s().V1alpha2().PolicyReports(),
+			kyvernoInformer.Wgpolicyk8s().V1alpha2().ClusterPolicyReports(),
+		)
+	}
 	var exceptionsLister engineapi.PolicyExceptionSelector
 	if enablePolicyException {
 		lister := kyvernoInformer.Kyverno().V2alpha1().PolicyExceptions().Lister()
@@ -311,11 +408,27 @@ func main() {
 	}
 	// start event generator
 	go eventGenerator.Run(ctx, 3)
+	// setup and start report sinks
+	var reportSink reportsink.Interface
+	if dispatcher := setupReportSink(
+		logger,
+		reportSinkWebhookURL,
+		reportSinkWebhookHeaders,
+		reportSinkCloudEventsURL,
+		reportSinkCloudEventsSrc,
+		reportSinkFile,
+		reportSinkFileMaxSize,
+		reportSinkFileMaxBackups,
+	); dispatcher != nil {
+		go dispatcher.Run(ctx, 1)
+		reportSink = dispatcher
+	}
 	eng := engine.NewEngine(
 		configuration,
+		metricsConfig.Config(),
 		dClient,
 		rclient,
-		engine.LegacyContextLoaderFactory(configMapResolver),
+		engine.LegacyContextLoaderFactory(configMapResolver, metricsConfig.Config()),
 		exceptionsLister,
 	)
 	// setup leader election
@@ -339,6 +452,8 @@ func main() {
 				backgroundScan,
 				admissionReports,
 				reportsChunkSize,
+				reportsPerResource,
+				reportsRollUp,
 				backgroundScanWorkers,
 				kubeInformer,
 				kyvernoInformer,
@@ -350,6 +465,7 @@ func main() {
 				eventGenerator,
 				configMapResolver,
 				backgroundScanInterval,
+				reportSink,
 			)
 			if err != nil {
 				logger.Error(err, "failed to create leader controllers")
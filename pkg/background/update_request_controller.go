@@ -21,6 +21,7 @@ import (
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	"github.com/kyverno/kyverno/pkg/event"
 	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	policyutils "github.com/kyverno/kyverno/pkg/utils/policy"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -34,6 +35,8 @@ import (
 
 const (
 	maxRetries = 10
+	// precedingMutationDelay is the delay before checking again if the preceding mutateExisting update requests completed
+	precedingMutationDelay = time.Second
 )
 
 type Controller interface {
@@ -213,6 +216,18 @@ func (c *controller) syncUpdateRequest(key string) error {
 	}
 	// process pending URs
 	if ur.Status.State == kyvernov1beta1.Pending {
+		// mutateExisting policies are applied to a trigger resource in order
+		if ur.Spec.Type == kyvernov1beta1.Mutate {
+			preceding, err := c.precedingMutation(ur)
+			if err != nil {
+				return err
+			}
+			if preceding != nil {
+				logger.V(4).Info("waiting for the preceding mutateExisting update request", "key", key, "preceding", preceding.GetName())
+				c.queue.AddAfter(key, precedingMutationDelay)
+				return nil
+			}
+		}
 		if err := c.processUR(ur); err != nil {
 			return fmt.Errorf("failed to process UR %s: %v", key, err)
 		}
@@ -222,6 +237,38 @@ func (c *controller) syncUpdateRequest(key string) error {
 	return err
 }
 
+// precedingMutation returns a pending mutateExisting update request of the same trigger resource
+// whose policy mutates resources before the policy of the given update request, if any
+func (c *controller) precedingMutation(ur *kyvernov1beta1.UpdateRequest) (*kyvernov1beta1.UpdateRequest, error) {
+	policy, err := c.getPolicy(ur.Spec.Policy)
+	if err != nil {
+		return nil, err
+	}
+	trigger := common.MutateLabelsSet(ur.Spec.Policy, ur.Spec.Resource)
+	delete(trigger, kyvernov1beta1.URMutatePolicyLabel)
+	urs, err := c.urLister.List(labels.SelectorFromSet(trigger))
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range urs {
+		if other.GetName() == ur.GetName() || other.Spec.Type != kyvernov1beta1.Mutate {
+			continue
+		}
+		if other.Status.State != "" && other.Status.State != kyvernov1beta1.Pending {
+			continue
+		}
+		otherPolicy, err := c.getPolicy(other.Spec.Policy)
+		if err != nil {
+			// update requests of deleted policies are cleaned up
+			continue
+		}
+		if policyutils.Less(otherPolicy, policy) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
 func (c *controller) checkIfCleanupRequired(ur *kyvernov1beta1.UpdateRequest) error {
 	var err error
 	pNamespace, pName, err := cache.SplitMetaNamespaceKey(ur.Spec.Policy)
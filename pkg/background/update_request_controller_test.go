@@ -0,0 +1,73 @@
+package background
+
+import (
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	kyvernov1beta1 "github.com/kyverno/kyverno/api/kyverno/v1beta1"
+	common "github.com/kyverno/kyverno/pkg/background/common"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	kyvernoinformers "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
+	"github.com/kyverno/kyverno/pkg/config"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_precedingMutation(t *testing.T) {
+	factory := kyvernoinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	cpolInformer := factory.Kyverno().V1().ClusterPolicies()
+	urInformer := factory.Kyverno().V1beta1().UpdateRequests()
+	c := controller{
+		cpolLister: cpolInformer.Lister(),
+		polLister:  factory.Kyverno().V1().Policies().Lister(),
+		urLister:   urInformer.Lister().UpdateRequests(config.KyvernoNamespace()),
+	}
+	order := func(order int32) *int32 { return &order }
+	for _, policy := range []*kyvernov1.ClusterPolicy{
+		{ObjectMeta: metav1.ObjectMeta{Name: "inject-sidecar"}, Spec: kyvernov1.Spec{Order: order(1)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "set-resources"}, Spec: kyvernov1.Spec{Order: order(2)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "add-labels"}, Spec: kyvernov1.Spec{Order: order(2)}},
+	} {
+		assert.NilError(t, cpolInformer.Informer().GetIndexer().Add(policy))
+	}
+	trigger := kyvernov1.ResourceSpec{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "test"}
+	other := kyvernov1.ResourceSpec{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "other"}
+	updateRequest := func(name, policy string, resource kyvernov1.ResourceSpec, state kyvernov1beta1.UpdateRequestState) *kyvernov1beta1.UpdateRequest {
+		return &kyvernov1beta1.UpdateRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: config.KyvernoNamespace(),
+				Labels:    common.MutateLabelsSet(policy, resource),
+			},
+			Spec:   kyvernov1beta1.UpdateRequestSpec{Type: kyvernov1beta1.Mutate, Policy: policy, Resource: resource},
+			Status: kyvernov1beta1.UpdateRequestStatus{State: state},
+		}
+	}
+	sidecar := updateRequest("ur-sidecar", "inject-sidecar", trigger, kyvernov1beta1.Pending)
+	labels := updateRequest("ur-labels", "add-labels", trigger, kyvernov1beta1.Pending)
+	resources := updateRequest("ur-resources", "set-resources", trigger, kyvernov1beta1.Pending)
+	otherResources := updateRequest("ur-other-resources", "set-resources", other, kyvernov1beta1.Pending)
+	for _, ur := range []*kyvernov1beta1.UpdateRequest{sidecar, labels, resources, otherResources} {
+		assert.NilError(t, urInformer.Informer().GetIndexer().Add(ur))
+	}
+
+	preceding, err := c.precedingMutation(sidecar)
+	assert.NilError(t, err)
+	assert.Assert(t, preceding == nil)
+	preceding, err = c.precedingMutation(labels)
+	assert.NilError(t, err)
+	assert.Equal(t, preceding.GetName(), "ur-sidecar")
+	// other trigger resources don't wait
+	preceding, err = c.precedingMutation(otherResources)
+	assert.NilError(t, err)
+	assert.Assert(t, preceding == nil)
+
+	// the same order is broken by name
+	assert.NilError(t, urInformer.Informer().GetIndexer().Update(updateRequest("ur-sidecar", "inject-sidecar", trigger, kyvernov1beta1.Completed)))
+	preceding, err = c.precedingMutation(labels)
+	assert.NilError(t, err)
+	assert.Assert(t, preceding == nil)
+	preceding, err = c.precedingMutation(resources)
+	assert.NilError(t, err)
+	assert.Equal(t, preceding.GetName(), "ur-labels")
+}
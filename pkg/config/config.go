@@ -7,7 +7,6 @@ import (
 
 	valid "github.com/asaskevich/govalidator"
 	osutils "github.com/kyverno/kyverno/pkg/utils/os"
-	wildcard "github.com/kyverno/kyverno/pkg/utils/wildcard"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,6 +67,8 @@ const (
 	LivenessServicePath = "/health/liveness"
 	// ReadinessServicePath is the path for check readness health
 	ReadinessServicePath = "/health/readiness"
+	// StatusServicePath is the path for the detailed readiness status of components
+	StatusServicePath = "/health/status"
 	// MetricsPath is the path for exposing metrics
 	MetricsPath = "/metrics"
 )
@@ -95,6 +96,8 @@ var (
 	kyvernoPodName = osutils.GetEnvWithFallback("KYVERNO_POD_NAME", "kyverno")
 	// kyvernoConfigMapName is the Kyverno configmap name
 	kyvernoConfigMapName = osutils.GetEnvWithFallback("INIT_CONFIG", "kyverno")
+	// kyvernoConfigurationName is the Kyverno configuration name
+	kyvernoConfigurationName = osutils.GetEnvWithFallback("KYVERNO_CONFIGURATION", "kyverno")
 	// defaultExcludeGroupRole ...
 	defaultExcludeGroupRole []string = []string{"system:serviceaccounts:kube-system", "system:nodes", "system:kube-scheduler"}
 	// kyvernoDryRunNamespace is the namespace for DryRun option of YAML verification
@@ -129,6 +132,10 @@ func KyvernoConfigMapName() string {
 	return kyvernoConfigMapName
 }
 
+func KyvernoConfigurationName() string {
+	return kyvernoConfigurationName
+}
+
 // Configuration to be used by consumer to check filters
 type Configuration interface {
 	// GetDefaultRegistry return default image registry
@@ -137,6 +144,8 @@ type Configuration interface {
 	GetEnableDefaultRegistryMutation() bool
 	// ToFilter checks if the given resource is set to be filtered in the configuration
 	ToFilter(kind, namespace, name string) bool
+	// ToFilterResource checks if the given resource, and the request changing it, is set to be filtered in the configuration
+	ToFilterResource(resource ResourceInfo) bool
 	// GetExcludeGroupRole return exclude roles
 	GetExcludeGroupRole() []string
 	// GetExcludeUsername return exclude username
@@ -147,8 +156,25 @@ type Configuration interface {
 	FilterNamespaces(namespaces []string) []string
 	// GetWebhooks returns the webhook configs
 	GetWebhooks() []WebhookConfig
+	// GetResourceVersion returns the kind and resource version of the ConfigMap or KyvernoConfiguration loaded last,
+	// empty if the defaults are used
+	GetResourceVersion() string
 	// Load loads configuration from a configmap
 	Load(cm *corev1.ConfigMap)
+	// LoadSettings loads configuration from settings, typically read from a KyvernoConfiguration
+	LoadSettings(settings Settings)
+}
+
+// Settings holds the configuration values declared in a KyvernoConfiguration
+type Settings struct {
+	DefaultRegistry               string
+	EnableDefaultRegistryMutation bool
+	ExcludeGroupRole              []string
+	ExcludeUsername               []string
+	Filters                       []Filter
+	GenerateSuccessEvents         bool
+	Webhooks                      []WebhookConfig
+	ResourceVersion               string
 }
 
 // configuration stores the configuration
@@ -157,10 +183,11 @@ type configuration struct {
 	enableDefaultRegistryMutation bool
 	excludeGroupRole              []string
 	excludeUsername               []string
-	filters                       []filter
+	filters                       []Filter
 	generateSuccessEvents         bool
 	mux                           sync.RWMutex
 	webhooks                      []WebhookConfig
+	resourceVersion               string
 }
 
 // NewDefaultConfiguration ...
@@ -186,18 +213,16 @@ func NewConfiguration(client kubernetes.Interface) (Configuration, error) {
 }
 
 func (cd *configuration) ToFilter(kind, namespace, name string) bool {
+	return cd.ToFilterResource(ResourceInfo{Kind: kind, Namespace: namespace, Name: name})
+}
+
+func (cd *configuration) ToFilterResource(resource ResourceInfo) bool {
 	cd.mux.RLock()
 	defer cd.mux.RUnlock()
 	for _, f := range cd.filters {
-		if wildcard.Match(f.Kind, kind) && wildcard.Match(f.Namespace, namespace) && wildcard.Match(f.Name, name) {
+		if f.matches(resource) {
 			return true
 		}
-		if kind == "Namespace" {
-			// [Namespace,kube-system,*] || [*,kube-system,*]
-			if (f.Kind == "Namespace" || f.Kind == "*") && wildcard.Match(f.Namespace, name) {
-				return true
-			}
-		}
 	}
 	return false
 }
@@ -248,6 +273,12 @@ func (cd *configuration) GetWebhooks() []WebhookConfig {
 	return cd.webhooks
 }
 
+func (cd *configuration) GetResourceVersion() string {
+	cd.mux.RLock()
+	defer cd.mux.RUnlock()
+	return cd.resourceVersion
+}
+
 func (cd *configuration) Load(cm *corev1.ConfigMap) {
 	if cm != nil {
 		cd.load(cm)
@@ -264,11 +295,14 @@ func (cd *configuration) load(cm *corev1.ConfigMap) {
 	cd.mux.Lock()
 	defer cd.mux.Unlock()
 	// reset
-	cd.filters = []filter{}
+	cd.defaultRegistry = "docker.io"
+	cd.enableDefaultRegistryMutation = true
+	cd.filters = []Filter{}
 	cd.excludeGroupRole = []string{}
 	cd.excludeUsername = []string{}
 	cd.generateSuccessEvents = false
 	cd.webhooks = nil
+	cd.resourceVersion = "ConfigMap/" + cm.ResourceVersion
 	// load filters
 	cd.filters = parseKinds(cm.Data["resourceFilters"])
 	newDefaultRegistry, ok := cm.Data["defaultRegistry"]
@@ -320,15 +354,29 @@ func (cd *configuration) load(cm *corev1.ConfigMap) {
 	}
 }
 
+func (cd *configuration) LoadSettings(settings Settings) {
+	cd.mux.Lock()
+	defer cd.mux.Unlock()
+	cd.defaultRegistry = settings.DefaultRegistry
+	cd.enableDefaultRegistryMutation = settings.EnableDefaultRegistryMutation
+	cd.excludeGroupRole = append(append([]string{}, settings.ExcludeGroupRole...), defaultExcludeGroupRole...)
+	cd.excludeUsername = settings.ExcludeUsername
+	cd.filters = settings.Filters
+	cd.generateSuccessEvents = settings.GenerateSuccessEvents
+	cd.webhooks = settings.Webhooks
+	cd.resourceVersion = settings.ResourceVersion
+}
+
 func (cd *configuration) unload() {
 	cd.mux.Lock()
 	defer cd.mux.Unlock()
-	cd.filters = []filter{}
+	cd.filters = []Filter{}
 	cd.defaultRegistry = "docker.io"
 	cd.enableDefaultRegistryMutation = true
 	cd.excludeGroupRole = []string{}
 	cd.excludeUsername = []string{}
 	cd.generateSuccessEvents = false
 	cd.webhooks = nil
+	cd.resourceVersion = ""
 	cd.excludeGroupRole = append(cd.excludeGroupRole, defaultExcludeGroupRole...)
 }
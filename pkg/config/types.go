@@ -5,7 +5,9 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type WebhookConfig struct {
@@ -36,17 +38,75 @@ func parseIncludeExcludeNamespacesFromNamespacesConfig(jsonStr string) (namespac
 	return namespacesConfigObject, err
 }
 
-type filter struct {
-	Kind      string // TODO: as we currently only support one GVK version, we use the kind only. But if we support multiple GVK, then GV need to be added
-	Namespace string
-	Name      string
+// Filter selects resources ignored by kyverno, string fields support wildcards.
+// Kind, namespace and name are always matched, other fields are matched when set.
+type Filter struct {
+	Group             string
+	Version           string
+	Kind              string
+	Subresource       string
+	Namespace         string
+	Name              string
+	Selector          labels.Selector
+	NamespaceSelector labels.Selector
+	Usernames         []string
+	UserGroups        []string
+}
+
+// ResourceInfo describes a resource, and the request changing it if any, to be matched against filters
+type ResourceInfo struct {
+	Group       string
+	Version     string
+	Kind        string
+	Subresource string
+	Namespace   string
+	Name        string
+	Labels      map[string]string
+	// NamespaceLabels are the labels of the resource namespace, nil if unknown
+	NamespaceLabels map[string]string
+	Username        string
+	UserGroups      []string
+}
+
+func (f Filter) matches(resource ResourceInfo) bool {
+	if f.Group != "" && !wildcard.Match(f.Group, resource.Group) {
+		return false
+	}
+	if f.Version != "" && !wildcard.Match(f.Version, resource.Version) {
+		return false
+	}
+	if f.Subresource != "" && !wildcard.Match(f.Subresource, resource.Subresource) {
+		return false
+	}
+	if f.Selector != nil && !f.Selector.Matches(labels.Set(resource.Labels)) {
+		return false
+	}
+	if f.NamespaceSelector != nil && (resource.NamespaceLabels == nil || !f.NamespaceSelector.Matches(labels.Set(resource.NamespaceLabels))) {
+		return false
+	}
+	if len(f.Usernames) != 0 && (resource.Username == "" || !wildcard.CheckPatterns(f.Usernames, resource.Username)) {
+		return false
+	}
+	if len(f.UserGroups) != 0 && (len(resource.UserGroups) == 0 || !wildcard.CheckPatterns(f.UserGroups, resource.UserGroups...)) {
+		return false
+	}
+	if wildcard.Match(f.Kind, resource.Kind) && wildcard.Match(f.Namespace, resource.Namespace) && wildcard.Match(f.Name, resource.Name) {
+		return true
+	}
+	if resource.Kind == "Namespace" {
+		// [Namespace,kube-system,*] || [*,kube-system,*]
+		if (f.Kind == "Namespace" || f.Kind == "*") && wildcard.Match(f.Namespace, resource.Name) {
+			return true
+		}
+	}
+	return false
 }
 
 // ParseKinds parses the kinds if a single string contains comma separated kinds
 // {"1,2,3","4","5"} => {"1","2","3","4","5"}
-func parseKinds(list string) []filter {
-	resources := []filter{}
-	var resource filter
+func parseKinds(list string) []Filter {
+	resources := []Filter{}
+	var resource Filter
 	re := regexp.MustCompile(`\[([^\[\]]*)\]`)
 	submatchall := re.FindAllString(list, -1)
 	for _, element := range submatchall {
@@ -57,13 +117,13 @@ func parseKinds(list string) []filter {
 			continue
 		}
 		if len(elements) == 3 {
-			resource = filter{Kind: elements[0], Namespace: elements[1], Name: elements[2]}
+			resource = Filter{Kind: elements[0], Namespace: elements[1], Name: elements[2]}
 		}
 		if len(elements) == 2 {
-			resource = filter{Kind: elements[0], Namespace: elements[1]}
+			resource = Filter{Kind: elements[0], Namespace: elements[1]}
 		}
 		if len(elements) == 1 {
-			resource = filter{Kind: elements[0]}
+			resource = Filter{Kind: elements[0]}
 		}
 		resources = append(resources, resource)
 	}
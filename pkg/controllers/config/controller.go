@@ -5,10 +5,16 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	kyvernov2alpha1informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v2alpha1"
+	kyvernov2alpha1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v2alpha1"
 	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/controllers"
+	configutils "github.com/kyverno/kyverno/pkg/utils/config"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/util/workqueue"
@@ -24,20 +30,32 @@ const (
 type controller struct {
 	configuration config.Configuration
 
+	// clients
+	kyvernoClient versioned.Interface
+
 	// listers
-	configmapLister corev1listers.ConfigMapLister
+	configmapLister            corev1listers.ConfigMapLister
+	kyvernoConfigurationLister kyvernov2alpha1listers.KyvernoConfigurationLister
 
 	// queue
 	queue workqueue.RateLimitingInterface
 }
 
-func NewController(configuration config.Configuration, configmapInformer corev1informers.ConfigMapInformer) controllers.Controller {
+func NewController(
+	configuration config.Configuration,
+	kyvernoClient versioned.Interface,
+	configmapInformer corev1informers.ConfigMapInformer,
+	kyvernoConfigurationInformer kyvernov2alpha1informers.KyvernoConfigurationInformer,
+) controllers.Controller {
 	c := controller{
-		configuration:   configuration,
-		configmapLister: configmapInformer.Lister(),
-		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		configuration:              configuration,
+		kyvernoClient:              kyvernoClient,
+		configmapLister:            configmapInformer.Lister(),
+		kyvernoConfigurationLister: kyvernoConfigurationInformer.Lister(),
+		queue:                      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
 	}
 	controllerutils.AddDefaultEventHandlers(logger, configmapInformer.Informer(), c.queue)
+	controllerutils.AddDefaultEventHandlers(logger, kyvernoConfigurationInformer.Informer(), c.queue)
 	return &c
 }
 
@@ -46,9 +64,29 @@ func (c *controller) Run(ctx context.Context, workers int) {
 }
 
 func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, namespace, name string) error {
-	if namespace != config.KyvernoNamespace() || name != config.KyvernoConfigMapName() {
+	if namespace == "" {
+		if name != config.KyvernoConfigurationName() {
+			return nil
+		}
+	} else if namespace != config.KyvernoNamespace() || name != config.KyvernoConfigMapName() {
 		return nil
 	}
+	// the KyvernoConfiguration takes precedence over the ConfigMap unless it is invalid
+	kyvernoConfiguration, err := c.kyvernoConfigurationLister.Get(config.KyvernoConfigurationName())
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		errs := configutils.Load(c.configuration, kyvernoConfiguration, configutils.Admission)
+		if err := c.updateStatus(ctx, kyvernoConfiguration, errs); err != nil {
+			return err
+		}
+		if len(errs) == 0 {
+			return nil
+		}
+		logger.Info("invalid configuration, using the configmap instead", "errors", errs.ToAggregate().Error())
+	}
+	namespace, name = config.KyvernoNamespace(), config.KyvernoConfigMapName()
 	configMap, err := c.configmapLister.ConfigMaps(namespace).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -59,3 +97,20 @@ func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, nam
 	c.configuration.Load(configMap.DeepCopy())
 	return nil
 }
+
+func (c *controller) updateStatus(ctx context.Context, kyvernoConfiguration *kyvernov2alpha1.KyvernoConfiguration, errs field.ErrorList) error {
+	_, err := controllerutils.UpdateStatus(
+		ctx,
+		kyvernoConfiguration,
+		c.kyvernoClient.KyvernoV2alpha1().KyvernoConfigurations(),
+		func(kyvernoConfiguration *kyvernov2alpha1.KyvernoConfiguration) error {
+			if len(errs) == 0 {
+				kyvernoConfiguration.GetStatus().SetApplied(kyvernoConfiguration.GetGeneration())
+			} else {
+				kyvernoConfiguration.GetStatus().SetInvalid(kyvernoConfiguration.GetGeneration(), errs)
+			}
+			return nil
+		},
+	)
+	return err
+}
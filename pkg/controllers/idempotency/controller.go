@@ -0,0 +1,160 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	kyvernov1informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	kyvernov1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/controllers"
+	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// Workers is the number of workers for this controller
+	Workers        = 1
+	ControllerName = "idempotency-controller"
+	maxRetries     = 10
+)
+
+// Recorder records the mutate rules found not idempotent by the admission controller
+type Recorder interface {
+	// Record queues the non idempotent rules of the policy to be reported in its status
+	Record(policy kyvernov1.PolicyInterface, rules ...string)
+}
+
+type Controller interface {
+	controllers.Controller
+	Recorder
+}
+
+// notIdempotent stores the non idempotent rules recorded for a policy generation
+type notIdempotent struct {
+	generation int64
+	rules      sets.String
+}
+
+type controller struct {
+	// clients
+	client versioned.Interface
+
+	// listers
+	cpolLister kyvernov1listers.ClusterPolicyLister
+	polLister  kyvernov1listers.PolicyLister
+
+	// queue
+	queue workqueue.RateLimitingInterface
+
+	lock     sync.Mutex
+	recorded map[string]notIdempotent
+}
+
+func NewController(
+	client versioned.Interface,
+	cpolInformer kyvernov1informers.ClusterPolicyInformer,
+	polInformer kyvernov1informers.PolicyInformer,
+) Controller {
+	return &controller{
+		client:     client,
+		cpolLister: cpolInformer.Lister(),
+		polLister:  polInformer.Lister(),
+		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		recorded:   map[string]notIdempotent{},
+	}
+}
+
+func (c *controller) Run(ctx context.Context, workers int) {
+	controllerutils.Run(ctx, logger, ControllerName, time.Second, c.queue, workers, maxRetries, c.reconcile)
+}
+
+func (c *controller) Record(policy kyvernov1.PolicyInterface, rules ...string) {
+	key, err := cache.MetaNamespaceKeyFunc(policy)
+	if err != nil {
+		logger.Error(err, "failed to compute policy key")
+		return
+	}
+	generation := policy.GetGeneration()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	recorded, ok := c.recorded[key]
+	if !ok || recorded.generation != generation {
+		recorded = notIdempotent{generation: generation, rules: sets.NewString()}
+	} else if recorded.rules.HasAll(rules...) && policy.GetStatus().IsNotIdempotent(generation, recorded.rules.List()...) {
+		// the status is already up to date
+		return
+	}
+	recorded.rules.Insert(rules...)
+	c.recorded[key] = recorded
+	c.queue.Add(key)
+}
+
+func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, namespace, name string) error {
+	c.lock.Lock()
+	recorded, ok := c.recorded[key]
+	var rules []string
+	if ok {
+		rules = recorded.rules.List()
+	}
+	c.lock.Unlock()
+	if !ok {
+		return nil
+	}
+	policy, err := c.getPolicy(namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			c.forget(key, recorded.generation)
+			return nil
+		}
+		return err
+	}
+	switch generation := policy.GetGeneration(); {
+	case generation > recorded.generation:
+		// the policy changed since the rules were recorded
+		c.forget(key, recorded.generation)
+		return nil
+	case generation < recorded.generation:
+		return fmt.Errorf("policy %s generation %d is not observed yet", key, recorded.generation)
+	}
+	if policy.GetStatus().IsNotIdempotent(recorded.generation, rules...) {
+		return nil
+	}
+	setCondition := func(status *kyvernov1.PolicyStatus) error {
+		status.SetNotIdempotent(recorded.generation, rules...)
+		return nil
+	}
+	if policy.IsNamespaced() {
+		_, err = controllerutils.UpdateStatus(ctx, policy.(*kyvernov1.Policy), c.client.KyvernoV1().Policies(namespace), func(policy *kyvernov1.Policy) error {
+			return setCondition(policy.GetStatus())
+		})
+	} else {
+		_, err = controllerutils.UpdateStatus(ctx, policy.(*kyvernov1.ClusterPolicy), c.client.KyvernoV1().ClusterPolicies(), func(policy *kyvernov1.ClusterPolicy) error {
+			return setCondition(policy.GetStatus())
+		})
+	}
+	return err
+}
+
+// forget drops the rules recorded for a policy generation
+func (c *controller) forget(key string, generation int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if recorded, ok := c.recorded[key]; ok && recorded.generation == generation {
+		delete(c.recorded, key)
+	}
+}
+
+func (c *controller) getPolicy(namespace, name string) (kyvernov1.PolicyInterface, error) {
+	if namespace == "" {
+		return c.cpolLister.Get(name)
+	}
+	return c.polLister.Policies(namespace).Get(name)
+}
@@ -0,0 +1,50 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	kyvernoinformers "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_controller(t *testing.T) {
+	policy := &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "add-sidecar", Generation: 2}}
+	client := fake.NewSimpleClientset(policy)
+	factory := kyvernoinformers.NewSharedInformerFactory(client, 0)
+	cpolInformer := factory.Kyverno().V1().ClusterPolicies()
+	assert.NilError(t, cpolInformer.Informer().GetIndexer().Add(policy))
+	c := NewController(client, cpolInformer, factory.Kyverno().V1().Policies()).(*controller)
+
+	// requests for the same policy are merged
+	c.Record(policy, "inject")
+	c.Record(policy, "resources", "inject")
+	assert.Equal(t, c.queue.Len(), 1)
+	key, _ := c.queue.Get()
+	c.queue.Done(key)
+	assert.NilError(t, c.reconcile(context.TODO(), logr.Discard(), key.(string), "", "add-sidecar"))
+	updated, err := client.KyvernoV1().ClusterPolicies().Get(context.TODO(), "add-sidecar", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, updated.GetStatus().IsNotIdempotent(2, "inject", "resources"))
+
+	// the status is not written again when it is up to date
+	assert.NilError(t, cpolInformer.Informer().GetIndexer().Update(updated))
+	c.Record(updated, "inject")
+	assert.Equal(t, c.queue.Len(), 0)
+	actions := len(client.Actions())
+	assert.NilError(t, c.reconcile(context.TODO(), logr.Discard(), key.(string), "", "add-sidecar"))
+	assert.Equal(t, len(client.Actions()), actions)
+
+	// rules recorded for a previous generation are dropped
+	c.Record(policy, "other")
+	changed := updated.DeepCopy()
+	changed.SetGeneration(3)
+	assert.NilError(t, cpolInformer.Informer().GetIndexer().Update(changed))
+	assert.NilError(t, c.reconcile(context.TODO(), logr.Discard(), key.(string), "", "add-sidecar"))
+	assert.Equal(t, len(client.Actions()), actions)
+	assert.Equal(t, len(c.recorded), 0)
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"context"
+
+	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
+	policyreportv1alpha2informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/policyreport/v1alpha2"
+	policyreportv1alpha2listers "github.com/kyverno/kyverno/pkg/client/listers/policyreport/v1alpha2"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type controller struct {
+	metricsConfig metrics.MetricsConfigManager
+	reportResults asyncint64.Gauge
+
+	// listers
+	polrLister  policyreportv1alpha2listers.PolicyReportLister
+	cpolrLister policyreportv1alpha2listers.ClusterPolicyReportLister
+}
+
+type resultKey struct {
+	namespace string
+	policy    string
+	rule      string
+	severity  policyreportv1alpha2.PolicySeverity
+	result    policyreportv1alpha2.PolicyResult
+}
+
+// NewController registers a gauge reporting the number of results in policy reports, computed from the informers cache
+// every time metrics are collected, results disappear from the metrics as soon as they disappear from the reports.
+func NewController(metricsConfig metrics.MetricsConfigManager, polrInformer policyreportv1alpha2informers.PolicyReportInformer, cpolrInformer policyreportv1alpha2informers.ClusterPolicyReportInformer) {
+	meter := global.MeterProvider().Meter(metrics.MeterName)
+	reportResultsMetric, err := meter.AsyncInt64().Gauge(
+		"kyverno_policy_report_results",
+		instrument.WithDescription("can be used to track the number of results stored in policy reports, by namespace, policy, rule, severity and result"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_policy_report_results")
+		return
+	}
+	c := controller{
+		metricsConfig: metricsConfig,
+		reportResults: reportResultsMetric,
+		polrLister:    polrInformer.Lister(),
+		cpolrLister:   cpolrInformer.Lister(),
+	}
+	if err := meter.RegisterCallback([]instrument.Asynchronous{c.reportResults}, c.report); err != nil {
+		logger.Error(err, "Failed to register callback")
+	}
+}
+
+func (c *controller) report(ctx context.Context) {
+	for key, count := range c.countResults() {
+		c.reportResults.Observe(ctx, count,
+			attribute.String("resource_namespace", key.namespace),
+			attribute.String("policy_name", key.policy),
+			attribute.String("rule_name", key.rule),
+			attribute.String("severity", string(key.severity)),
+			attribute.String("rule_result", string(key.result)),
+		)
+	}
+}
+
+func (c *controller) countResults() map[resultKey]int64 {
+	counts := map[resultKey]int64{}
+	polrs, err := c.polrLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list policy reports")
+		return nil
+	}
+	for _, polr := range polrs {
+		if controllerutils.IsManagedByKyverno(polr) && c.metricsConfig.Config().CheckNamespace(polr.Namespace) {
+			countResults(counts, polr.Namespace, polr.GetResults())
+		}
+	}
+	cpolrs, err := c.cpolrLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list cluster policy reports")
+		return nil
+	}
+	for _, cpolr := range cpolrs {
+		if controllerutils.IsManagedByKyverno(cpolr) {
+			countResults(counts, "-", cpolr.GetResults())
+		}
+	}
+	return counts
+}
+
+func countResults(counts map[resultKey]int64, namespace string, results []policyreportv1alpha2.PolicyReportResult) {
+	for _, result := range results {
+		counts[resultKey{
+			namespace: namespace,
+			policy:    result.Policy,
+			rule:      result.Rule,
+			severity:  result.Severity,
+			result:    result.Result,
+		}]++
+	}
+}
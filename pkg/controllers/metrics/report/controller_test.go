@@ -0,0 +1,56 @@
+package report
+
+import (
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	kyvernoinformer "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
+	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type metricsConfig struct {
+	metrics.MetricsConfigManager
+	config config.MetricsConfiguration
+}
+
+func (c metricsConfig) Config() config.MetricsConfiguration { return c.config }
+
+type namespaces struct {
+	config.MetricsConfiguration
+	excluded string
+}
+
+func (n namespaces) CheckNamespace(namespace string) bool { return namespace != n.excluded }
+
+func Test_CountResults(t *testing.T) {
+	managed := map[string]string{kyvernov1.LabelAppManagedBy: kyvernov1.ValueKyvernoApp}
+	results := []policyreportv1alpha2.PolicyReportResult{
+		{Policy: "p", Rule: "r", Severity: policyreportv1alpha2.SeverityHigh, Result: policyreportv1alpha2.StatusFail},
+		{Policy: "p", Rule: "r", Severity: policyreportv1alpha2.SeverityHigh, Result: policyreportv1alpha2.StatusFail},
+		{Policy: "p", Rule: "r", Severity: policyreportv1alpha2.SeverityHigh, Result: policyreportv1alpha2.StatusPass},
+	}
+	informers := kyvernoinformer.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	polrs := informers.Wgpolicyk8s().V1alpha2().PolicyReports().Informer().GetIndexer()
+	cpolrs := informers.Wgpolicyk8s().V1alpha2().ClusterPolicyReports().Informer().GetIndexer()
+	assert.NilError(t, polrs.Add(&policyreportv1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", Labels: managed}, Results: results}))
+	assert.NilError(t, polrs.Add(&policyreportv1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default", Labels: managed}, Results: results[:1]}))
+	assert.NilError(t, polrs.Add(&policyreportv1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "excluded", Labels: managed}, Results: results}))
+	assert.NilError(t, polrs.Add(&policyreportv1alpha2.PolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "default"}, Results: results}))
+	assert.NilError(t, cpolrs.Add(&policyreportv1alpha2.ClusterPolicyReport{ObjectMeta: metav1.ObjectMeta{Name: "e", Labels: managed}, Results: results[2:]}))
+	c := controller{
+		metricsConfig: metricsConfig{config: namespaces{excluded: "excluded"}},
+		polrLister:    informers.Wgpolicyk8s().V1alpha2().PolicyReports().Lister(),
+		cpolrLister:   informers.Wgpolicyk8s().V1alpha2().ClusterPolicyReports().Lister(),
+	}
+	counts := c.countResults()
+	assert.DeepEqual(t, counts, map[resultKey]int64{
+		{namespace: "default", policy: "p", rule: "r", severity: policyreportv1alpha2.SeverityHigh, result: policyreportv1alpha2.StatusFail}: 3,
+		{namespace: "default", policy: "p", rule: "r", severity: policyreportv1alpha2.SeverityHigh, result: policyreportv1alpha2.StatusPass}: 1,
+		{namespace: "-", policy: "p", rule: "r", severity: policyreportv1alpha2.SeverityHigh, result: policyreportv1alpha2.StatusPass}:       1,
+	})
+}
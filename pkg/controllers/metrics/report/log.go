@@ -0,0 +1,7 @@
+package report
+
+import "github.com/kyverno/kyverno/pkg/logging"
+
+const controllerName = "report-metrics"
+
+var logger = logging.ControllerLogger(controllerName)
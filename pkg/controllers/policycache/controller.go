@@ -2,6 +2,7 @@ package policycache
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -31,6 +32,8 @@ const (
 type Controller interface {
 	controllers.Controller
 	WarmUp() error
+	// CheckReady returns an error if existing policies are missing from the cache
+	CheckReady() error
 }
 
 type controller struct {
@@ -91,6 +94,32 @@ func (c *controller) WarmUp() error {
 	return nil
 }
 
+func (c *controller) CheckReady() error {
+	var missing int
+	pols, err := c.polLister.Policies(metav1.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, policy := range pols {
+		if key, err := cache.MetaNamespaceKeyFunc(policy); err == nil && !c.cache.Has(key) {
+			missing++
+		}
+	}
+	cpols, err := c.cpolLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, policy := range cpols {
+		if key, err := cache.MetaNamespaceKeyFunc(policy); err == nil && !c.cache.Has(key) {
+			missing++
+		}
+	}
+	if missing != 0 {
+		return fmt.Errorf("%d policies are not loaded in the policy cache", missing)
+	}
+	return nil
+}
+
 func (c *controller) Run(ctx context.Context, workers int) {
 	controllerutils.Run(ctx, logger, ControllerName, time.Second, c.queue, workers, maxRetries, c.reconcile)
 }
@@ -0,0 +1,33 @@
+package policycache
+
+import (
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	kyvernoinformers "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
+	pcache "github.com/kyverno/kyverno/pkg/policycache"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_CheckReady(t *testing.T) {
+	informers := kyvernoinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	cpolInformer := informers.Kyverno().V1().ClusterPolicies()
+	polInformer := informers.Kyverno().V1().Policies()
+	policyCache := pcache.NewCache()
+	c := NewController(nil, policyCache, cpolInformer, polInformer)
+	assert.NilError(t, c.CheckReady())
+
+	cpol := &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "cpol"}}
+	pol := &kyvernov1.Policy{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pol"}}
+	assert.NilError(t, cpolInformer.Informer().GetIndexer().Add(cpol))
+	assert.NilError(t, polInformer.Informer().GetIndexer().Add(pol))
+	assert.Error(t, c.CheckReady(), "2 policies are not loaded in the policy cache")
+
+	policyCache.Set("cpol", cpol, nil)
+	assert.Error(t, c.CheckReady(), "1 policies are not loaded in the policy cache")
+
+	policyCache.Set("default/pol", pol, nil)
+	assert.NilError(t, c.CheckReady())
+}
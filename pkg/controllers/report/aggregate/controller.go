@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -16,11 +18,14 @@ import (
 	kyvernov1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/controllers"
 	"github.com/kyverno/kyverno/pkg/controllers/report/resource"
+	"github.com/kyverno/kyverno/pkg/controllers/report/utils"
+	"github.com/kyverno/kyverno/pkg/reportsink"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	metadatainformers "k8s.io/client-go/metadata/metadatainformer"
 	"k8s.io/client-go/tools/cache"
@@ -37,6 +42,8 @@ const (
 	maxRetries     = 10
 	mergeLimit     = 1000
 	enqueueDelay   = 30 * time.Second
+	// propertyOwnerChain is the result property listing the owners a result was rolled up through
+	propertyOwnerChain = "ownerChain"
 )
 
 type controller struct {
@@ -58,6 +65,18 @@ type controller struct {
 	metadataCache resource.MetadataCache
 
 	chunkSize int
+
+	// perResource maintains one policy report per resource instead of per namespace
+	perResource bool
+
+	// owners resolves controller owners when results are rolled up to workloads, it is optional
+	owners utils.OwnerResolver
+
+	// sink receives the changes of aggregated results, it is optional
+	sink reportsink.Interface
+	// results stores the last aggregated results per namespace, used to compute deltas
+	results     map[string][]policyreportv1alpha2.PolicyReportResult
+	resultsLock sync.Mutex
 }
 
 type policyMapEntry struct {
@@ -76,6 +95,9 @@ func NewController(
 	cpolInformer kyvernov1informers.ClusterPolicyInformer,
 	metadataCache resource.MetadataCache,
 	chunkSize int,
+	perResource bool,
+	owners utils.OwnerResolver,
+	sink reportsink.Interface,
 ) controllers.Controller {
 	admrInformer := metadataFactory.ForResource(kyvernov1alpha2.SchemeGroupVersion.WithResource("admissionreports"))
 	cadmrInformer := metadataFactory.ForResource(kyvernov1alpha2.SchemeGroupVersion.WithResource("clusteradmissionreports"))
@@ -94,6 +116,10 @@ func NewController(
 		queue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
 		metadataCache:  metadataCache,
 		chunkSize:      chunkSize,
+		perResource:    perResource,
+		owners:         owners,
+		sink:           sink,
+		results:        map[string][]policyreportv1alpha2.PolicyReportResult{},
 	}
 	controllerutils.AddDelayedExplicitEventHandlers(logger, polrInformer.Informer(), c.queue, enqueueDelay, keyFunc)
 	controllerutils.AddDelayedExplicitEventHandlers(logger, cpolrInformer.Informer(), c.queue, enqueueDelay, keyFunc)
@@ -139,7 +165,7 @@ func (c *controller) mergeAdmissionReports(ctx context.Context, namespace string
 			}
 			next = cadms.Continue
 			for i := range cadms.Items {
-				mergeReports(policyMap, accumulator, &cadms.Items[i])
+				c.mergeReports(ctx, policyMap, accumulator, &cadms.Items[i])
 			}
 			if next == "" {
 				return nil
@@ -159,7 +185,7 @@ func (c *controller) mergeAdmissionReports(ctx context.Context, namespace string
 			}
 			next = adms.Continue
 			for i := range adms.Items {
-				mergeReports(policyMap, accumulator, &adms.Items[i])
+				c.mergeReports(ctx, policyMap, accumulator, &adms.Items[i])
 			}
 			if next == "" {
 				return nil
@@ -181,7 +207,7 @@ func (c *controller) mergeBackgroundScanReports(ctx context.Context, namespace s
 			}
 			next = cbgscans.Continue
 			for i := range cbgscans.Items {
-				mergeReports(policyMap, accumulator, &cbgscans.Items[i])
+				c.mergeReports(ctx, policyMap, accumulator, &cbgscans.Items[i])
 			}
 			if next == "" {
 				return nil
@@ -199,7 +225,7 @@ func (c *controller) mergeBackgroundScanReports(ctx context.Context, namespace s
 			}
 			next = bgscans.Continue
 			for i := range bgscans.Items {
-				mergeReports(policyMap, accumulator, &bgscans.Items[i])
+				c.mergeReports(ctx, policyMap, accumulator, &bgscans.Items[i])
 			}
 			if next == "" {
 				return nil
@@ -208,26 +234,30 @@ func (c *controller) mergeBackgroundScanReports(ctx context.Context, namespace s
 	}
 }
 
-func (c *controller) reconcileReport(ctx context.Context, policyMap map[string]policyMapEntry, report kyvernov1alpha2.ReportInterface, namespace, name string, results ...policyreportv1alpha2.PolicyReportResult) (kyvernov1alpha2.ReportInterface, error) {
-	if report == nil {
-		report = reportutils.NewPolicyReport(namespace, name, results...)
+func (c *controller) reconcileReport(ctx context.Context, policyMap map[string]policyMapEntry, report kyvernov1alpha2.ReportInterface, namespace, name string, owner *corev1.ObjectReference, results ...policyreportv1alpha2.PolicyReportResult) (kyvernov1alpha2.ReportInterface, error) {
+	decorate := func(report kyvernov1alpha2.ReportInterface) {
 		for _, result := range results {
 			policy := policyMap[result.Policy]
 			if policy.policy != nil {
 				reportutils.SetPolicyLabel(report, policy.policy)
 			}
 		}
+		if owner != nil {
+			controllerutils.SetOwner(report, owner.APIVersion, owner.Kind, owner.Name, owner.UID)
+			reportutils.SetResourceLabels(report, owner.UID)
+			reportutils.SetResultLabels(report, results...)
+		}
+	}
+	if report == nil {
+		report = reportutils.NewPolicyReport(namespace, name, results...)
+		decorate(report)
 		return reportutils.CreateReport(ctx, report, c.client)
 	}
 	after := reportutils.DeepCopy(report)
 	after.SetLabels(nil)
+	after.SetOwnerReferences(nil)
 	reportutils.SetManagedByKyvernoLabel(after)
-	for _, result := range results {
-		policy := policyMap[result.Policy]
-		if policy.policy != nil {
-			reportutils.SetPolicyLabel(after, policy.policy)
-		}
-	}
+	decorate(after)
 	reportutils.SetResults(after, results...)
 	if reflect.DeepEqual(report, after) {
 		return after, nil
@@ -251,21 +281,33 @@ func (c *controller) cleanReports(ctx context.Context, actual map[string]kyverno
 	return nil
 }
 
-func mergeReports(policyMap map[string]policyMapEntry, accumulator map[string]policyreportv1alpha2.PolicyReportResult, reports ...kyvernov1alpha2.ReportInterface) {
+func (c *controller) mergeReports(ctx context.Context, policyMap map[string]policyMapEntry, accumulator map[string]policyreportv1alpha2.PolicyReportResult, reports ...kyvernov1alpha2.ReportInterface) {
 	for _, report := range reports {
 		if len(report.GetOwnerReferences()) == 1 {
 			ownerRef := report.GetOwnerReferences()[0]
-			objectRefs := []corev1.ObjectReference{{
+			resource := corev1.ObjectReference{
 				APIVersion: ownerRef.APIVersion,
 				Kind:       ownerRef.Kind,
 				Namespace:  report.GetNamespace(),
 				Name:       ownerRef.Name,
 				UID:        ownerRef.UID,
-			}}
+			}
+			// when rolling up, results are attributed to the top level controller owner of the resource
+			var chain []corev1.ObjectReference
+			if c.owners != nil {
+				chain = c.owners.OwnerChain(ctx, resource)
+				resource = chain[len(chain)-1]
+			}
+			objectRefs := []corev1.ObjectReference{resource}
 			for _, result := range report.GetResults() {
 				currentPolicy := policyMap[result.Policy]
 				if currentPolicy.rules != nil && currentPolicy.rules.Has(result.Rule) {
-					key := result.Policy + "/" + result.Rule + "/" + string(ownerRef.UID)
+					key := result.Policy + "/" + result.Rule + "/" + string(resource.UID)
+					if len(chain) > 1 {
+						// identical results of resources owned by the same workload are merged
+						key += "/" + string(result.Result)
+						result.Properties = withOwnerChain(result.Properties, chain)
+					}
 					result.Resources = objectRefs
 					if rule, exists := accumulator[key]; !exists {
 						accumulator[key] = result
@@ -278,6 +320,20 @@ func mergeReports(policyMap map[string]policyMapEntry, accumulator map[string]po
 	}
 }
 
+// withOwnerChain returns a copy of the result properties with the owner chain, from the resource to its top level owner
+func withOwnerChain(properties map[string]string, chain []corev1.ObjectReference) map[string]string {
+	out := make(map[string]string, len(properties)+1)
+	for key, value := range properties {
+		out[key] = value
+	}
+	owners := make([]string, 0, len(chain))
+	for _, owner := range chain {
+		owners = append(owners, owner.Kind+"/"+owner.Name)
+	}
+	out[propertyOwnerChain] = strings.Join(owners, ",")
+	return out
+}
+
 func (c *controller) createPolicyMap() (map[string]policyMapEntry, error) {
 	results := map[string]policyMapEntry{}
 	cpols, err := c.cpolLister.List(labels.Everything())
@@ -375,6 +431,24 @@ func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, _,
 	for _, report := range policyReports {
 		actual[report.GetName()] = report
 	}
+	var expected []kyvernov1alpha2.ReportInterface
+	if c.perResource {
+		expected, err = c.reconcilePerResource(ctx, policyMap, actual, key, results)
+	} else {
+		expected, err = c.reconcilePerPolicy(ctx, logger, policyMap, actual, key, results)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.cleanReports(ctx, actual, expected); err != nil {
+		return err
+	}
+	c.publishDelta(key, policyReports, results)
+	return nil
+}
+
+// reconcilePerPolicy maintains reports split by policy, chunked according to the configured chunk size
+func (c *controller) reconcilePerPolicy(ctx context.Context, logger logr.Logger, policyMap map[string]policyMapEntry, actual map[string]kyvernov1alpha2.ReportInterface, namespace string, results []policyreportv1alpha2.PolicyReportResult) ([]kyvernov1alpha2.ReportInterface, error) {
 	splitReports := reportutils.SplitResultsByPolicy(logger, results)
 	var expected []kyvernov1alpha2.ReportInterface
 	chunkSize := c.chunkSize
@@ -391,12 +465,69 @@ func (c *controller) reconcile(ctx context.Context, logger logr.Logger, key, _,
 			if i > 0 {
 				name = fmt.Sprintf("%s-%d", name, i/chunkSize)
 			}
-			report, err := c.reconcileReport(ctx, policyMap, actual[name], key, name, results[i:end]...)
+			report, err := c.reconcileReport(ctx, policyMap, actual[name], namespace, name, nil, results[i:end]...)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			expected = append(expected, report)
 		}
 	}
-	return c.cleanReports(ctx, actual, expected)
+	return expected, nil
+}
+
+// reconcilePerResource maintains one report per resource, named after the resource uid and owned by
+// the resource so that it is garbage collected with it
+func (c *controller) reconcilePerResource(ctx context.Context, policyMap map[string]policyMapEntry, actual map[string]kyvernov1alpha2.ReportInterface, namespace string, results []policyreportv1alpha2.PolicyReportResult) ([]kyvernov1alpha2.ReportInterface, error) {
+	splitReports := splitResultsByResource(results)
+	var expected []kyvernov1alpha2.ReportInterface
+	for _, entry := range splitReports {
+		name := string(entry.owner.UID)
+		report, err := c.reconcileReport(ctx, policyMap, actual[name], namespace, name, &entry.owner, entry.results...)
+		if err != nil {
+			return nil, err
+		}
+		expected = append(expected, report)
+	}
+	return expected, nil
+}
+
+type resourceResults struct {
+	owner   corev1.ObjectReference
+	results []policyreportv1alpha2.PolicyReportResult
+}
+
+func splitResultsByResource(results []policyreportv1alpha2.PolicyReportResult) map[types.UID]*resourceResults {
+	resources := map[types.UID]*resourceResults{}
+	for _, result := range results {
+		// merged results always reference a single resource
+		if len(result.Resources) != 1 || result.Resources[0].UID == "" {
+			continue
+		}
+		resource := result.Resources[0]
+		entry := resources[resource.UID]
+		if entry == nil {
+			entry = &resourceResults{owner: resource}
+			resources[resource.UID] = entry
+		}
+		entry.results = append(entry.results, result)
+	}
+	return resources
+}
+
+// publishDelta sends the changes of aggregated results to the sink, when the previous results
+// of the namespace are not known (after a restart) they are taken from the existing policy reports
+func (c *controller) publishDelta(namespace string, policyReports []kyvernov1alpha2.ReportInterface, results []policyreportv1alpha2.PolicyReportResult) {
+	if c.sink == nil {
+		return
+	}
+	c.resultsLock.Lock()
+	defer c.resultsLock.Unlock()
+	previous, ok := c.results[namespace]
+	if !ok {
+		for _, report := range policyReports {
+			previous = append(previous, report.GetResults()...)
+		}
+	}
+	c.results[namespace] = results
+	c.sink.Publish(reportsink.ComputeDelta(namespace, previous, results))
 }
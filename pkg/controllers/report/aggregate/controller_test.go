@@ -0,0 +1,102 @@
+package aggregate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	kyvernov1alpha2 "github.com/kyverno/kyverno/api/kyverno/v1alpha2"
+	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func Test_ReconcilePerResource(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	c := controller{client: client, perResource: true}
+	policy := &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "require-labels", ResourceVersion: "42"}}
+	policyMap := map[string]policyMapEntry{
+		"require-labels": {policy: policy, rules: sets.New("check")},
+	}
+	pod := corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "web", UID: "pod-uid"}
+	svc := corev1.ObjectReference{APIVersion: "v1", Kind: "Service", Namespace: "default", Name: "web", UID: "svc-uid"}
+	results := []policyreportv1alpha2.PolicyReportResult{
+		{Policy: "require-labels", Rule: "check", Result: policyreportv1alpha2.StatusFail, Resources: []corev1.ObjectReference{pod}},
+		{Policy: "require-labels", Rule: "other", Result: policyreportv1alpha2.StatusPass, Resources: []corev1.ObjectReference{pod}},
+		{Policy: "require-labels", Rule: "check", Result: policyreportv1alpha2.StatusPass, Resources: []corev1.ObjectReference{svc}},
+	}
+	stale := reportutils.NewPolicyReport("default", "cpol-require-labels")
+	actual := map[string]kyvernov1alpha2.ReportInterface{stale.GetName(): stale}
+
+	expected, err := c.reconcilePerResource(ctx, policyMap, actual, "default", results)
+	assert.NilError(t, err)
+	assert.Equal(t, len(expected), 2)
+
+	report, err := client.Wgpolicyk8sV1alpha2().PolicyReports("default").Get(ctx, "pod-uid", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(report.GetResults()), 2)
+	assert.Equal(t, len(report.GetOwnerReferences()), 1)
+	assert.Equal(t, report.GetOwnerReferences()[0].Kind, "Pod")
+	assert.Equal(t, report.GetOwnerReferences()[0].Name, "web")
+	assert.Equal(t, report.GetLabels()[reportutils.LabelResourceUid], "pod-uid")
+	assert.Equal(t, report.GetLabels()[reportutils.LabelPrefixResult+policyreportv1alpha2.StatusFail], "1")
+	assert.Equal(t, report.GetLabels()[reportutils.LabelPrefixResult+policyreportv1alpha2.StatusPass], "1")
+	assert.Equal(t, report.GetLabels()[reportutils.PolicyLabel(policy)], "42")
+	assert.Equal(t, report.GetLabels()[kyvernov1.LabelAppManagedBy], kyvernov1.ValueKyvernoApp)
+
+	// a second pass with unchanged results doesn't modify the reports
+	actual = map[string]kyvernov1alpha2.ReportInterface{}
+	for _, report := range expected {
+		actual[report.GetName()] = report
+	}
+	again, err := c.reconcilePerResource(ctx, policyMap, actual, "default", results)
+	assert.NilError(t, err)
+	for _, report := range again {
+		assert.Equal(t, report.GetResourceVersion(), actual[report.GetName()].GetResourceVersion())
+	}
+}
+
+type staticOwners map[types.UID]corev1.ObjectReference
+
+func (o staticOwners) OwnerChain(_ context.Context, resource corev1.ObjectReference) []corev1.ObjectReference {
+	chain := []corev1.ObjectReference{resource}
+	for {
+		owner, ok := o[resource.UID]
+		if !ok {
+			return chain
+		}
+		chain = append(chain, owner)
+		resource = owner
+	}
+}
+
+func Test_MergeReportsRollUp(t *testing.T) {
+	deployment := corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "web", UID: "deploy-uid"}
+	replicaSet := corev1.ObjectReference{APIVersion: "apps/v1", Kind: "ReplicaSet", Namespace: "default", Name: "web-abc", UID: "rs-uid"}
+	c := controller{owners: staticOwners{"pod-1": replicaSet, "pod-2": replicaSet, "pod-3": replicaSet, "rs-uid": deployment}}
+	policyMap := map[string]policyMapEntry{"p": {rules: sets.New("check")}}
+	newReport := func(uid types.UID, status policyreportv1alpha2.PolicyResult) kyvernov1alpha2.ReportInterface {
+		report := reportutils.NewBackgroundScanReport("default", string(uid), schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, "web-"+string(uid), uid)
+		reportutils.SetResults(report, policyreportv1alpha2.PolicyReportResult{Policy: "p", Rule: "check", Result: status})
+		return report
+	}
+	accumulator := map[string]policyreportv1alpha2.PolicyReportResult{}
+	c.mergeReports(context.Background(), policyMap, accumulator,
+		newReport("pod-1", policyreportv1alpha2.StatusFail),
+		newReport("pod-2", policyreportv1alpha2.StatusFail),
+		newReport("pod-3", policyreportv1alpha2.StatusPass),
+	)
+	assert.Equal(t, len(accumulator), 2)
+	for _, result := range accumulator {
+		assert.DeepEqual(t, result.Resources, []corev1.ObjectReference{deployment})
+		assert.Assert(t, strings.HasSuffix(result.Properties[propertyOwnerChain], ",ReplicaSet/web-abc,Deployment/web"))
+	}
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/cache"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	ownerCacheSize = 10000
+	ownerCacheTTL  = 10 * time.Minute
+)
+
+var (
+	pod         = schema.GroupKind{Kind: "Pod"}
+	replicaSet  = schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}
+	deployment  = schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	statefulSet = schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+	job         = schema.GroupKind{Group: "batch", Kind: "Job"}
+	cronJob     = schema.GroupKind{Group: "batch", Kind: "CronJob"}
+	// followedOwners are the controller owner kinds followed for each kind of resource
+	followedOwners = map[schema.GroupKind]sets.Set[schema.GroupKind]{
+		pod:        sets.New(replicaSet, statefulSet, job),
+		replicaSet: sets.New(deployment),
+		job:        sets.New(cronJob),
+	}
+	// topLevelOwners are the owner kinds results are rolled up to
+	topLevelOwners = sets.New(deployment, statefulSet, cronJob)
+)
+
+// OwnerResolver computes the chain of controller owners of a resource
+type OwnerResolver interface {
+	// OwnerChain returns the resource followed by its controller owners, up to the top level Deployment,
+	// StatefulSet or CronJob owner, the chain only contains the resource if it has no such owner
+	OwnerChain(ctx context.Context, resource corev1.ObjectReference) []corev1.ObjectReference
+}
+
+type ownerResolver struct {
+	logger logr.Logger
+	client dclient.Interface
+	// owners caches the controller owner of resources, a nil value means the resource has no owner
+	owners *cache.LRUExpireCache
+}
+
+func NewOwnerResolver(logger logr.Logger, client dclient.Interface) OwnerResolver {
+	return &ownerResolver{
+		logger: logger,
+		client: client,
+		owners: cache.NewLRUExpireCache(ownerCacheSize),
+	}
+}
+
+func groupKind(resource corev1.ObjectReference) schema.GroupKind {
+	return schema.FromAPIVersionAndKind(resource.APIVersion, resource.Kind).GroupKind()
+}
+
+func (r *ownerResolver) OwnerChain(ctx context.Context, resource corev1.ObjectReference) []corev1.ObjectReference {
+	chain := []corev1.ObjectReference{resource}
+	// followed owners never loop back to a followed kind, the chain is bounded
+	for {
+		followed, ok := followedOwners[groupKind(resource)]
+		if !ok {
+			break
+		}
+		owner := r.controllerOf(ctx, resource)
+		if owner == nil || !followed.Has(groupKind(*owner)) {
+			break
+		}
+		chain = append(chain, *owner)
+		resource = *owner
+	}
+	if !topLevelOwners.Has(groupKind(chain[len(chain)-1])) {
+		return chain[:1]
+	}
+	return chain
+}
+
+func (r *ownerResolver) controllerOf(ctx context.Context, resource corev1.ObjectReference) *corev1.ObjectReference {
+	if cached, ok := r.owners.Get(resource.UID); ok {
+		return cached.(*corev1.ObjectReference)
+	}
+	obj, err := r.client.GetResource(ctx, resource.APIVersion, resource.Kind, resource.Namespace, resource.Name)
+	if err != nil {
+		// don't cache errors, the resource may be temporarily unavailable
+		r.logger.V(4).Info("failed to get resource owner", "kind", resource.Kind, "name", resource.Name, "error", err.Error())
+		return nil
+	}
+	var owner *corev1.ObjectReference
+	if ref := metav1.GetControllerOfNoCopy(obj); ref != nil {
+		owner = &corev1.ObjectReference{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Namespace:  resource.Namespace,
+			Name:       ref.Name,
+			UID:        ref.UID,
+		}
+	}
+	r.owners.Add(resource.UID, owner, ownerCacheTTL)
+	return owner
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newOwned(apiVersion, kind, name string, owner *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := kubeutils.NewUnstructured(apiVersion, kind, "default", name)
+	obj.SetUID(types.UID(name))
+	if owner != nil {
+		controller := true
+		obj.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: owner.GetAPIVersion(),
+			Kind:       owner.GetKind(),
+			Name:       owner.GetName(),
+			UID:        owner.GetUID(),
+			Controller: &controller,
+		}})
+	}
+	return obj
+}
+
+func reference(obj *unstructured.Unstructured) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+		UID:        obj.GetUID(),
+	}
+}
+
+func Test_OwnerChain(t *testing.T) {
+	deploy := newOwned("apps/v1", "Deployment", "deploy", nil)
+	rs := newOwned("apps/v1", "ReplicaSet", "rs", deploy)
+	deployPod := newOwned("v1", "Pod", "deploy-pod", rs)
+	sts := newOwned("apps/v1", "StatefulSet", "sts", nil)
+	stsPod := newOwned("v1", "Pod", "sts-pod", sts)
+	cronjob := newOwned("batch/v1", "CronJob", "cronjob", nil)
+	cronjobJob := newOwned("batch/v1", "Job", "cronjob-job", cronjob)
+	cronjobPod := newOwned("v1", "Pod", "cronjob-pod", cronjobJob)
+	job := newOwned("batch/v1", "Job", "job", nil)
+	jobPod := newOwned("v1", "Pod", "job-pod", job)
+	rollout := newOwned("argoproj.io/v1alpha1", "Rollout", "rollout", nil)
+	rolloutRs := newOwned("apps/v1", "ReplicaSet", "rollout-rs", rollout)
+	rolloutPod := newOwned("v1", "Pod", "rollout-pod", rolloutRs)
+	orphan := newOwned("v1", "Pod", "orphan", nil)
+
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}:                                 "PodList",
+		{Group: "apps", Version: "v1", Resource: "replicasets"}:           "ReplicaSetList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}:           "DeploymentList",
+		{Group: "apps", Version: "v1", Resource: "statefulsets"}:          "StatefulSetList",
+		{Group: "batch", Version: "v1", Resource: "jobs"}:                 "JobList",
+		{Group: "batch", Version: "v1", Resource: "cronjobs"}:             "CronJobList",
+		{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}: "RolloutList",
+	}
+	var resources []schema.GroupVersionResource
+	for gvr := range gvrToListKind {
+		resources = append(resources, gvr)
+	}
+	client, err := dclient.NewFakeClient(runtime.NewScheme(), gvrToListKind,
+		deploy, rs, deployPod, sts, stsPod, cronjob, cronjobJob, cronjobPod, job, jobPod, rollout, rolloutRs, rolloutPod, orphan,
+	)
+	assert.NilError(t, err)
+	client.SetDiscovery(dclient.NewFakeDiscoveryClient(resources))
+	resolver := NewOwnerResolver(logr.Discard(), client)
+
+	tests := []struct {
+		name     string
+		resource *unstructured.Unstructured
+		want     []*unstructured.Unstructured
+	}{{
+		name:     "deployment",
+		resource: deployPod,
+		want:     []*unstructured.Unstructured{deployPod, rs, deploy},
+	}, {
+		name:     "statefulset",
+		resource: stsPod,
+		want:     []*unstructured.Unstructured{stsPod, sts},
+	}, {
+		name:     "cronjob",
+		resource: cronjobPod,
+		want:     []*unstructured.Unstructured{cronjobPod, cronjobJob, cronjob},
+	}, {
+		name:     "replicaset",
+		resource: rs,
+		want:     []*unstructured.Unstructured{rs, deploy},
+	}, {
+		name:     "job without cronjob",
+		resource: jobPod,
+		want:     []*unstructured.Unstructured{jobPod},
+	}, {
+		name:     "custom controller",
+		resource: rolloutPod,
+		want:     []*unstructured.Unstructured{rolloutPod},
+	}, {
+		name:     "no owner",
+		resource: orphan,
+		want:     []*unstructured.Unstructured{orphan},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want []corev1.ObjectReference
+			for _, obj := range tt.want {
+				want = append(want, reference(obj))
+			}
+			assert.DeepEqual(t, resolver.OwnerChain(context.TODO(), reference(tt.resource)), want)
+		})
+	}
+}
@@ -9,26 +9,33 @@ import (
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
 	"github.com/kyverno/kyverno/pkg/autogen"
 	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
 	kyvernov1informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
+	kyvernov2alpha1informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v2alpha1"
 	kyvernov1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
+	kyvernov2alpha1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v2alpha1"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/controllers"
 	"github.com/kyverno/kyverno/pkg/tls"
 	"github.com/kyverno/kyverno/pkg/utils"
+	configutils "github.com/kyverno/kyverno/pkg/utils/config"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
 	runtimeutils "github.com/kyverno/kyverno/pkg/utils/runtime"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
 	"github.com/kyverno/kyverno/pkg/utils/wildcard"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	admissionregistrationv1informers "k8s.io/client-go/informers/admissionregistration/v1"
 	coordinationv1informers "k8s.io/client-go/informers/coordination/v1"
@@ -80,14 +87,15 @@ type controller struct {
 	kyvernoClient   versioned.Interface
 
 	// listers
-	mwcLister         admissionregistrationv1listers.MutatingWebhookConfigurationLister
-	vwcLister         admissionregistrationv1listers.ValidatingWebhookConfigurationLister
-	cpolLister        kyvernov1listers.ClusterPolicyLister
-	polLister         kyvernov1listers.PolicyLister
-	secretLister      corev1listers.SecretLister
-	configMapLister   corev1listers.ConfigMapLister
-	leaseLister       coordinationv1listers.LeaseLister
-	clusterroleLister rbacv1listers.ClusterRoleLister
+	mwcLister                  admissionregistrationv1listers.MutatingWebhookConfigurationLister
+	vwcLister                  admissionregistrationv1listers.ValidatingWebhookConfigurationLister
+	cpolLister                 kyvernov1listers.ClusterPolicyLister
+	polLister                  kyvernov1listers.PolicyLister
+	secretLister               corev1listers.SecretLister
+	configMapLister            corev1listers.ConfigMapLister
+	kyvernoConfigurationLister kyvernov2alpha1listers.KyvernoConfigurationLister
+	leaseLister                coordinationv1listers.LeaseLister
+	clusterroleLister          rbacv1listers.ClusterRoleLister
 
 	// queue
 	queue workqueue.RateLimitingInterface
@@ -98,11 +106,14 @@ type controller struct {
 	servicePort        int32
 	autoUpdateWebhooks bool
 	admissionReports   bool
+	perPolicyWebhooks  bool
+	matchConditions    bool
 	runtime            runtimeutils.Runtime
 
 	// state
-	lock        sync.Mutex
-	policyState map[string]sets.Set[string]
+	lock           sync.Mutex
+	policyState    map[string]sets.Set[string]
+	conditionState map[string]map[string][]matchCondition
 }
 
 func NewController(
@@ -117,6 +128,7 @@ func NewController(
 	polInformer kyvernov1informers.PolicyInformer,
 	secretInformer corev1informers.SecretInformer,
 	configMapInformer corev1informers.ConfigMapInformer,
+	kyvernoConfigurationInformer kyvernov2alpha1informers.KyvernoConfigurationInformer,
 	leaseInformer coordinationv1informers.LeaseInformer,
 	clusterroleInformer rbacv1informers.ClusterRoleInformer,
 	server string,
@@ -124,34 +136,40 @@ func NewController(
 	servicePort int32,
 	autoUpdateWebhooks bool,
 	admissionReports bool,
+	perPolicyWebhooks bool,
+	matchConditions bool,
 	runtime runtimeutils.Runtime,
 ) controllers.Controller {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 	c := controller{
-		discoveryClient:    discoveryClient,
-		mwcClient:          mwcClient,
-		vwcClient:          vwcClient,
-		leaseClient:        leaseClient,
-		kyvernoClient:      kyvernoClient,
-		mwcLister:          mwcInformer.Lister(),
-		vwcLister:          vwcInformer.Lister(),
-		cpolLister:         cpolInformer.Lister(),
-		polLister:          polInformer.Lister(),
-		secretLister:       secretInformer.Lister(),
-		configMapLister:    configMapInformer.Lister(),
-		leaseLister:        leaseInformer.Lister(),
-		clusterroleLister:  clusterroleInformer.Lister(),
-		queue:              queue,
-		server:             server,
-		defaultTimeout:     defaultTimeout,
-		servicePort:        servicePort,
-		autoUpdateWebhooks: autoUpdateWebhooks,
-		admissionReports:   admissionReports,
-		runtime:            runtime,
+		discoveryClient:            discoveryClient,
+		mwcClient:                  mwcClient,
+		vwcClient:                  vwcClient,
+		leaseClient:                leaseClient,
+		kyvernoClient:              kyvernoClient,
+		mwcLister:                  mwcInformer.Lister(),
+		vwcLister:                  vwcInformer.Lister(),
+		cpolLister:                 cpolInformer.Lister(),
+		polLister:                  polInformer.Lister(),
+		secretLister:               secretInformer.Lister(),
+		configMapLister:            configMapInformer.Lister(),
+		kyvernoConfigurationLister: kyvernoConfigurationInformer.Lister(),
+		leaseLister:                leaseInformer.Lister(),
+		clusterroleLister:          clusterroleInformer.Lister(),
+		queue:                      queue,
+		server:                     server,
+		defaultTimeout:             defaultTimeout,
+		servicePort:                servicePort,
+		autoUpdateWebhooks:         autoUpdateWebhooks,
+		admissionReports:           admissionReports,
+		perPolicyWebhooks:          perPolicyWebhooks,
+		matchConditions:            matchConditions,
+		runtime:                    runtime,
 		policyState: map[string]sets.Set[string]{
 			config.MutatingWebhookConfigurationName:   sets.New[string](),
 			config.ValidatingWebhookConfigurationName: sets.New[string](),
 		},
+		conditionState: map[string]map[string][]matchCondition{},
 	}
 	controllerutils.AddDefaultEventHandlers(logger, mwcInformer.Informer(), queue)
 	controllerutils.AddDefaultEventHandlers(logger, vwcInformer.Informer(), queue)
@@ -191,6 +209,24 @@ func NewController(
 			}
 		},
 	)
+	controllerutils.AddEventHandlersT(
+		kyvernoConfigurationInformer.Informer(),
+		func(obj *kyvernov2alpha1.KyvernoConfiguration) {
+			if obj.GetName() == config.KyvernoConfigurationName() {
+				c.enqueueAll()
+			}
+		},
+		func(_, obj *kyvernov2alpha1.KyvernoConfiguration) {
+			if obj.GetName() == config.KyvernoConfigurationName() {
+				c.enqueueAll()
+			}
+		},
+		func(obj *kyvernov2alpha1.KyvernoConfiguration) {
+			if obj.GetName() == config.KyvernoConfigurationName() {
+				c.enqueueAll()
+			}
+		},
+	)
 	controllerutils.AddEventHandlers(
 		cpolInformer.Informer(),
 		func(interface{}) { c.enqueueResourceWebhooks(0) },
@@ -295,6 +331,11 @@ func (c *controller) enqueueVerifyWebhook() {
 
 func (c *controller) loadConfig() config.Configuration {
 	cfg := config.NewDefaultConfiguration()
+	if kyvernoConfiguration, err := c.kyvernoConfigurationLister.Get(config.KyvernoConfigurationName()); err == nil {
+		if errs := configutils.Load(cfg, kyvernoConfiguration, configutils.Admission); len(errs) == 0 {
+			return cfg
+		}
+	}
 	cm, err := c.configMapLister.ConfigMaps(config.KyvernoNamespace()).Get(config.KyvernoConfigMapName())
 	if err == nil {
 		cfg.Load(cm)
@@ -319,6 +360,22 @@ func (c *controller) recordPolicyState(webhookConfigurationName string, policies
 	}
 }
 
+func (c *controller) recordConditionState(webhookConfigurationName string, conditions map[string][]matchCondition) {
+	if !c.matchConditions {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.conditionState[webhookConfigurationName] = conditions
+}
+
+// getConditionState returns the match conditions of the webhooks in the configuration, nil if they are not managed
+func (c *controller) getConditionState(webhookConfigurationName string) map[string][]matchCondition {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.conditionState[webhookConfigurationName]
+}
+
 func (c *controller) clientConfig(caBundle []byte, path string) admissionregistrationv1.WebhookClientConfig {
 	clientConfig := admissionregistrationv1.WebhookClientConfig{
 		CABundle: caBundle,
@@ -376,21 +433,31 @@ func (c *controller) reconcileValidatingWebhookConfiguration(ctx context.Context
 	}
 	observed, err := c.vwcLister.Get(desired.Name)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			_, err := c.vwcClient.Create(ctx, desired, metav1.CreateOptions{})
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := c.vwcClient.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else {
+		if !autoUpdateWebhooks {
+			return nil
+		}
+		_, err = controllerutils.Update(ctx, observed, c.vwcClient, func(w *admissionregistrationv1.ValidatingWebhookConfiguration) error {
+			w.Labels = desired.Labels
+			w.OwnerReferences = desired.OwnerReferences
+			w.Webhooks = desired.Webhooks
+			return nil
+		})
+		if err != nil {
 			return err
 		}
-		return err
 	}
-	if !autoUpdateWebhooks {
-		return nil
+	patch, err := c.buildMatchConditionsPatch(desired.Name, validatingWebhookNames(desired)...)
+	if err != nil || patch == nil {
+		return err
 	}
-	_, err = controllerutils.Update(ctx, observed, c.vwcClient, func(w *admissionregistrationv1.ValidatingWebhookConfiguration) error {
-		w.Labels = desired.Labels
-		w.OwnerReferences = desired.OwnerReferences
-		w.Webhooks = desired.Webhooks
-		return nil
-	})
+	_, err = c.vwcClient.Patch(ctx, desired.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
 	return err
 }
 
@@ -405,24 +472,61 @@ func (c *controller) reconcileMutatingWebhookConfiguration(ctx context.Context,
 	}
 	observed, err := c.mwcLister.Get(desired.Name)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			_, err := c.mwcClient.Create(ctx, desired, metav1.CreateOptions{})
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if _, err := c.mwcClient.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else {
+		if !autoUpdateWebhooks {
+			return nil
+		}
+		_, err = controllerutils.Update(ctx, observed, c.mwcClient, func(w *admissionregistrationv1.MutatingWebhookConfiguration) error {
+			w.Labels = desired.Labels
+			w.OwnerReferences = desired.OwnerReferences
+			w.Webhooks = desired.Webhooks
+			return nil
+		})
+		if err != nil {
 			return err
 		}
-		return err
 	}
-	if !autoUpdateWebhooks {
-		return nil
+	patch, err := c.buildMatchConditionsPatch(desired.Name, mutatingWebhookNames(desired)...)
+	if err != nil || patch == nil {
+		return err
 	}
-	_, err = controllerutils.Update(ctx, observed, c.mwcClient, func(w *admissionregistrationv1.MutatingWebhookConfiguration) error {
-		w.Labels = desired.Labels
-		w.OwnerReferences = desired.OwnerReferences
-		w.Webhooks = desired.Webhooks
-		return nil
-	})
+	_, err = c.mwcClient.Patch(ctx, desired.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
 	return err
 }
 
+// buildMatchConditionsPatch returns the patch setting the match conditions of the webhooks, nil if they are not managed.
+// The field is not part of the api types we build with and is dropped every time the webhook configuration is updated,
+// patching is a no-op when conditions didn't change.
+func (c *controller) buildMatchConditionsPatch(webhookConfigurationName string, webhooks ...string) ([]byte, error) {
+	conditions := c.getConditionState(webhookConfigurationName)
+	if conditions == nil || len(webhooks) == 0 {
+		return nil, nil
+	}
+	return matchConditionsPatch(conditions, webhooks...)
+}
+
+func validatingWebhookNames(config *admissionregistrationv1.ValidatingWebhookConfiguration) []string {
+	var names []string
+	for _, webhook := range config.Webhooks {
+		names = append(names, webhook.Name)
+	}
+	return names
+}
+
+func mutatingWebhookNames(config *admissionregistrationv1.MutatingWebhookConfiguration) []string {
+	var names []string
+	for _, webhook := range config.Webhooks {
+		names = append(names, webhook.Name)
+	}
+	return names
+}
+
 func (c *controller) updatePolicyStatuses(ctx context.Context) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -444,6 +548,10 @@ func (c *controller) updatePolicyStatuses(ctx context.Context) error {
 		}
 		status := policy.GetStatus()
 		status.SetReady(ready)
+		// idempotency is verified again by the admission webhook once the policy changed
+		if condition := meta.FindStatusCondition(status.Conditions, kyvernov1.PolicyConditionIdempotent); condition != nil && condition.ObservedGeneration != policy.GetGeneration() {
+			meta.RemoveStatusCondition(&status.Conditions, kyvernov1.PolicyConditionIdempotent)
+		}
 		status.Autogen.Rules = nil
 		rules := autogen.ComputeRules(policy)
 		setRuleCount(rules, status)
@@ -620,71 +728,47 @@ func (c *controller) buildResourceMutatingWebhookConfiguration(caBundle []byte)
 		Webhooks:   []admissionregistrationv1.MutatingWebhook{},
 	}
 	if c.watchdogCheck() {
-		ignore := newWebhook(c.defaultTimeout, ignore)
-		fail := newWebhook(c.defaultTimeout, fail)
 		policies, err := c.getAllPolicies()
 		if err != nil {
 			return nil, err
 		}
 		c.recordPolicyState(config.MutatingWebhookConfigurationName, policies...)
-		// TODO: shouldn't be per failure policy, depending of the policy/rules that apply ?
-		if hasWildcard(policies...) {
-			ignore.setWildcard()
-			fail.setWildcard()
-		} else {
-			for _, p := range policies {
-				spec := p.GetSpec()
-				if spec.HasMutate() || spec.HasVerifyImages() {
-					if spec.GetFailurePolicy() == kyvernov1.Ignore {
-						c.mergeWebhook(ignore, p, false)
-					} else {
-						c.mergeWebhook(fail, p, false)
-					}
-				}
-			}
-		}
 		cfg := c.loadConfig()
+		webhooks := c.buildResourceWebhooks(cfg, policies, false, func(spec *kyvernov1.Spec) bool {
+			return spec.HasMutate() || spec.HasVerifyImages()
+		})
+		conditions := map[string][]matchCondition{}
 		webhookCfg := config.WebhookConfig{}
 		webhookCfgs := cfg.GetWebhooks()
 		if len(webhookCfgs) > 0 {
 			webhookCfg = webhookCfgs[0]
 		}
-		if !ignore.isEmpty() {
-			result.Webhooks = append(
-				result.Webhooks,
-				admissionregistrationv1.MutatingWebhook{
-					Name:                    config.MutatingWebhookName + "-ignore",
-					ClientConfig:            c.clientConfig(caBundle, config.MutatingWebhookServicePath+"/ignore"),
-					Rules:                   ignore.buildRulesWithOperations(admissionregistrationv1.Create, admissionregistrationv1.Update),
-					FailurePolicy:           &ignore.failurePolicy,
-					SideEffects:             &noneOnDryRun,
-					AdmissionReviewVersions: []string{"v1"},
-					NamespaceSelector:       webhookCfg.NamespaceSelector,
-					ObjectSelector:          webhookCfg.ObjectSelector,
-					TimeoutSeconds:          &ignore.maxWebhookTimeout,
-					ReinvocationPolicy:      &ifNeeded,
-				},
-			)
-		}
-		if !fail.isEmpty() {
+		for _, wh := range webhooks {
+			if wh.isEmpty() {
+				continue
+			}
+			namespaceSelector, objectSelector := wh.selectors(webhookCfg.NamespaceSelector, webhookCfg.ObjectSelector)
+			conditions[config.MutatingWebhookName+wh.nameSuffix()] = wh.matchConditions()
 			result.Webhooks = append(
 				result.Webhooks,
 				admissionregistrationv1.MutatingWebhook{
-					Name:                    config.MutatingWebhookName + "-fail",
-					ClientConfig:            c.clientConfig(caBundle, config.MutatingWebhookServicePath+"/fail"),
-					Rules:                   fail.buildRulesWithOperations(admissionregistrationv1.Create, admissionregistrationv1.Update),
-					FailurePolicy:           &fail.failurePolicy,
+					Name:                    config.MutatingWebhookName + wh.nameSuffix(),
+					ClientConfig:            c.clientConfig(caBundle, config.MutatingWebhookServicePath+wh.pathSuffix()),
+					Rules:                   wh.buildRulesWithOperations(admissionregistrationv1.Create, admissionregistrationv1.Update),
+					FailurePolicy:           &wh.failurePolicy,
 					SideEffects:             &noneOnDryRun,
 					AdmissionReviewVersions: []string{"v1"},
-					NamespaceSelector:       webhookCfg.NamespaceSelector,
-					ObjectSelector:          webhookCfg.ObjectSelector,
-					TimeoutSeconds:          &fail.maxWebhookTimeout,
+					NamespaceSelector:       namespaceSelector,
+					ObjectSelector:          objectSelector,
+					TimeoutSeconds:          &wh.maxWebhookTimeout,
 					ReinvocationPolicy:      &ifNeeded,
 				},
 			)
 		}
+		c.recordConditionState(config.MutatingWebhookConfigurationName, conditions)
 	} else {
 		c.recordPolicyState(config.MutatingWebhookConfigurationName)
+		c.recordConditionState(config.MutatingWebhookConfigurationName, map[string][]matchCondition{})
 	}
 	return &result, nil
 }
@@ -727,30 +811,16 @@ func (c *controller) buildResourceValidatingWebhookConfiguration(caBundle []byte
 		Webhooks:   []admissionregistrationv1.ValidatingWebhook{},
 	}
 	if c.watchdogCheck() {
-		ignore := newWebhook(c.defaultTimeout, ignore)
-		fail := newWebhook(c.defaultTimeout, fail)
 		policies, err := c.getAllPolicies()
 		if err != nil {
 			return nil, err
 		}
 		c.recordPolicyState(config.ValidatingWebhookConfigurationName, policies...)
-		// TODO: shouldn't be per failure policy, depending of the policy/rules that apply ?
-		if hasWildcard(policies...) {
-			ignore.setWildcard()
-			fail.setWildcard()
-		} else {
-			for _, p := range policies {
-				spec := p.GetSpec()
-				if spec.HasValidate() || spec.HasGenerate() || spec.HasMutate() || spec.HasImagesValidationChecks() || spec.HasYAMLSignatureVerify() {
-					if spec.GetFailurePolicy() == kyvernov1.Ignore {
-						c.mergeWebhook(ignore, p, true)
-					} else {
-						c.mergeWebhook(fail, p, true)
-					}
-				}
-			}
-		}
 		cfg := c.loadConfig()
+		webhooks := c.buildResourceWebhooks(cfg, policies, true, func(spec *kyvernov1.Spec) bool {
+			return spec.HasValidate() || spec.HasGenerate() || spec.HasMutate() || spec.HasImagesValidationChecks() || spec.HasYAMLSignatureVerify()
+		})
+		conditions := map[string][]matchCondition{}
 		webhookCfg := config.WebhookConfig{}
 		webhookCfgs := cfg.GetWebhooks()
 		if len(webhookCfgs) > 0 {
@@ -760,44 +830,104 @@ func (c *controller) buildResourceValidatingWebhookConfiguration(caBundle []byte
 		if c.admissionReports {
 			sideEffects = &noneOnDryRun
 		}
-		if !ignore.isEmpty() {
-			result.Webhooks = append(
-				result.Webhooks,
-				admissionregistrationv1.ValidatingWebhook{
-					Name:                    config.ValidatingWebhookName + "-ignore",
-					ClientConfig:            c.clientConfig(caBundle, config.ValidatingWebhookServicePath+"/ignore"),
-					Rules:                   ignore.buildRulesWithOperations(admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete, admissionregistrationv1.Connect),
-					FailurePolicy:           &ignore.failurePolicy,
-					SideEffects:             sideEffects,
-					AdmissionReviewVersions: []string{"v1"},
-					NamespaceSelector:       webhookCfg.NamespaceSelector,
-					ObjectSelector:          webhookCfg.ObjectSelector,
-					TimeoutSeconds:          &ignore.maxWebhookTimeout,
-				},
-			)
-		}
-		if !fail.isEmpty() {
+		for _, wh := range webhooks {
+			if wh.isEmpty() {
+				continue
+			}
+			namespaceSelector, objectSelector := wh.selectors(webhookCfg.NamespaceSelector, webhookCfg.ObjectSelector)
+			conditions[config.ValidatingWebhookName+wh.nameSuffix()] = wh.matchConditions()
 			result.Webhooks = append(
 				result.Webhooks,
 				admissionregistrationv1.ValidatingWebhook{
-					Name:                    config.ValidatingWebhookName + "-fail",
-					ClientConfig:            c.clientConfig(caBundle, config.ValidatingWebhookServicePath+"/fail"),
-					Rules:                   fail.buildRulesWithOperations(admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete, admissionregistrationv1.Connect),
-					FailurePolicy:           &fail.failurePolicy,
+					Name:                    config.ValidatingWebhookName + wh.nameSuffix(),
+					ClientConfig:            c.clientConfig(caBundle, config.ValidatingWebhookServicePath+wh.pathSuffix()),
+					Rules:                   wh.buildRulesWithOperations(admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete, admissionregistrationv1.Connect),
+					FailurePolicy:           &wh.failurePolicy,
 					SideEffects:             sideEffects,
 					AdmissionReviewVersions: []string{"v1"},
-					NamespaceSelector:       webhookCfg.NamespaceSelector,
-					ObjectSelector:          webhookCfg.ObjectSelector,
-					TimeoutSeconds:          &fail.maxWebhookTimeout,
+					NamespaceSelector:       namespaceSelector,
+					ObjectSelector:          objectSelector,
+					TimeoutSeconds:          &wh.maxWebhookTimeout,
 				},
 			)
 		}
+		c.recordConditionState(config.ValidatingWebhookConfigurationName, conditions)
 	} else {
 		c.recordPolicyState(config.MutatingWebhookConfigurationName)
+		c.recordConditionState(config.ValidatingWebhookConfigurationName, map[string][]matchCondition{})
 	}
 	return &result, nil
 }
 
+// buildResourceWebhooks aggregates the policies selected by filter into resource webhooks,
+// one per failure policy by default, one per failure policy and policy scope when per policy webhooks are enabled
+func (c *controller) buildResourceWebhooks(cfg config.Configuration, policies []kyvernov1.PolicyInterface, updateValidate bool, filter func(*kyvernov1.Spec) bool) []*webhook {
+	merge := func(dst *webhook, policy kyvernov1.PolicyInterface) {
+		c.mergeWebhook(dst, policy, updateValidate)
+		if c.matchConditions {
+			dst.addCondition(policyCondition(policy, cfg.GetExcludeGroupRole()))
+		}
+	}
+	if !c.perPolicyWebhooks {
+		ignore := newWebhook(c.defaultTimeout, ignore)
+		fail := newWebhook(c.defaultTimeout, fail)
+		// TODO: shouldn't be per failure policy, depending of the policy/rules that apply ?
+		if hasWildcard(policies...) {
+			ignore.setWildcard()
+			fail.setWildcard()
+		} else {
+			for _, p := range policies {
+				spec := p.GetSpec()
+				if filter(spec) {
+					if spec.GetFailurePolicy() == kyvernov1.Ignore {
+						merge(ignore, p)
+					} else {
+						merge(fail, p)
+					}
+				}
+			}
+		}
+		return []*webhook{ignore, fail}
+	}
+	webhooks := map[string]*webhook{}
+	wildcards := sets.New[string]()
+	for _, p := range policies {
+		spec := p.GetSpec()
+		if !filter(spec) {
+			continue
+		}
+		failurePolicy := fail
+		if spec.GetFailurePolicy() == kyvernov1.Ignore {
+			failurePolicy = ignore
+		}
+		timeout := c.defaultTimeout
+		if spec.WebhookTimeoutSeconds != nil {
+			timeout = *spec.WebhookTimeoutSeconds
+		}
+		scope := scopeutils.Compute(p)
+		key := string(failurePolicy) + "/" + scope.ID()
+		dst, ok := webhooks[key]
+		if !ok {
+			dst = newScopedWebhook(timeout, failurePolicy, scope)
+			webhooks[key] = dst
+		} else if dst.maxWebhookTimeout < timeout {
+			dst.maxWebhookTimeout = timeout
+		}
+		merge(dst, p)
+		if hasWildcard(p) {
+			wildcards.Insert(key)
+		}
+	}
+	var result []*webhook
+	for _, key := range sets.List(sets.KeySet(webhooks)) {
+		if wildcards.Has(key) {
+			webhooks[key].setWildcard()
+		}
+		result = append(result, webhooks[key])
+	}
+	return result
+}
+
 func (c *controller) getAllPolicies() ([]kyvernov1.PolicyInterface, error) {
 	var policies []kyvernov1.PolicyInterface
 	if cpols, err := c.cpolLister.List(labels.Everything()); err != nil {
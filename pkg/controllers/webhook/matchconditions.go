@@ -0,0 +1,355 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/autogen"
+	apiutils "github.com/kyverno/kyverno/pkg/utils/api"
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// matchConditionName is the name of the match condition set on resource webhooks
+	matchConditionName = "kyverno.io/policies"
+	// maxMatchConditionLength bounds the size of the generated expression, the webhook is not filtered above it
+	maxMatchConditionLength = 8192
+	serviceAccountPrefix    = "system:serviceaccount:"
+)
+
+// matchCondition mirrors admissionregistrationv1.MatchCondition, it was added in kubernetes 1.27
+// and is not available in the api version we depend on, it is set on webhooks with a json patch
+type matchCondition struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+var (
+	variableRegex = regexp.MustCompile(`^\{\{\s*(.+?)\s*\}\}$`)
+	labelRegex    = regexp.MustCompile(`^request\.object\.metadata\.labels\.(?:"([^"]+)"|([A-Za-z0-9_]+))$`)
+)
+
+// policyCondition returns a CEL expression evaluating to true for every admission request the policy can apply to.
+// It only translates simple, side effect free, match and exclude blocks and preconditions, the returned expression
+// is always a superset of the requests the policy applies to. It returns false when no expression can be derived.
+func policyCondition(policy kyvernov1.PolicyInterface, excludeGroupRole []string) (string, bool) {
+	var conditions []string
+	for _, rule := range autogen.ComputeRules(policy) {
+		// generate and mutate existing rules also process requests not matching the rule
+		if rule.HasGenerate() || rule.IsMutateExisting() {
+			return "", false
+		}
+		condition, ok := ruleCondition(rule, excludeGroupRole)
+		if !ok {
+			return "", false
+		}
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return "", false
+	}
+	return or(conditions...), true
+}
+
+func ruleCondition(rule kyvernov1.Rule, excludeGroupRole []string) (string, bool) {
+	var conditions []string
+	if condition, ok := matchUserCondition(rule.MatchResources, excludeGroupRole); ok {
+		conditions = append(conditions, condition)
+	}
+	if condition, ok := excludeUserCondition(rule.ExcludeResources); ok {
+		conditions = append(conditions, "!("+condition+")")
+	}
+	if condition, ok := preconditionsCondition(rule); ok {
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return "", false
+	}
+	return and(conditions...), true
+}
+
+// matchUserCondition returns an expression matching a superset of the users selected by the match block
+func matchUserCondition(match kyvernov1.MatchResources, excludeGroupRole []string) (string, bool) {
+	if len(match.Any) > 0 {
+		var conditions []string
+		for _, filter := range match.Any {
+			condition, ok := subjectsCondition(filter.Subjects, false, excludeGroupRole)
+			if !ok {
+				return "", false
+			}
+			conditions = append(conditions, condition)
+		}
+		return or(conditions...), true
+	}
+	if len(match.All) > 0 {
+		var conditions []string
+		for _, filter := range match.All {
+			if condition, ok := subjectsCondition(filter.Subjects, false, excludeGroupRole); ok {
+				conditions = append(conditions, condition)
+			}
+		}
+		if len(conditions) == 0 {
+			return "", false
+		}
+		return and(conditions...), true
+	}
+	return subjectsCondition(match.Subjects, false, excludeGroupRole)
+}
+
+// excludeUserCondition returns an expression matching a subset of the requests excluded by the exclude block
+func excludeUserCondition(exclude kyvernov1.MatchResources) (string, bool) {
+	// a filter can only be translated if it selects users and nothing else
+	filterCondition := func(filter kyvernov1.ResourceFilter) (string, bool) {
+		if len(filter.Roles) > 0 || len(filter.ClusterRoles) > 0 || !filter.ResourceDescription.IsEmpty() {
+			return "", false
+		}
+		return subjectsCondition(filter.Subjects, true, nil)
+	}
+	if len(exclude.Any) > 0 {
+		var conditions []string
+		for _, filter := range exclude.Any {
+			if condition, ok := filterCondition(filter); ok {
+				conditions = append(conditions, condition)
+			}
+		}
+		if len(conditions) == 0 {
+			return "", false
+		}
+		return or(conditions...), true
+	}
+	if len(exclude.All) > 0 {
+		var conditions []string
+		for _, filter := range exclude.All {
+			condition, ok := filterCondition(filter)
+			if !ok {
+				return "", false
+			}
+			conditions = append(conditions, condition)
+		}
+		return and(conditions...), true
+	}
+	return filterCondition(kyvernov1.ResourceFilter{UserInfo: exclude.UserInfo, ResourceDescription: exclude.ResourceDescription})
+}
+
+// subjectsCondition translates subjects, when strict is false the expression can match more users than the subjects
+func subjectsCondition(subjects []rbacv1.Subject, strict bool, excludeGroupRole []string) (string, bool) {
+	if len(subjects) == 0 {
+		return "", false
+	}
+	// kyverno treats the configured excluded groups as additional subjects
+	for _, group := range excludeGroupRole {
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.GroupKind, Name: group})
+	}
+	var conditions []string
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			name := subject.Namespace + ":" + subject.Name
+			if strict {
+				conditions = append(conditions, "request.userInfo.username == "+quote(serviceAccountPrefix+name))
+			} else {
+				conditions = append(conditions, "request.userInfo.username.endsWith("+quote(name)+")")
+			}
+		case rbacv1.UserKind, rbacv1.GroupKind:
+			// users and groups are matched against both the user name and the user groups
+			conditions = append(conditions, fmt.Sprintf(
+				"(request.userInfo.username == %[1]s || (has(request.userInfo.groups) && %[1]s in request.userInfo.groups))",
+				quote(subject.Name),
+			))
+		}
+	}
+	if len(conditions) == 0 {
+		return "", false
+	}
+	return or(conditions...), true
+}
+
+// preconditionsCondition returns an expression matching a superset of the requests satisfying the rule preconditions
+func preconditionsCondition(rule kyvernov1.Rule) (string, bool) {
+	if rule.RawAnyAllConditions == nil {
+		return "", false
+	}
+	conditions, err := apiutils.ApiextensionsJsonToKyvernoConditions(rule.GetAnyAllConditions())
+	if err != nil {
+		return "", false
+	}
+	translateAll := func(conditions []kyvernov1.Condition) (string, bool) {
+		var translated []string
+		for _, condition := range conditions {
+			if expression, ok := conditionExpression(condition); ok {
+				translated = append(translated, expression)
+			}
+		}
+		if len(translated) == 0 {
+			return "", false
+		}
+		return and(translated...), true
+	}
+	switch typed := conditions.(type) {
+	case kyvernov1.AnyAllConditions:
+		var translated []string
+		if len(typed.AnyConditions) > 0 {
+			var anyOf []string
+			for _, condition := range typed.AnyConditions {
+				expression, ok := conditionExpression(condition)
+				if !ok {
+					anyOf = nil
+					break
+				}
+				anyOf = append(anyOf, expression)
+			}
+			if len(anyOf) > 0 {
+				translated = append(translated, or(anyOf...))
+			}
+		}
+		if expression, ok := translateAll(typed.AllConditions); ok {
+			translated = append(translated, expression)
+		}
+		if len(translated) == 0 {
+			return "", false
+		}
+		return and(translated...), true
+	case []kyvernov1.Condition:
+		return translateAll(typed)
+	}
+	return "", false
+}
+
+// conditionExpression translates a single precondition operating on the request operation, user name,
+// namespace or object labels, with literal values
+func conditionExpression(condition kyvernov1.Condition) (string, bool) {
+	key, ok := condition.GetKey().(string)
+	if !ok {
+		return "", false
+	}
+	match := variableRegex.FindStringSubmatch(key)
+	if match == nil {
+		return "", false
+	}
+	var field, label string
+	switch variable := match[1]; variable {
+	case "request.operation", "request.namespace", "request.userInfo.username":
+		field = variable
+	default:
+		labelMatch := labelRegex.FindStringSubmatch(variable)
+		if labelMatch == nil {
+			return "", false
+		}
+		label = quote(labelMatch[1] + labelMatch[2])
+		field = "object.metadata.labels[" + label + "]"
+	}
+	var expression string
+	switch condition.Operator {
+	case kyvernov1.ConditionOperators["Equals"], kyvernov1.ConditionOperators["Equal"]:
+		value, ok := literal(condition.GetValue())
+		if !ok {
+			return "", false
+		}
+		expression = field + " == " + value
+	case kyvernov1.ConditionOperators["NotEquals"], kyvernov1.ConditionOperators["NotEqual"]:
+		value, ok := literal(condition.GetValue())
+		if !ok {
+			return "", false
+		}
+		expression = field + " != " + value
+	case kyvernov1.ConditionOperators["In"], kyvernov1.ConditionOperators["AnyIn"], kyvernov1.ConditionOperators["AllIn"]:
+		values, ok := literals(condition.GetValue())
+		if !ok {
+			return "", false
+		}
+		expression = field + " in " + values
+	case kyvernov1.ConditionOperators["NotIn"], kyvernov1.ConditionOperators["AnyNotIn"], kyvernov1.ConditionOperators["AllNotIn"]:
+		values, ok := literals(condition.GetValue())
+		if !ok {
+			return "", false
+		}
+		expression = "!(" + field + " in " + values + ")"
+	default:
+		return "", false
+	}
+	if label != "" {
+		// the variable is not resolved when the object or the label doesn't exist, the rule is not skipped in this case
+		expression = fmt.Sprintf("(object == null || !has(object.metadata.labels) || !(%s in object.metadata.labels) || %s)", label, expression)
+	}
+	return expression, true
+}
+
+// matchConditionsPatch builds a json patch setting the match conditions of the webhooks, in order,
+// it fails if the webhooks changed in the meantime
+func matchConditionsPatch(conditions map[string][]matchCondition, webhooks ...string) ([]byte, error) {
+	var patch []map[string]interface{}
+	for i, name := range webhooks {
+		path := fmt.Sprintf("/webhooks/%d", i)
+		matchConditions := conditions[name]
+		if matchConditions == nil {
+			matchConditions = []matchCondition{}
+		}
+		patch = append(
+			patch,
+			map[string]interface{}{"op": "test", "path": path + "/name", "value": name},
+			map[string]interface{}{"op": "add", "path": path + "/matchConditions", "value": matchConditions},
+		)
+	}
+	return json.Marshal(patch)
+}
+
+// literal translates a plain string value, other values are not compared as strings by kyverno operators
+// (the fields translated are strings and never equal a boolean or a number), and strings parsing as
+// quantities or durations are compared by value (1Gi equals 1024Mi)
+func literal(value interface{}) (string, bool) {
+	s, ok := value.(string)
+	if !ok || strings.Contains(s, "{{") || wildcard.ContainsWildcard(s) {
+		return "", false
+	}
+	if _, err := resource.ParseQuantity(s); err == nil {
+		return "", false
+	}
+	if _, err := time.ParseDuration(s); err == nil {
+		return "", false
+	}
+	return quote(s), true
+}
+
+func literals(value interface{}) (string, bool) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+	var quoted []string
+	for _, value := range values {
+		s, ok := literal(value)
+		if !ok {
+			return "", false
+		}
+		quoted = append(quoted, s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]", true
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
+
+func and(conditions ...string) string {
+	return join(" && ", conditions...)
+}
+
+func or(conditions ...string) string {
+	return join(" || ", conditions...)
+}
+
+func join(operator string, conditions ...string) string {
+	conditions = sets.List(sets.New(conditions...))
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "(" + strings.Join(conditions, operator) + ")"
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	kyverno "github.com/kyverno/kyverno/api/kyverno/v1"
+	"gotest.tools/assert"
+)
+
+func Test_policyCondition(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+		wantOk bool
+	}{{
+		name: "preconditions",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"preconditions": {
+					"all": [
+						{"key": "{{ request.operation }}", "operator": "AnyIn", "value": ["CREATE", "UPDATE"]},
+						{"key": "{{ request.object.metadata.labels.\"app.kubernetes.io/name\" }}", "operator": "Equals", "value": "web"},
+						{"key": "{{ request.object.data.foo }}", "operator": "Equals", "value": "bar"}
+					]
+				},
+				"validate": {"message": "message", "pattern": {"data": {"foo": "?*"}}}
+			}]
+		}`,
+		want:   `((object == null || !has(object.metadata.labels) || !("app.kubernetes.io/name" in object.metadata.labels) || object.metadata.labels["app.kubernetes.io/name"] == "web") && request.operation in ["CREATE", "UPDATE"])`,
+		wantOk: true,
+	}, {
+		name: "users",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}, "subjects": [{"kind": "Group", "name": "developers"}]}]},
+				"exclude": {"any": [
+					{"subjects": [{"kind": "ServiceAccount", "namespace": "ci", "name": "deployer"}]},
+					{"resources": {"namespaces": ["kube-system"]}}
+				]},
+				"validate": {"message": "message", "pattern": {"data": {"foo": "?*"}}}
+			}]
+		}`,
+		want:   `(!(request.userInfo.username == "system:serviceaccount:ci:deployer") && (request.userInfo.username == "developers" || (has(request.userInfo.groups) && "developers" in request.userInfo.groups)))`,
+		wantOk: true,
+	}, {
+		name: "any precondition not translated",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"preconditions": {"any": [
+					{"key": "{{ request.operation }}", "operator": "Equals", "value": "CREATE"},
+					{"key": "{{ request.object.data.foo }}", "operator": "Equals", "value": "bar"}
+				]},
+				"validate": {"message": "message", "pattern": {"data": {"foo": "?*"}}}
+			}]
+		}`,
+	}, {
+		name: "wildcard value",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"preconditions": {"all": [{"key": "{{ request.userInfo.username }}", "operator": "NotEquals", "value": "system:*"}]},
+				"validate": {"message": "message", "pattern": {"data": {"foo": "?*"}}}
+			}]
+		}`,
+	}, {
+		name: "quantity, duration and boolean values",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"preconditions": {"all": [
+					{"key": "{{ request.object.metadata.labels.size }}", "operator": "Equals", "value": "1Gi"},
+					{"key": "{{ request.object.metadata.labels.timeout }}", "operator": "Equals", "value": "30s"},
+					{"key": "{{ request.object.metadata.labels.replicas }}", "operator": "AnyIn", "value": ["web", "3"]},
+					{"key": "{{ request.object.metadata.labels.enabled }}", "operator": "Equals", "value": true}
+				]},
+				"validate": {"message": "message", "pattern": {"data": {"foo": "?*"}}}
+			}]
+		}`,
+	}, {
+		name: "generate",
+		policy: `{
+			"rules": [{
+				"name": "generate",
+				"match": {"any": [{"resources": {"kinds": ["Namespace"]}}]},
+				"preconditions": {"all": [{"key": "{{ request.operation }}", "operator": "Equals", "value": "CREATE"}]},
+				"generate": {"kind": "ConfigMap", "name": "test", "namespace": "{{ request.object.metadata.name }}", "data": {}}
+			}]
+		}`,
+	}, {
+		name: "one rule not translated",
+		policy: `{
+			"rules": [{
+				"name": "create",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"preconditions": {"all": [{"key": "{{ request.operation }}", "operator": "Equals", "value": "CREATE"}]},
+				"validate": {"message": "message", "pattern": {"data": {"foo": "?*"}}}
+			}, {
+				"name": "all",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"validate": {"message": "message", "pattern": {"data": {"bar": "?*"}}}
+			}]
+		}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &kyverno.ClusterPolicy{}
+			assert.NilError(t, json.Unmarshal([]byte(tt.policy), &policy.Spec))
+			got, ok := policyCondition(policy, nil)
+			assert.Equal(t, ok, tt.wantOk)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func Test_webhook_matchConditions(t *testing.T) {
+	wh := newWebhook(DefaultWebhookTimeout, fail)
+	assert.Assert(t, wh.matchConditions() == nil)
+	wh.addCondition(`request.operation == "CREATE"`, true)
+	wh.addCondition(`request.operation == "DELETE"`, true)
+	assert.DeepEqual(t, wh.matchConditions(), []matchCondition{{
+		Name:       matchConditionName,
+		Expression: `(request.operation == "CREATE" || request.operation == "DELETE")`,
+	}})
+	wh.addCondition("", false)
+	assert.Assert(t, wh.matchConditions() == nil)
+
+	patch, err := matchConditionsPatch(map[string][]matchCondition{"b": {{Name: "n", Expression: "true"}}}, "a", "b")
+	assert.NilError(t, err)
+	assert.Equal(t, string(patch), `[`+
+		`{"op":"test","path":"/webhooks/0/name","value":"a"},`+
+		`{"op":"add","path":"/webhooks/0/matchConditions","value":[]},`+
+		`{"op":"test","path":"/webhooks/1/name","value":"b"},`+
+		`{"op":"add","path":"/webhooks/1/matchConditions","value":[{"name":"n","expression":"true"}]}]`)
+}
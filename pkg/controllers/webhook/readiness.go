@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	admissionregistrationv1listers "k8s.io/client-go/listers/admissionregistration/v1"
+)
+
+// CheckResourceWebhooks returns a readiness check failing until the resource webhook configurations
+// are registered with a CA bundle and rules for each of their webhooks
+func CheckResourceWebhooks(
+	mwcLister admissionregistrationv1listers.MutatingWebhookConfigurationLister,
+	vwcLister admissionregistrationv1listers.ValidatingWebhookConfigurationLister,
+) func() error {
+	return func() error {
+		mwc, err := mwcLister.Get(config.MutatingWebhookConfigurationName)
+		if err != nil {
+			return err
+		}
+		for _, webhook := range mwc.Webhooks {
+			if err := checkWebhook(webhook.Name, webhook.ClientConfig, webhook.Rules); err != nil {
+				return err
+			}
+		}
+		vwc, err := vwcLister.Get(config.ValidatingWebhookConfigurationName)
+		if err != nil {
+			return err
+		}
+		for _, webhook := range vwc.Webhooks {
+			if err := checkWebhook(webhook.Name, webhook.ClientConfig, webhook.Rules); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func checkWebhook(name string, clientConfig admissionregistrationv1.WebhookClientConfig, rules []admissionregistrationv1.RuleWithOperations) error {
+	if len(clientConfig.CABundle) == 0 {
+		return fmt.Errorf("webhook %s has no CA bundle", name)
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("webhook %s has no rules", name)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/kyverno/kyverno/pkg/config"
+	"gotest.tools/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_CheckResourceWebhooks(t *testing.T) {
+	rules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{""},
+			APIVersions: []string{"v1"},
+			Resources:   []string{"pods"},
+		},
+	}}
+	caBundle := []byte("ca")
+	tests := []struct {
+		name    string
+		mwc     *admissionregistrationv1.MutatingWebhookConfiguration
+		vwc     *admissionregistrationv1.ValidatingWebhookConfiguration
+		wantErr string
+	}{{
+		name:    "not registered",
+		wantErr: `mutatingwebhookconfiguration.admissionregistration.k8s.io "` + config.MutatingWebhookConfigurationName + `" not found`,
+	}, {
+		name: "no policies",
+		mwc:  &admissionregistrationv1.MutatingWebhookConfiguration{},
+		vwc:  &admissionregistrationv1.ValidatingWebhookConfiguration{},
+	}, {
+		name: "ready",
+		mwc: &admissionregistrationv1.MutatingWebhookConfiguration{
+			Webhooks: []admissionregistrationv1.MutatingWebhook{{
+				Name:         "mutate",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle},
+				Rules:        rules,
+			}},
+		},
+		vwc: &admissionregistrationv1.ValidatingWebhookConfiguration{
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name:         "validate",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle},
+				Rules:        rules,
+			}},
+		},
+	}, {
+		name: "missing CA bundle",
+		mwc: &admissionregistrationv1.MutatingWebhookConfiguration{
+			Webhooks: []admissionregistrationv1.MutatingWebhook{{
+				Name:  "mutate",
+				Rules: rules,
+			}},
+		},
+		vwc:     &admissionregistrationv1.ValidatingWebhookConfiguration{},
+		wantErr: "webhook mutate has no CA bundle",
+	}, {
+		name: "missing rules",
+		mwc:  &admissionregistrationv1.MutatingWebhookConfiguration{},
+		vwc: &admissionregistrationv1.ValidatingWebhookConfiguration{
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name:         "validate",
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{CABundle: caBundle},
+			}},
+		},
+		wantErr: "webhook validate has no rules",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			informers := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0).Admissionregistration().V1()
+			if tt.mwc != nil {
+				tt.mwc.ObjectMeta = metav1.ObjectMeta{Name: config.MutatingWebhookConfigurationName}
+				assert.NilError(t, informers.MutatingWebhookConfigurations().Informer().GetIndexer().Add(tt.mwc))
+			}
+			if tt.vwc != nil {
+				tt.vwc.ObjectMeta = metav1.ObjectMeta{Name: config.ValidatingWebhookConfigurationName}
+				assert.NilError(t, informers.ValidatingWebhookConfigurations().Informer().GetIndexer().Add(tt.vwc))
+			}
+			err := CheckResourceWebhooks(informers.MutatingWebhookConfigurations().Lister(), informers.ValidatingWebhookConfigurations().Lister())()
+			if tt.wantErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tt.wantErr)
+			}
+		})
+	}
+}
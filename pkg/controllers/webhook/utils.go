@@ -5,6 +5,7 @@ import (
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/utils"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
 	"golang.org/x/exp/slices"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +19,12 @@ type webhook struct {
 	maxWebhookTimeout int32
 	failurePolicy     admissionregistrationv1.FailurePolicyType
 	rules             map[schema.GroupVersionResource]struct{}
+	// scope is set when the webhook only receives the requests of the policies sharing this scope
+	scope *scopeutils.Scope
+	// conditions holds the match condition of the policies merged in the webhook
+	conditions sets.Set[string]
+	// unconditional is set when at least one policy merged in the webhook has no match condition
+	unconditional bool
 }
 
 func newWebhook(timeout int32, failurePolicy admissionregistrationv1.FailurePolicyType) *webhook {
@@ -25,9 +32,62 @@ func newWebhook(timeout int32, failurePolicy admissionregistrationv1.FailurePoli
 		maxWebhookTimeout: timeout,
 		failurePolicy:     failurePolicy,
 		rules:             map[schema.GroupVersionResource]struct{}{},
+		conditions:        sets.New[string](),
 	}
 }
 
+func newScopedWebhook(timeout int32, failurePolicy admissionregistrationv1.FailurePolicyType, scope scopeutils.Scope) *webhook {
+	wh := newWebhook(timeout, failurePolicy)
+	wh.scope = &scope
+	return wh
+}
+
+// nameSuffix returns the suffix appended to the webhook name
+func (wh *webhook) nameSuffix() string {
+	suffix := "-" + strings.ToLower(string(wh.failurePolicy))
+	if wh.scope != nil {
+		suffix += "-" + wh.scope.ID()
+	}
+	return suffix
+}
+
+// pathSuffix returns the suffix appended to the webhook service path
+func (wh *webhook) pathSuffix() string {
+	suffix := "/" + strings.ToLower(string(wh.failurePolicy))
+	if wh.scope != nil {
+		suffix += "/" + wh.scope.ID()
+	}
+	return suffix
+}
+
+// selectors combines the configured selectors with the ones derived from the webhook scope
+func (wh *webhook) selectors(namespaceSelector, objectSelector *metav1.LabelSelector) (*metav1.LabelSelector, *metav1.LabelSelector) {
+	if wh.scope == nil {
+		return namespaceSelector, objectSelector
+	}
+	return scopeutils.And(namespaceSelector, wh.scope.NamespaceSelector), scopeutils.And(objectSelector, wh.scope.ObjectSelector)
+}
+
+func (wh *webhook) addCondition(condition string, ok bool) {
+	if !ok {
+		wh.unconditional = true
+	} else {
+		wh.conditions.Insert(condition)
+	}
+}
+
+// matchConditions returns the match conditions of the webhook, nil if requests can't be filtered
+func (wh *webhook) matchConditions() []matchCondition {
+	if wh.unconditional || wh.conditions.Len() == 0 {
+		return nil
+	}
+	expression := or(sets.List(wh.conditions)...)
+	if len(expression) > maxMatchConditionLength {
+		return nil
+	}
+	return []matchCondition{{Name: matchConditionName, Expression: expression}}
+}
+
 func (wh *webhook) buildRulesWithOperations(ops ...admissionregistrationv1.OperationType) []admissionregistrationv1.RuleWithOperations {
 	var rules []admissionregistrationv1.RuleWithOperations
 	for gvr := range wh.rules {
@@ -84,6 +144,7 @@ func (wh *webhook) setWildcard() {
 	wh.rules = map[schema.GroupVersionResource]struct{}{
 		{Group: "*", Version: "*", Resource: "*/*"}: {},
 	}
+	wh.unconditional = true
 }
 
 func hasWildcard(policies ...kyvernov1.PolicyInterface) bool {
@@ -6,9 +6,14 @@ import (
 
 	kyverno "github.com/kyverno/kyverno/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/autogen"
+	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	"github.com/kyverno/kyverno/pkg/config"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
 
 	"gotest.tools/assert"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_webhook_isEmpty(t *testing.T) {
@@ -157,3 +162,61 @@ func Test_RuleCount(t *testing.T) {
 	assert.Equal(t, status.RuleCount.Mutate, 1)
 	assert.Equal(t, status.RuleCount.VerifyImages, 2)
 }
+
+func Test_buildResourceWebhooks_PerPolicy(t *testing.T) {
+	newPolicy := func(name string, failurePolicy kyverno.FailurePolicyType, timeout *int32, namespaces ...string) kyverno.PolicyInterface {
+		return &kyverno.ClusterPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kyverno.Spec{
+				FailurePolicy:         &failurePolicy,
+				WebhookTimeoutSeconds: timeout,
+				Rules: []kyverno.Rule{{
+					Name: "check",
+					MatchResources: kyverno.MatchResources{
+						ResourceDescription: kyverno.ResourceDescription{Kinds: []string{"ConfigMap"}, Namespaces: namespaces},
+					},
+					Validation: kyverno.Validation{Message: "message"},
+				}},
+			},
+		}
+	}
+	timeout := int32(15)
+	policies := []kyverno.PolicyInterface{
+		newPolicy("a", kyverno.Fail, nil, "team-a"),
+		newPolicy("b", kyverno.Fail, &timeout, "team-a"),
+		newPolicy("c", kyverno.Fail, nil, "team-b"),
+		newPolicy("d", kyverno.Ignore, nil),
+	}
+	c := controller{discoveryClient: dclient.NewFakeDiscoveryClient(nil), defaultTimeout: DefaultWebhookTimeout}
+	all := func(*kyverno.Spec) bool { return true }
+
+	webhooks := c.buildResourceWebhooks(config.NewDefaultConfiguration(), policies, true, all)
+	assert.Equal(t, len(webhooks), 2)
+	assert.Equal(t, webhooks[0].nameSuffix(), "-ignore")
+	assert.Equal(t, webhooks[1].pathSuffix(), "/fail")
+
+	c.perPolicyWebhooks = true
+	webhooks = c.buildResourceWebhooks(config.NewDefaultConfiguration(), policies, true, all)
+	assert.Equal(t, len(webhooks), 3)
+	byName := map[string]*webhook{}
+	for _, wh := range webhooks {
+		byName[wh.nameSuffix()] = wh
+	}
+	teamA := byName["-fail-"+scopeutils.Compute(policies[0]).ID()]
+	assert.Assert(t, teamA != nil)
+	assert.Equal(t, teamA.maxWebhookTimeout, timeout)
+	assert.Equal(t, teamA.pathSuffix(), "/fail/"+scopeutils.Compute(policies[0]).ID())
+	namespaceSelector, objectSelector := teamA.selectors(nil, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}})
+	assert.DeepEqual(t, namespaceSelector, &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: corev1.LabelMetadataName, Operator: metav1.LabelSelectorOpIn, Values: []string{"team-a"}}},
+	})
+	assert.DeepEqual(t, objectSelector, &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}})
+	teamB := byName["-fail-"+scopeutils.Compute(policies[2]).ID()]
+	assert.Assert(t, teamB != nil)
+	assert.Equal(t, teamB.maxWebhookTimeout, int32(DefaultWebhookTimeout))
+	unscoped := byName["-ignore-"+scopeutils.Unrestricted.ID()]
+	assert.Assert(t, unscoped != nil)
+	namespaceSelector, objectSelector = unscoped.selectors(nil, nil)
+	assert.Assert(t, namespaceSelector == nil)
+	assert.Assert(t, objectSelector == nil)
+}
@@ -20,4 +20,6 @@ type PolicyStats struct {
 	RulesAppliedCount int
 	// RulesErrorCount is the count of rules that with execution errors
 	RulesErrorCount int
+	// RulesUnchangedCount is the count of rules not evaluated again because the update didn't change the fields they reference
+	RulesUnchangedCount int
 }
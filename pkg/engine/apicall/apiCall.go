@@ -17,28 +17,45 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/engine/jmespath"
 	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"github.com/kyverno/kyverno/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type apiCall struct {
-	log     logr.Logger
-	entry   kyvernov1.ContextEntry
-	ctx     goctx.Context
-	jsonCtx context.Interface
-	client  dclient.Interface
+	log       logr.Logger
+	entry     kyvernov1.ContextEntry
+	ctx       goctx.Context
+	jsonCtx   context.Interface
+	client    dclient.Interface
+	transport http.RoundTripper
 }
 
-func New(ctx goctx.Context, entry kyvernov1.ContextEntry, jsonCtx context.Interface, client dclient.Interface, log logr.Logger) (*apiCall, error) {
+// Option is an option to configure an APICall executor.
+type Option = func(*apiCall)
+
+// WithTransport replaces the http transport used to execute service calls.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(a *apiCall) {
+		a.transport = transport
+	}
+}
+
+func New(ctx goctx.Context, entry kyvernov1.ContextEntry, jsonCtx context.Interface, client dclient.Interface, log logr.Logger, options ...Option) (*apiCall, error) {
 	if entry.APICall == nil {
 		return nil, fmt.Errorf("missing APICall in context entry %v", entry)
 	}
 
-	return &apiCall{
+	a := &apiCall{
 		ctx:     ctx,
 		entry:   entry,
 		jsonCtx: jsonCtx,
 		client:  client,
 		log:     log,
-	}, nil
+	}
+	for _, option := range options {
+		option(a)
+	}
+	return a, nil
 }
 
 func (a *apiCall) Execute() ([]byte, error) {
@@ -121,7 +138,7 @@ func (a *apiCall) buildHTTPRequest(service *kyvernov1.ServiceCall) (req *http.Re
 	}()
 
 	if service.Method == "GET" {
-		req, err = http.NewRequest("GET", service.URL, nil)
+		req, err = http.NewRequestWithContext(a.ctx, "GET", service.URL, nil)
 		return
 	}
 
@@ -131,7 +148,7 @@ func (a *apiCall) buildHTTPRequest(service *kyvernov1.ServiceCall) (req *http.Re
 			return nil, dataErr
 		}
 
-		req, err = http.NewRequest("POST", service.URL, data)
+		req, err = http.NewRequestWithContext(a.ctx, "POST", service.URL, data)
 		return
 	}
 
@@ -149,8 +166,22 @@ func (a *apiCall) getToken() string {
 }
 
 func (a *apiCall) buildHTTPClient(service *kyvernov1.ServiceCall) (*http.Client, error) {
+	transport, err := a.buildTransport(service)
+	if err != nil {
+		return nil, err
+	}
+	// spans are created only when called within a trace, the trace context is propagated to the service
+	return &http.Client{
+		Transport: tracing.Transport(transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan)),
+	}, nil
+}
+
+func (a *apiCall) buildTransport(service *kyvernov1.ServiceCall) (http.RoundTripper, error) {
+	if a.transport != nil {
+		return a.transport, nil
+	}
 	if service.CABundle == "" {
-		return http.DefaultClient, nil
+		return http.DefaultTransport, nil
 	}
 
 	caCertPool := x509.NewCertPool()
@@ -158,12 +189,10 @@ func (a *apiCall) buildHTTPClient(service *kyvernov1.ServiceCall) (*http.Client,
 		return nil, fmt.Errorf("failed to parse PEM CA bundle for APICall %s", a.entry.Name)
 	}
 
-	return &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs:    caCertPool,
-				MinVersion: tls.VersionTLS12,
-			},
+	return &http.Transport{
+		TLSClientConfig: &tls.Config{
+			RootCAs:    caCertPool,
+			MinVersion: tls.VersionTLS12,
 		},
 	}, nil
 }
@@ -5,11 +5,15 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"gotest.tools/assert"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -144,3 +148,34 @@ func Test_servicePostRequest(t *testing.T) {
 	expectedResults := `{"images":["https://ghcr.io/tomcat/tomcat:9","https://ghcr.io/vault/vault:v3","https://ghcr.io/busybox/busybox:latest"]}`
 	assert.Equal(t, string(expectedResults)+"\n", string(data))
 }
+
+func Test_serviceRequestTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	var traceParent string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	entry := kyvernov1.ContextEntry{
+		Name: "test",
+		APICall: &kyvernov1.APICall{
+			Service: &kyvernov1.ServiceCall{
+				URL:    s.URL,
+				Method: "GET",
+			},
+		},
+	}
+	provider := sdktrace.NewTracerProvider()
+	defer provider.Shutdown(context.TODO())
+	spanCtx, span := provider.Tracer("test").Start(context.TODO(), "test")
+	defer span.End()
+
+	call, err := New(spanCtx, entry, enginecontext.NewContext(), nil, logging.GlobalLogger())
+	assert.NilError(t, err)
+	_, err = call.Execute()
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(traceParent, span.SpanContext().TraceID().String()), traceParent)
+}
@@ -6,6 +6,7 @@ import (
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/autogen"
+	"github.com/kyverno/kyverno/pkg/config"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	"github.com/kyverno/kyverno/pkg/engine/internal"
 	"github.com/kyverno/kyverno/pkg/engine/utils"
@@ -26,6 +27,29 @@ func (e *engine) applyBackgroundChecks(
 	return e.filterRules(policyContext, policyStartTime)
 }
 
+// filterResourceInfo describes the resource of a policy context to be matched against the resource filters
+// deleted resources are described by the old resource
+func filterResourceInfo(policyContext engineapi.PolicyContext) config.ResourceInfo {
+	resource := policyContext.NewResource()
+	if resource.Object == nil {
+		resource = policyContext.OldResource()
+	}
+	gvk := resource.GroupVersionKind()
+	userInfo := policyContext.AdmissionInfo().AdmissionUserInfo
+	return config.ResourceInfo{
+		Group:           gvk.Group,
+		Version:         gvk.Version,
+		Kind:            gvk.Kind,
+		Subresource:     policyContext.SubResource(),
+		Namespace:       resource.GetNamespace(),
+		Name:            resource.GetName(),
+		Labels:          resource.GetLabels(),
+		NamespaceLabels: policyContext.NamespaceLabels(),
+		Username:        userInfo.Username,
+		UserGroups:      userInfo.Groups,
+	}
+}
+
 func (e *engine) filterRules(
 	policyContext engineapi.PolicyContext,
 	startTime time.Time,
@@ -56,7 +80,7 @@ func (e *engine) filterRules(
 		},
 	}
 
-	if e.configuration.ToFilter(kind, namespace, name) {
+	if e.configuration.ToFilterResource(filterResourceInfo(policyContext)) {
 		logging.WithName("ApplyBackgroundChecks").Info("resource excluded", "kind", kind, "namespace", namespace, "name", name)
 		return resp
 	}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"testing"
+
+	kyvernov1beta1 "github.com/kyverno/kyverno/api/kyverno/v1beta1"
+	"github.com/kyverno/kyverno/pkg/config"
+	"gotest.tools/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_filterResourceInfo(t *testing.T) {
+	resource := func(label string) unstructured.Unstructured {
+		var resource unstructured.Unstructured
+		resource.SetAPIVersion("apps/v1")
+		resource.SetKind("Deployment")
+		resource.SetNamespace("team-a")
+		resource.SetName("app")
+		resource.SetLabels(map[string]string{"app": label})
+		return resource
+	}
+	admissionInfo := kyvernov1beta1.RequestInfo{
+		AdmissionUserInfo: authenticationv1.UserInfo{Username: "alice", Groups: []string{"developers"}},
+	}
+	want := func(label string) config.ResourceInfo {
+		return config.ResourceInfo{
+			Group:           "apps",
+			Version:         "v1",
+			Kind:            "Deployment",
+			Subresource:     "scale",
+			Namespace:       "team-a",
+			Name:            "app",
+			Labels:          map[string]string{"app": label},
+			NamespaceLabels: map[string]string{"team": "a"},
+			Username:        "alice",
+			UserGroups:      []string{"developers"},
+		}
+	}
+	tests := []struct {
+		name        string
+		newResource unstructured.Unstructured
+		oldResource unstructured.Unstructured
+		want        config.ResourceInfo
+	}{{
+		name:        "create",
+		newResource: resource("new"),
+		want:        want("new"),
+	}, {
+		name:        "update",
+		newResource: resource("new"),
+		oldResource: resource("old"),
+		want:        want("new"),
+	}, {
+		name:        "delete",
+		oldResource: resource("old"),
+		want:        want("old"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policyContext := NewPolicyContext().
+				WithResources(tt.newResource, tt.oldResource).
+				WithSubresource("scale").
+				WithNamespaceLabels(map[string]string{"team": "a"}).
+				WithAdmissionInfo(admissionInfo)
+			assert.DeepEqual(t, filterResourceInfo(policyContext), tt.want)
+		})
+	}
+}
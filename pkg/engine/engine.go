@@ -9,6 +9,7 @@ import (
 	"github.com/kyverno/kyverno/pkg/config"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/logging"
 	"github.com/kyverno/kyverno/pkg/registryclient"
 )
 
@@ -18,10 +19,13 @@ type engine struct {
 	rclient           registryclient.Client
 	contextLoader     engineapi.ContextLoaderFactory
 	exceptionSelector engineapi.PolicyExceptionSelector
+	metrics           engineMetrics
+	unchangedOutcomes *unchangedOutcomes
 }
 
 func NewEngine(
 	configuration config.Configuration,
+	metricsConfiguration config.MetricsConfiguration,
 	client dclient.Interface,
 	rclient registryclient.Client,
 	contextLoader engineapi.ContextLoaderFactory,
@@ -33,6 +37,8 @@ func NewEngine(
 		rclient:           rclient,
 		contextLoader:     contextLoader,
 		exceptionSelector: exceptionSelector,
+		metrics:           newEngineMetrics(logging.WithName("Engine"), metricsConfiguration),
+		unchangedOutcomes: newUnchangedOutcomes(unchangedOutcomesSize, unchangedOutcomesTTL),
 	}
 }
 
@@ -40,28 +46,37 @@ func (e *engine) Validate(
 	ctx context.Context,
 	policyContext engineapi.PolicyContext,
 ) *engineapi.EngineResponse {
-	return e.validate(ctx, policyContext)
+	response := e.validate(ctx, policyContext)
+	e.metrics.recordErrors(ctx, response)
+	e.metrics.recordUnchanged(ctx, response)
+	return response
 }
 
 func (e *engine) Mutate(
 	ctx context.Context,
 	policyContext engineapi.PolicyContext,
 ) *engineapi.EngineResponse {
-	return e.mutate(ctx, policyContext)
+	response := e.mutate(ctx, policyContext)
+	e.metrics.recordErrors(ctx, response)
+	return response
 }
 
 func (e *engine) VerifyAndPatchImages(
 	ctx context.Context,
 	policyContext engineapi.PolicyContext,
 ) (*engineapi.EngineResponse, *engineapi.ImageVerificationMetadata) {
-	return e.verifyAndPatchImages(ctx, policyContext)
+	response, ivm := e.verifyAndPatchImages(registryclient.NamespaceContext(ctx, policyContext.Policy().GetNamespace()), policyContext)
+	e.metrics.recordErrors(ctx, response)
+	return response, ivm
 }
 
 func (e *engine) ApplyBackgroundChecks(
 	ctx context.Context,
 	policyContext engineapi.PolicyContext,
 ) *engineapi.EngineResponse {
-	return e.applyBackgroundChecks(ctx, policyContext)
+	response := e.applyBackgroundChecks(ctx, policyContext)
+	e.metrics.recordErrors(ctx, response)
+	return response
 }
 
 func (e *engine) GenerateResponse(
@@ -69,7 +84,9 @@ func (e *engine) GenerateResponse(
 	policyContext engineapi.PolicyContext,
 	gr kyvernov1beta1.UpdateRequest,
 ) *engineapi.EngineResponse {
-	return e.generateResponse(ctx, policyContext, gr)
+	response := e.generateResponse(ctx, policyContext, gr)
+	e.metrics.recordErrors(ctx, response)
+	return response
 }
 
 func (e *engine) ContextLoader(
@@ -79,7 +96,7 @@ func (e *engine) ContextLoader(
 	loader := e.contextLoader(policy, rule)
 	return func(ctx context.Context, contextEntries []kyvernov1.ContextEntry, jsonContext enginecontext.Interface) error {
 		return loader.Load(
-			ctx,
+			registryclient.NamespaceContext(ctx, policy.GetNamespace()),
 			e.client,
 			e.rclient,
 			e.exceptionSelector,
@@ -54,7 +54,7 @@ func (e *engine) filterGenerateRules(
 			},
 		},
 	}
-	if e.configuration.ToFilter(kind, namespace, name) {
+	if e.configuration.ToFilterResource(filterResourceInfo(policyContext)) {
 		logging.WithName("Generate").Info("resource excluded", "kind", kind, "namespace", namespace, "name", name)
 		return resp
 	}
@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"context"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
+)
+
+// NonIdempotentRule is a mutate rule producing patches when applied to its own output
+type NonIdempotentRule struct {
+	Policy kyvernov1.PolicyInterface
+	Rule   string
+}
+
+// NonIdempotentRules applies the mutate rules of the policies again, in order, to the resource they already mutated
+// (the new resource of the policy context) and returns the rules producing further patches.
+// Mutations of existing resources are not verified.
+func NonIdempotentRules(
+	ctx context.Context,
+	eng engineapi.Engine,
+	policyContext *PolicyContext,
+	policies ...kyvernov1.PolicyInterface,
+) ([]NonIdempotentRule, error) {
+	jsonContext := policyContext.JSONContext()
+	jsonContext.Checkpoint()
+	defer jsonContext.Restore()
+	resource := policyContext.NewResource()
+	if err := enginecontext.ReplaceResource(jsonContext, resource.Object); err != nil {
+		return nil, err
+	}
+	var rules []NonIdempotentRule
+	for _, policy := range policies {
+		if !policy.GetSpec().HasMutate() {
+			continue
+		}
+		response := eng.Mutate(ctx, policyContext.WithPolicy(policy))
+		for _, rule := range response.PolicyResponse.Rules {
+			if rule.Status == engineapi.RuleStatusPass && rule.PatchedTarget == nil && len(rule.Patches) > 0 {
+				rules = append(rules, NonIdempotentRule{Policy: policy, Rule: rule.Name})
+			}
+		}
+		policyContext = policyContext.WithNewResource(response.PatchedResource)
+	}
+	return rules, nil
+}
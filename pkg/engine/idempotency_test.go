@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	"gotest.tools/assert"
+)
+
+func Test_NonIdempotentRules(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {"name": "mutate-pods"},
+		"spec": {
+			"rules": [{
+				"name": "add-label",
+				"match": {"any": [{"resources": {"kinds": ["Pod"]}}]},
+				"mutate": {"patchStrategicMerge": {"metadata": {"labels": {"team": "a"}}}}
+			}, {
+				"name": "add-sidecar",
+				"match": {"any": [{"resources": {"kinds": ["Pod"]}}]},
+				"mutate": {"patchesJson6902": "- op: add\n  path: /spec/containers/-\n  value: {\"name\": \"sidecar\", \"image\": \"sidecar\"}"}
+			}]
+		}
+	}`)
+	resourceRaw := []byte(`{
+		"apiVersion": "v1",
+		"kind": "Pod",
+		"metadata": {"name": "test", "namespace": "default"},
+		"spec": {"containers": [{"name": "nginx", "image": "nginx"}]}
+	}`)
+	var policy kyvernov1.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	resource, err := kubeutils.BytesToUnstructured(resourceRaw)
+	assert.NilError(t, err)
+	jsonContext := enginecontext.NewContext()
+	assert.NilError(t, enginecontext.AddResource(jsonContext, resourceRaw))
+	policyContext := &PolicyContext{
+		policy:      &policy,
+		newResource: *resource,
+		jsonContext: jsonContext,
+	}
+	eng := NewEngine(cfg, config.NewDefaultMetricsConfiguration(), nil, registryclient.NewOrDie(), LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()), nil)
+	response := eng.Mutate(context.TODO(), policyContext)
+	assert.Assert(t, response.IsSuccessful())
+
+	rules, err := NonIdempotentRules(context.TODO(), eng, policyContext.WithNewResource(response.PatchedResource), &policy)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, rules, []NonIdempotentRule{{Policy: &policy, Rule: "add-sidecar"}})
+
+	// the context holds the original resource again
+	name, err := jsonContext.Query("request.object.spec.containers[-1].name")
+	assert.NilError(t, err)
+	assert.Equal(t, name, "nginx")
+}
@@ -57,6 +57,10 @@ func (e *engine) verifyAndPatchImages(
 				if len(rule.VerifyImages) == 0 {
 					return
 				}
+				ruleCount := len(resp.PolicyResponse.Rules)
+				defer func() {
+					internal.SetRuleSpanResponses(span, resp.PolicyResponse.Rules[ruleCount:]...)
+				}()
 				startTime := time.Now()
 				kindsInPolicy := append(rule.MatchResources.GetKinds(), rule.ExcludeResources.GetKinds()...)
 				subresourceGVKToAPIResource := GetSubresourceGVKToAPIResourceMap(e.client, kindsInPolicy, policyContext)
@@ -121,11 +125,15 @@ func (e *engine) verifyAndPatchImages(
 					ivm,
 				)
 				for _, imageVerify := range ruleCopy.VerifyImages {
-					for _, r := range iv.Verify(ctx, imageVerify, ruleImages, e.configuration) {
+					verifyStartTime := time.Now()
+					responses := iv.Verify(ctx, imageVerify, ruleImages, e.configuration)
+					e.metrics.recordImageVerification(ctx, policy, rule.Name, verifyStartTime)
+					for _, r := range responses {
 						internal.AddRuleResponse(&resp.PolicyResponse, r, startTime)
 					}
 				}
 			},
+			internal.RuleSpanOptions(policy, rule.Name),
 		)
 
 		if applyRules == kyvernov1.ApplyOne && resp.PolicyResponse.RulesAppliedCount > 0 {
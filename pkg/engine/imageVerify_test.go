@@ -170,9 +170,10 @@ func testVerifyAndPatchImages(
 ) (*engineapi.EngineResponse, *engineapi.ImageVerificationMetadata) {
 	e := NewEngine(
 		cfg,
+		config.NewDefaultMetricsConfiguration(),
 		nil,
 		rclient,
-		LegacyContextLoaderFactory(cmResolver),
+		LegacyContextLoaderFactory(cmResolver, config.NewDefaultMetricsConfiguration()),
 		nil,
 	)
 	return e.VerifyAndPatchImages(
@@ -0,0 +1,42 @@
+package internal
+
+import (
+	"errors"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RuleSpanOptions returns the options used to start a rule span
+func RuleSpanOptions(policy kyvernov1.PolicyInterface, rule string) trace.SpanStartOption {
+	return trace.WithAttributes(
+		tracing.PolicyNamespaceKey.String(policy.GetNamespace()),
+		tracing.PolicyNameKey.String(policy.GetName()),
+		tracing.RuleNameKey.String(rule),
+	)
+}
+
+// SetRuleSpanResponses sets the result attributes of a rule span, the span status is an error
+// if one of the responses is an error
+func SetRuleSpanResponses(span trace.Span, responses ...engineapi.RuleResponse) {
+	if len(responses) == 0 {
+		return
+	}
+	var statuses, messages []string
+	var err error
+	for _, response := range responses {
+		statuses = append(statuses, string(response.Status))
+		messages = append(messages, tracing.StringValue(response.Message))
+		if response.Status == engineapi.RuleStatusError && err == nil {
+			err = errors.New(response.Message)
+		}
+	}
+	span.SetAttributes(
+		tracing.RuleTypeKey.String(string(responses[0].Type)),
+		tracing.RuleStatusKey.StringSlice(statuses),
+		tracing.RuleMessageKey.StringSlice(messages),
+	)
+	tracing.SetSpanStatus(span, err)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/store"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
+	"github.com/kyverno/kyverno/pkg/config"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	"github.com/kyverno/kyverno/pkg/engine/apicall"
 	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
@@ -16,22 +18,30 @@ import (
 	"github.com/kyverno/kyverno/pkg/engine/variables"
 	"github.com/kyverno/kyverno/pkg/logging"
 	"github.com/kyverno/kyverno/pkg/registryclient"
+	"github.com/kyverno/kyverno/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func LegacyContextLoaderFactory(
 	cmResolver engineapi.ConfigmapResolver,
+	metricsConfiguration config.MetricsConfiguration,
 ) engineapi.ContextLoaderFactory {
+	metrics := newContextEntryMetrics(logging.WithName("LegacyContextLoaderFactory"), metricsConfiguration)
 	return func(policy kyvernov1.PolicyInterface, rule kyvernov1.Rule) engineapi.ContextLoader {
 		if store.IsMock() {
 			return &mockContextLoader{
 				logger:     logging.WithName("MockContextLoaderFactory"),
 				policyName: policy.GetName(),
 				ruleName:   rule.Name,
+				cmResolver: cmResolver,
 			}
 		} else {
 			return &contextLoader{
 				logger:     logging.WithName("LegacyContextLoaderFactory"),
 				cmResolver: cmResolver,
+				policy:     policy,
+				ruleName:   rule.Name,
+				metrics:    metrics,
 			}
 		}
 	}
@@ -40,6 +50,9 @@ func LegacyContextLoaderFactory(
 type contextLoader struct {
 	logger     logr.Logger
 	cmResolver engineapi.ConfigmapResolver
+	policy     kyvernov1.PolicyInterface
+	ruleName   string
+	metrics    contextEntryMetrics
 }
 
 func (l *contextLoader) Load(
@@ -51,31 +64,53 @@ func (l *contextLoader) Load(
 	jsonContext enginecontext.Interface,
 ) error {
 	for _, entry := range contextEntries {
-		if entry.ConfigMap != nil {
-			if err := loadConfigMap(ctx, l.logger, entry, jsonContext, l.cmResolver); err != nil {
-				return err
-			}
-		} else if entry.APICall != nil {
-			if err := loadAPIData(ctx, l.logger, entry, jsonContext, client); err != nil {
-				return err
-			}
-		} else if entry.ImageRegistry != nil {
-			if err := loadImageData(ctx, rclient, l.logger, entry, jsonContext); err != nil {
+		startTime := time.Now()
+		err := tracing.ChildSpan1(
+			ctx,
+			"pkg/engine",
+			fmt.Sprintf("CONTEXT ENTRY %s", entry.Name),
+			func(ctx context.Context, span trace.Span) error {
+				err := l.loadEntry(ctx, client, rclient, entry, jsonContext)
+				tracing.SetSpanStatus(span, err)
 				return err
-			}
-		} else if entry.Variable != nil {
-			if err := loadVariable(l.logger, entry, jsonContext); err != nil {
-				return err
-			}
+			},
+			trace.WithAttributes(
+				tracing.ContextEntryNameKey.String(entry.Name),
+				tracing.ContextEntryTypeKey.String(contextEntryType(entry)),
+			),
+		)
+		l.metrics.record(ctx, l.policy, l.ruleName, entry, startTime, err)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+func (l *contextLoader) loadEntry(
+	ctx context.Context,
+	client dclient.Interface,
+	rclient registryclient.Client,
+	entry kyvernov1.ContextEntry,
+	jsonContext enginecontext.Interface,
+) error {
+	if entry.ConfigMap != nil {
+		return loadConfigMap(ctx, l.logger, entry, jsonContext, l.cmResolver)
+	} else if entry.APICall != nil {
+		return loadAPIData(ctx, l.logger, entry, jsonContext, client)
+	} else if entry.ImageRegistry != nil {
+		return loadImageData(ctx, rclient, l.logger, entry, jsonContext)
+	} else if entry.Variable != nil {
+		return loadVariable(l.logger, entry, jsonContext)
+	}
+	return nil
+}
+
 type mockContextLoader struct {
 	logger     logr.Logger
 	policyName string
 	ruleName   string
+	cmResolver engineapi.ConfigmapResolver
 }
 
 func (l *mockContextLoader) Load(
@@ -107,8 +142,12 @@ func (l *mockContextLoader) Load(
 			if err := loadVariable(l.logger, entry, jsonContext); err != nil {
 				return err
 			}
-		} else if entry.APICall != nil && store.IsApiCallAllowed() {
-			if err := loadAPIData(ctx, l.logger, entry, jsonContext, client); err != nil {
+		} else if entry.APICall != nil && (store.IsApiCallAllowed() || store.HasAPICalls()) {
+			if err := loadAPIData(ctx, l.logger, entry, jsonContext, store.GetClient(client), apicall.WithTransport(store.GetServiceTransport())); err != nil {
+				return err
+			}
+		} else if entry.ConfigMap != nil && l.cmResolver != nil {
+			if err := loadConfigMap(ctx, l.logger, entry, jsonContext, l.cmResolver); err != nil {
 				return err
 			}
 		}
@@ -278,8 +317,8 @@ func fetchImageDataMap(ctx context.Context, rclient registryclient.Client, ref s
 	return untyped, nil
 }
 
-func loadAPIData(ctx context.Context, logger logr.Logger, entry kyvernov1.ContextEntry, enginectx enginecontext.Interface, client dclient.Interface) error {
-	executor, err := apicall.New(ctx, entry, enginectx, client, logger)
+func loadAPIData(ctx context.Context, logger logr.Logger, entry kyvernov1.ContextEntry, enginectx enginecontext.Interface, client dclient.Interface, options ...apicall.Option) error {
+	executor, err := apicall.New(ctx, entry, enginectx, client, logger, options...)
 	if err != nil {
 		return fmt.Errorf("failed to initialize APICall: %w", err)
 	}
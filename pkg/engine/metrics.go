@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+)
+
+type engineMetrics struct {
+	configuration       config.MetricsConfiguration
+	imageVerifyDuration syncfloat64.Histogram
+	errorsTotal         syncint64.Counter
+	unchangedTotal      syncint64.Counter
+}
+
+func newEngineMetrics(logger logr.Logger, configuration config.MetricsConfiguration) engineMetrics {
+	meter := global.MeterProvider().Meter(metrics.MeterName)
+	imageVerifyDuration, err := meter.SyncFloat64().Histogram(
+		"kyverno_image_verification_duration_seconds",
+		instrument.WithDescription("can be used to track the latencies (in seconds) associated with the verification of images (signatures and attestations) by verifyImages rules"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_image_verification_duration_seconds")
+	}
+	errorsTotal, err := meter.SyncInt64().Counter(
+		"kyverno_engine_errors",
+		instrument.WithDescription("can be used to track the number of rules that failed to execute with an error, by rule type"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_engine_errors")
+	}
+	unchangedTotal, err := meter.SyncInt64().Counter(
+		"kyverno_policy_rules_unchanged",
+		instrument.WithDescription("can be used to track the number of validate rules not evaluated again because the update didn't change the fields they reference"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_policy_rules_unchanged")
+	}
+	return engineMetrics{
+		configuration:       configuration,
+		imageVerifyDuration: imageVerifyDuration,
+		errorsTotal:         errorsTotal,
+		unchangedTotal:      unchangedTotal,
+	}
+}
+
+// checkNamespace returns true if the metrics of the policy have to be recorded,
+// the namespace filters of the metrics configuration apply to the policy namespace
+func checkNamespace(configuration config.MetricsConfiguration, policy kyvernov1.PolicyInterface) bool {
+	return configuration == nil || configuration.CheckNamespace(policy.GetNamespace())
+}
+
+func policyAttributes(policy kyvernov1.PolicyInterface, ruleName string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("policy_namespace", policy.GetNamespace()),
+		attribute.String("policy_name", policy.GetName()),
+		attribute.String("rule_name", ruleName),
+	}
+}
+
+func (m engineMetrics) recordImageVerification(ctx context.Context, policy kyvernov1.PolicyInterface, ruleName string, startTime time.Time) {
+	if m.imageVerifyDuration != nil && checkNamespace(m.configuration, policy) {
+		m.imageVerifyDuration.Record(ctx, time.Since(startTime).Seconds(), policyAttributes(policy, ruleName)...)
+	}
+}
+
+func (m engineMetrics) recordErrors(ctx context.Context, response *engineapi.EngineResponse) {
+	if m.errorsTotal == nil || response == nil || response.Policy == nil || !checkNamespace(m.configuration, response.Policy) {
+		return
+	}
+	for _, rule := range response.PolicyResponse.Rules {
+		if rule.Status == engineapi.RuleStatusError {
+			attributes := append(policyAttributes(response.Policy, rule.Name), attribute.String("rule_type", string(rule.Type)))
+			m.errorsTotal.Add(ctx, 1, attributes...)
+		}
+	}
+}
+
+func (m engineMetrics) recordUnchanged(ctx context.Context, response *engineapi.EngineResponse) {
+	if m.unchangedTotal == nil || response == nil || response.Policy == nil || response.PolicyResponse.RulesUnchangedCount == 0 || !checkNamespace(m.configuration, response.Policy) {
+		return
+	}
+	attributes := []attribute.KeyValue{
+		attribute.String("policy_namespace", response.Policy.GetNamespace()),
+		attribute.String("policy_name", response.Policy.GetName()),
+	}
+	m.unchangedTotal.Add(ctx, int64(response.PolicyResponse.RulesUnchangedCount), attributes...)
+}
+
+type contextEntryMetrics struct {
+	configuration config.MetricsConfiguration
+	duration      syncfloat64.Histogram
+}
+
+func newContextEntryMetrics(logger logr.Logger, configuration config.MetricsConfiguration) contextEntryMetrics {
+	meter := global.MeterProvider().Meter(metrics.MeterName)
+	duration, err := meter.SyncFloat64().Histogram(
+		"kyverno_context_entry_duration_seconds",
+		instrument.WithDescription("can be used to track the latencies (in seconds) associated with the loading of rule context entries, by entry type (apiCall, configMap, imageRegistry, variable)"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_context_entry_duration_seconds")
+	}
+	return contextEntryMetrics{
+		configuration: configuration,
+		duration:      duration,
+	}
+}
+
+func contextEntryType(entry kyvernov1.ContextEntry) string {
+	switch {
+	case entry.APICall != nil:
+		return "apiCall"
+	case entry.ConfigMap != nil:
+		return "configMap"
+	case entry.ImageRegistry != nil:
+		return "imageRegistry"
+	case entry.Variable != nil:
+		return "variable"
+	default:
+		return "unknown"
+	}
+}
+
+func (m contextEntryMetrics) record(ctx context.Context, policy kyvernov1.PolicyInterface, ruleName string, entry kyvernov1.ContextEntry, startTime time.Time, err error) {
+	if m.duration == nil || policy == nil || !checkNamespace(m.configuration, policy) {
+		return
+	}
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	attributes := append(
+		policyAttributes(policy, ruleName),
+		attribute.String("entry_type", contextEntryType(entry)),
+		attribute.String("entry_name", entry.Name),
+		attribute.String("status", status),
+	)
+	m.duration.Record(ctx, time.Since(startTime).Seconds(), attributes...)
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gotest.tools/assert"
+)
+
+// metricsConfiguration excludes a namespace from metrics
+type metricsConfiguration struct {
+	config.MetricsConfiguration
+	excluded string
+}
+
+func (c metricsConfiguration) CheckNamespace(namespace string) bool {
+	return namespace != c.excluded
+}
+
+func newNamespacedPolicy(namespace string) kyvernov1.PolicyInterface {
+	policy := &kyvernov1.Policy{}
+	policy.SetNamespace(namespace)
+	policy.SetName("test")
+	return policy
+}
+
+// recordedNamespaces returns the policy namespaces of the data points of each metric
+func recordedNamespaces(t *testing.T, reader sdkmetric.Reader) map[string][]string {
+	data, err := reader.Collect(context.Background())
+	assert.NilError(t, err)
+	namespaces := map[string][]string{}
+	for _, scope := range data.ScopeMetrics {
+		for _, metric := range scope.Metrics {
+			var attributes []attribute.Set
+			switch data := metric.Data.(type) {
+			case metricdata.Histogram:
+				for _, point := range data.DataPoints {
+					attributes = append(attributes, point.Attributes)
+				}
+			case metricdata.Sum[int64]:
+				for _, point := range data.DataPoints {
+					attributes = append(attributes, point.Attributes)
+				}
+			}
+			for _, set := range attributes {
+				namespace, _ := set.Value(attribute.Key("policy_namespace"))
+				namespaces[metric.Name] = append(namespaces[metric.Name], namespace.AsString())
+			}
+		}
+	}
+	return namespaces
+}
+
+func Test_EngineMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("kyverno")
+	imageVerifyDuration, err := meter.SyncFloat64().Histogram("kyverno_image_verification_duration_seconds")
+	assert.NilError(t, err)
+	errorsTotal, err := meter.SyncInt64().Counter("kyverno_engine_errors")
+	assert.NilError(t, err)
+	unchangedTotal, err := meter.SyncInt64().Counter("kyverno_policy_rules_unchanged")
+	assert.NilError(t, err)
+	contextEntryDuration, err := meter.SyncFloat64().Histogram("kyverno_context_entry_duration_seconds")
+	assert.NilError(t, err)
+	configuration := metricsConfiguration{excluded: "excluded"}
+	metrics := engineMetrics{
+		configuration:       configuration,
+		imageVerifyDuration: imageVerifyDuration,
+		errorsTotal:         errorsTotal,
+		unchangedTotal:      unchangedTotal,
+	}
+	contextMetrics := contextEntryMetrics{
+		configuration: configuration,
+		duration:      contextEntryDuration,
+	}
+
+	ctx := context.Background()
+	for _, namespace := range []string{"included", "excluded"} {
+		policy := newNamespacedPolicy(namespace)
+		response := &engineapi.EngineResponse{
+			Policy: policy,
+			PolicyResponse: engineapi.PolicyResponse{
+				Rules: []engineapi.RuleResponse{
+					{Name: "error", Type: engineapi.Validation, Status: engineapi.RuleStatusError},
+					{Name: "pass", Type: engineapi.Validation, Status: engineapi.RuleStatusPass},
+				},
+				PolicyStats: engineapi.PolicyStats{
+					RulesUnchangedCount: 1,
+				},
+			},
+		}
+		metrics.recordImageVerification(ctx, policy, "verify", time.Now())
+		metrics.recordErrors(ctx, response)
+		metrics.recordUnchanged(ctx, response)
+		contextMetrics.record(ctx, policy, "rule", kyvernov1.ContextEntry{Name: "entry", Variable: &kyvernov1.Variable{}}, time.Now(), errors.New("error"))
+	}
+
+	assert.DeepEqual(t, recordedNamespaces(t, reader), map[string][]string{
+		"kyverno_image_verification_duration_seconds": {"included"},
+		"kyverno_engine_errors":                       {"included"},
+		"kyverno_policy_rules_unchanged":              {"included"},
+		"kyverno_context_entry_duration_seconds":      {"included"},
+	})
+}
@@ -60,6 +60,10 @@ func (e *engine) mutate(
 			"pkg/engine",
 			fmt.Sprintf("RULE %s", rule.Name),
 			func(ctx context.Context, span trace.Span) {
+				ruleCount := len(resp.PolicyResponse.Rules)
+				defer func() {
+					internal.SetRuleSpanResponses(span, resp.PolicyResponse.Rules[ruleCount:]...)
+				}()
 				logger := logger.WithValues("rule", rule.Name)
 				var excludeResource []string
 				if len(e.configuration.GetExcludeGroupRole()) > 0 {
@@ -160,6 +164,7 @@ func (e *engine) mutate(
 					}
 				}
 			},
+			internal.RuleSpanOptions(policy, rule.Name),
 		)
 		if applyRules == kyvernov1.ApplyOne && resp.PolicyResponse.RulesAppliedCount > 0 {
 			break
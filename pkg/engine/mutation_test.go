@@ -11,6 +11,7 @@ import (
 	"github.com/kyverno/kyverno/cmd/cli/kubectl-kyverno/utils/store"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	client "github.com/kyverno/kyverno/pkg/clients/dclient"
+	"github.com/kyverno/kyverno/pkg/config"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/registryclient"
@@ -29,9 +30,10 @@ func testMutate(
 ) *engineapi.EngineResponse {
 	e := NewEngine(
 		cfg,
+		config.NewDefaultMetricsConfiguration(),
 		client,
 		rclient,
-		LegacyContextLoaderFactory(nil),
+		LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()),
 		nil,
 	)
 	return e.Mutate(
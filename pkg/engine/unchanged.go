@@ -0,0 +1,248 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/anchor"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+const (
+	// unchangedOutcomesSize is the maximum number of stored outcomes, one per rule and set of referenced field values
+	unchangedOutcomesSize = 1000
+	// unchangedOutcomesTTL bounds the time an outcome is reused, it is evaluated again on the next update after that
+	unchangedOutcomesTTL = 10 * time.Minute
+)
+
+var (
+	// objectPathRegex matches variables referencing a field of the admitted object with a plain path
+	objectPathRegex  = regexp.MustCompile(`^request\.object((?:\.(?:[A-Za-z0-9_-]+|"[^"]+"))+)$`)
+	pathElementRegex = regexp.MustCompile(`\.(?:([A-Za-z0-9_-]+)|"([^"]+)")`)
+)
+
+// unchangedOutcomes stores the outcomes of the validate rules of the policies opted in with the
+// AnnotationSkipUnchangedUpdates annotation, by the values of the object fields the rules reference
+type unchangedOutcomes struct {
+	outcomes *cache.LRUExpireCache
+	ttl      time.Duration
+}
+
+func newUnchangedOutcomes(size int, ttl time.Duration) *unchangedOutcomes {
+	return &unchangedOutcomes{
+		outcomes: cache.NewLRUExpireCache(size),
+		ttl:      ttl,
+	}
+}
+
+// get returns a copy of the outcome stored for the key
+func (o *unchangedOutcomes) get(key string) (*engineapi.RuleResponse, bool) {
+	value, ok := o.outcomes.Get(key)
+	if !ok {
+		return nil, false
+	}
+	response := value.(engineapi.RuleResponse)
+	return &response, true
+}
+
+// add stores the outcome of a rule, errors are not stored as they can be transient
+func (o *unchangedOutcomes) add(key string, response *engineapi.RuleResponse) {
+	if response == nil || response.Status == engineapi.RuleStatusError {
+		return
+	}
+	o.outcomes.Add(key, *response, o.ttl)
+}
+
+// unchangedPaths returns the paths of the object fields a validate rule depends on when the policy
+// opted in with the AnnotationSkipUnchangedUpdates annotation
+func unchangedPaths(policyContext engineapi.PolicyContext, rule *kyvernov1.Rule) ([][]string, bool) {
+	if policyContext.Policy().GetAnnotations()[kyvernov1.AnnotationSkipUnchangedUpdates] != "true" {
+		return nil, false
+	}
+	return referencedPaths(rule)
+}
+
+// outcomeKey returns the key of the outcome of a validate rule, computed from the values of the fields it references
+func outcomeKey(policyContext engineapi.PolicyContext, rule *kyvernov1.Rule, paths [][]string) (string, bool) {
+	newResource := policyContext.NewResource()
+	if isEmptyUnstructured(&newResource) {
+		return "", false
+	}
+	values := make([]interface{}, 0, len(paths))
+	for _, path := range paths {
+		value, _, err := unstructured.NestedFieldNoCopy(newResource.Object, path...)
+		if err != nil {
+			return "", false
+		}
+		values = append(values, value)
+	}
+	policy := policyContext.Policy()
+	data, err := json.Marshal(struct {
+		Policy string        `json:"policy"`
+		Rule   string        `json:"rule"`
+		Values []interface{} `json:"values"`
+	}{
+		Policy: policy.GetNamespace() + "/" + policy.GetName() + "@" + policy.GetResourceVersion(),
+		Rule:   rule.Name,
+		Values: values,
+	})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
+
+// skipUnchanged returns true when the update request didn't change any of the given fields,
+// the outcome of the rule for the new object is the same as for the old one.
+func skipUnchanged(policyContext engineapi.PolicyContext, paths [][]string) bool {
+	if !policyContext.AdmissionOperation() {
+		return false
+	}
+	newResource, oldResource := policyContext.NewResource(), policyContext.OldResource()
+	if isEmptyUnstructured(&newResource) || isEmptyUnstructured(&oldResource) {
+		return false
+	}
+	for _, path := range paths {
+		oldValue, _, oldErr := unstructured.NestedFieldNoCopy(oldResource.Object, path...)
+		newValue, _, newErr := unstructured.NestedFieldNoCopy(newResource.Object, path...)
+		if oldErr != nil || newErr != nil || !reflect.DeepEqual(oldValue, newValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// processUnchangedValidationRule returns the previous outcome of a validate rule when the update didn't change
+// the fields it references, it evaluates the rule and stores its outcome otherwise
+func (e *engine) processUnchangedValidationRule(
+	ctx context.Context,
+	log logr.Logger,
+	policyContext engineapi.PolicyContext,
+	rule *kyvernov1.Rule,
+	resp *engineapi.PolicyResponse,
+) *engineapi.RuleResponse {
+	paths, ok := unchangedPaths(policyContext, rule)
+	if !ok {
+		return e.processValidationRule(ctx, log, policyContext, rule)
+	}
+	key, ok := outcomeKey(policyContext, rule, paths)
+	if !ok {
+		return e.processValidationRule(ctx, log, policyContext, rule)
+	}
+	if skipUnchanged(policyContext, paths) {
+		if previous, ok := e.unchangedOutcomes.get(key); ok {
+			log.V(3).Info("reusing the previous outcome of the validation rule, the update didn't change the fields it references", "status", previous.Status)
+			resp.RulesUnchangedCount++
+			return previous
+		}
+	}
+	ruleResp := e.processValidationRule(ctx, log, policyContext, rule)
+	e.unchangedOutcomes.add(key, ruleResp)
+	return ruleResp
+}
+
+// referencedPaths returns the paths of the object fields a validate rule depends on,
+// it returns false if the rule can depend on something else than the object fields
+func referencedPaths(rule *kyvernov1.Rule) ([][]string, bool) {
+	validation := rule.Validation
+	if len(rule.Context) > 0 || validation.PodSecurity != nil || len(validation.ForEachValidation) > 0 || validation.Manifests != nil {
+		return nil, false
+	}
+	var paths [][]string
+	if pattern := validation.GetPattern(); pattern != nil {
+		patternPaths(pattern, nil, &paths)
+	}
+	if anyPattern := validation.GetAnyPattern(); anyPattern != nil {
+		patterns, ok := anyPattern.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		for _, pattern := range patterns {
+			patternPaths(pattern, nil, &paths)
+		}
+	}
+	// the message is part of the outcome, the variables it references are substituted from the object
+	values := []interface{}{rule.GetAnyAllConditions(), validation.GetPattern(), validation.GetAnyPattern(), validation.Message}
+	if validation.Deny != nil {
+		values = append(values, validation.Deny.GetAnyAllConditions())
+	}
+	for _, value := range values {
+		for _, s := range collectStrings(value, nil) {
+			// references point to other fields of the pattern
+			if strings.Contains(s, "$(") {
+				return nil, false
+			}
+			for _, match := range variables.RegexVariables.FindAllStringSubmatch(s, -1) {
+				path, ok := objectPath(match[2])
+				if !ok {
+					return nil, false
+				}
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths, true
+}
+
+// patternPaths collects the deepest paths of the pattern that can be compared as a whole
+func patternPaths(pattern interface{}, path []string, paths *[][]string) {
+	fields, ok := pattern.(map[string]interface{})
+	if !ok || len(fields) == 0 {
+		*paths = append(*paths, path)
+		return
+	}
+	for key, value := range fields {
+		if a := anchor.Parse(key); a != nil {
+			key = a.Key()
+		}
+		if wildcard.ContainsWildcard(key) {
+			*paths = append(*paths, path)
+			continue
+		}
+		patternPaths(value, append(path[:len(path):len(path)], key), paths)
+	}
+}
+
+// objectPath returns the path of a variable in the form {{ request.object.a.b."c" }}
+func objectPath(variable string) ([]string, bool) {
+	variable = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(variable, "{{"), "}}"))
+	match := objectPathRegex.FindStringSubmatch(variable)
+	if match == nil {
+		return nil, false
+	}
+	var path []string
+	for _, element := range pathElementRegex.FindAllStringSubmatch(match[1], -1) {
+		path = append(path, element[1]+element[2])
+	}
+	return path, true
+}
+
+func collectStrings(value interface{}, result []string) []string {
+	switch typed := value.(type) {
+	case string:
+		result = append(result, typed)
+	case map[string]interface{}:
+		for key, value := range typed {
+			result = collectStrings(value, append(result, key))
+		}
+	case []interface{}:
+		for _, value := range typed {
+			result = collectStrings(value, result)
+		}
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/config"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	enginecontext "github.com/kyverno/kyverno/pkg/engine/context"
+	"github.com/kyverno/kyverno/pkg/registryclient"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	"gotest.tools/assert"
+)
+
+func Test_referencedPaths(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		want   [][]string
+		wantOk bool
+	}{{
+		name: "pattern",
+		rule: `{
+			"name": "check",
+			"validate": {"pattern": {"spec": {"=(hostNetwork)": false, "containers": [{"image": "!*:latest"}]}}}
+		}`,
+		want:   [][]string{{"spec", "containers"}, {"spec", "hostNetwork"}},
+		wantOk: true,
+	}, {
+		name: "wildcard key",
+		rule: `{
+			"name": "check",
+			"validate": {"pattern": {"metadata": {"labels": {"app*": "?*"}}}}
+		}`,
+		want:   [][]string{{"metadata", "labels"}},
+		wantOk: true,
+	}, {
+		name: "deny",
+		rule: `{
+			"name": "check",
+			"validate": {"deny": {"conditions": {"any": [
+				{"key": "{{ request.object.metadata.labels.\"app.kubernetes.io/name\" }}", "operator": "Equals", "value": "{{ request.object.spec.replicas }}"}
+			]}}}
+		}`,
+		want:   [][]string{{"metadata", "labels", "app.kubernetes.io/name"}, {"spec", "replicas"}},
+		wantOk: true,
+	}, {
+		name: "request variable",
+		rule: `{
+			"name": "check",
+			"validate": {"deny": {"conditions": {"any": [
+				{"key": "{{ request.userInfo.username }}", "operator": "Equals", "value": "admin"}
+			]}}}
+		}`,
+	}, {
+		name: "function",
+		rule: `{
+			"name": "check",
+			"validate": {"deny": {"conditions": {"any": [
+				{"key": "{{ length(request.object.spec.containers) }}", "operator": "GreaterThan", "value": 2}
+			]}}}
+		}`,
+	}, {
+		name: "context",
+		rule: `{
+			"name": "check",
+			"context": [{"name": "name", "variable": {"value": "test"}}],
+			"validate": {"pattern": {"metadata": {"name": "{{ name }}"}}}
+		}`,
+	}, {
+		name: "message",
+		rule: `{
+			"name": "check",
+			"validate": {"message": "{{ request.object.metadata.name }} is not allowed", "pattern": {"spec": {"hostNetwork": false}}}
+		}`,
+		want:   [][]string{{"metadata", "name"}, {"spec", "hostNetwork"}},
+		wantOk: true,
+	}, {
+		name: "message request variable",
+		rule: `{
+			"name": "check",
+			"validate": {"message": "{{ request.userInfo.username }} is not allowed", "pattern": {"spec": {"hostNetwork": false}}}
+		}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rule kyvernov1.Rule
+			assert.NilError(t, json.Unmarshal([]byte(tt.rule), &rule))
+			got, ok := referencedPaths(&rule)
+			assert.Equal(t, ok, tt.wantOk)
+			if ok {
+				assert.DeepEqual(t, sortedPaths(got), tt.want)
+			}
+		})
+	}
+}
+
+func Test_skipUnchanged(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "disallow-latest",
+			"annotations": {"policies.kyverno.io/skip-unchanged-updates": "true"}
+		},
+		"spec": {
+			"validationFailureAction": "enforce",
+			"rules": [{
+				"name": "check-image",
+				"match": {"any": [{"resources": {"kinds": ["Pod"]}}]},
+				"validate": {"message": "latest tag is not allowed", "pattern": {"spec": {"containers": [{"image": "!*:latest"}]}}}
+			}]
+		}
+	}`)
+	pod := func(image string, phase string) []byte {
+		return []byte(`{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "test", "namespace": "default"},
+			"spec": {"containers": [{"name": "nginx", "image": "` + image + `"}]},
+			"status": {"phase": "` + phase + `"}
+		}`)
+	}
+	tests := []struct {
+		name          string
+		annotated     bool
+		oldObject     []byte
+		newObject     []byte
+		wantUnchanged int
+		wantSuccess   bool
+	}{{
+		name:          "status update",
+		annotated:     true,
+		oldObject:     pod("nginx:latest", "Pending"),
+		newObject:     pod("nginx:latest", "Running"),
+		wantUnchanged: 1,
+	}, {
+		name:          "status update passing",
+		annotated:     true,
+		oldObject:     pod("nginx:1.23", "Pending"),
+		newObject:     pod("nginx:1.23", "Running"),
+		wantUnchanged: 1,
+		wantSuccess:   true,
+	}, {
+		name:      "image update",
+		annotated: true,
+		oldObject: pod("nginx:1.23", "Running"),
+		newObject: pod("nginx:latest", "Running"),
+	}, {
+		name:      "not opted in",
+		oldObject: pod("nginx:latest", "Pending"),
+		newObject: pod("nginx:latest", "Running"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policy kyvernov1.ClusterPolicy
+			assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+			if !tt.annotated {
+				policy.SetAnnotations(nil)
+			}
+			e := newUnchangedEngine()
+			// the outcome for the old object is computed when it is created
+			created := e.Validate(context.TODO(), newUnchangedPolicyContext(t, &policy, tt.oldObject, nil))
+			assert.Equal(t, len(created.PolicyResponse.Rules), 1)
+			assert.Equal(t, created.PolicyResponse.RulesUnchangedCount, 0)
+			resp := e.Validate(context.TODO(), newUnchangedPolicyContext(t, &policy, tt.newObject, tt.oldObject))
+			assert.Equal(t, len(resp.PolicyResponse.Rules), 1)
+			assert.Equal(t, resp.PolicyResponse.RulesUnchangedCount, tt.wantUnchanged)
+			assert.Equal(t, resp.IsSuccessful(), tt.wantSuccess)
+		})
+	}
+}
+
+func Test_skipUnchangedMessage(t *testing.T) {
+	policyRaw := []byte(`{
+		"apiVersion": "kyverno.io/v1",
+		"kind": "ClusterPolicy",
+		"metadata": {
+			"name": "disallow-latest",
+			"annotations": {"policies.kyverno.io/skip-unchanged-updates": "true"}
+		},
+		"spec": {
+			"validationFailureAction": "enforce",
+			"rules": [{
+				"name": "check-image",
+				"match": {"any": [{"resources": {"kinds": ["Pod"]}}]},
+				"validate": {"message": "{{ request.object.metadata.name }} uses the latest tag", "pattern": {"spec": {"containers": [{"image": "!*:latest"}]}}}
+			}]
+		}
+	}`)
+	pod := func(name string, phase string) []byte {
+		return []byte(`{
+			"apiVersion": "v1",
+			"kind": "Pod",
+			"metadata": {"name": "` + name + `", "namespace": "default"},
+			"spec": {"containers": [{"name": "nginx", "image": "nginx:latest"}]},
+			"status": {"phase": "` + phase + `"}
+		}`)
+	}
+	var policy kyvernov1.ClusterPolicy
+	assert.NilError(t, json.Unmarshal(policyRaw, &policy))
+	e := newUnchangedEngine()
+	created := e.Validate(context.TODO(), newUnchangedPolicyContext(t, &policy, pod("first", "Pending"), nil))
+	assert.Equal(t, len(created.PolicyResponse.Rules), 1)
+	assert.Assert(t, strings.Contains(created.PolicyResponse.Rules[0].Message, "first uses the latest tag"))
+	// the second pod references the same fields but the message of the first pod must not be reused
+	resp := e.Validate(context.TODO(), newUnchangedPolicyContext(t, &policy, pod("second", "Running"), pod("second", "Pending")))
+	assert.Equal(t, len(resp.PolicyResponse.Rules), 1)
+	assert.Equal(t, resp.PolicyResponse.RulesUnchangedCount, 0)
+	assert.Assert(t, strings.Contains(resp.PolicyResponse.Rules[0].Message, "second uses the latest tag"))
+}
+
+func newUnchangedEngine() engineapi.Engine {
+	return NewEngine(config.NewDefaultConfiguration(), config.NewDefaultMetricsConfiguration(), nil, registryclient.NewOrDie(), LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()), nil)
+}
+
+func newUnchangedPolicyContext(t *testing.T, policy kyvernov1.PolicyInterface, newObject, oldObject []byte) *PolicyContext {
+	newResource, err := kubeutils.BytesToUnstructured(newObject)
+	assert.NilError(t, err)
+	jsonContext := enginecontext.NewContext()
+	assert.NilError(t, enginecontext.AddResource(jsonContext, newObject))
+	pc := &PolicyContext{
+		policy:             policy,
+		newResource:        *newResource,
+		admissionOperation: true,
+		jsonContext:        jsonContext,
+	}
+	if oldObject != nil {
+		oldResource, err := kubeutils.BytesToUnstructured(oldObject)
+		assert.NilError(t, err)
+		pc.oldResource = *oldResource
+	}
+	return pc
+}
+
+func sortedPaths(paths [][]string) [][]string {
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.Join(paths[i], "/") < strings.Join(paths[j], "/")
+	})
+	return paths
+}
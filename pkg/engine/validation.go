@@ -38,7 +38,7 @@ func (e *engine) validate(
 	logger := internal.BuildLogger(policyContext)
 	logger.V(4).Info("start validate policy processing", "startTime", startTime)
 	policyResponse := e.validateResource(ctx, logger, policyContext)
-	defer logger.V(4).Info("finished policy processing", "processingTime", policyResponse.ProcessingTime.String(), "validationRulesApplied", policyResponse.RulesAppliedCount)
+	defer logger.V(4).Info("finished policy processing", "processingTime", policyResponse.ProcessingTime.String(), "validationRulesApplied", policyResponse.RulesAppliedCount, "validationRulesUnchanged", policyResponse.RulesUnchangedCount)
 	engineResponse := &engineapi.EngineResponse{PolicyResponse: *policyResponse}
 	return internal.BuildResponse(policyContext, engineResponse, startTime)
 }
@@ -78,7 +78,12 @@ func (e *engine) validateResource(
 			ctx,
 			"pkg/engine",
 			fmt.Sprintf("RULE %s", rule.Name),
-			func(ctx context.Context, span trace.Span) *engineapi.RuleResponse {
+			func(ctx context.Context, span trace.Span) (response *engineapi.RuleResponse) {
+				defer func() {
+					if response != nil {
+						internal.SetRuleSpanResponses(span, *response)
+					}
+				}()
 				hasValidate := rule.HasValidate()
 				hasValidateImage := rule.HasImagesValidationChecks()
 				hasYAMLSignatureVerify := rule.HasYAMLSignatureVerify()
@@ -100,7 +105,7 @@ func (e *engine) validateResource(
 				log.V(3).Info("processing validation rule", "matchCount", matchCount, "applyRules", applyRules)
 				enginectx.JSONContext().Reset()
 				if hasValidate && !hasYAMLSignatureVerify {
-					return e.processValidationRule(ctx, log, enginectx, rule)
+					return e.processUnchangedValidationRule(ctx, log, enginectx, rule, resp)
 				} else if hasValidateImage {
 					return e.processImageValidationRule(ctx, log, enginectx, rule)
 				} else if hasYAMLSignatureVerify {
@@ -108,6 +113,7 @@ func (e *engine) validateResource(
 				}
 				return nil
 			},
+			internal.RuleSpanOptions(enginectx.Policy(), rule.Name),
 		)
 		if ruleResp != nil {
 			internal.AddRuleResponse(resp, ruleResp, startTime)
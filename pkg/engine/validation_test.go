@@ -22,9 +22,10 @@ import (
 func testValidate(ctx context.Context, rclient registryclient.Client, pContext *PolicyContext, cfg config.Configuration) *engineapi.EngineResponse {
 	e := NewEngine(
 		cfg,
+		config.NewDefaultMetricsConfiguration(),
 		nil,
 		rclient,
-		LegacyContextLoaderFactory(nil),
+		LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()),
 		nil,
 	)
 	return e.Validate(
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+type aggregate struct {
+	start time.Time
+	count int
+}
+
+// aggregator folds identical events emitted within a time window,
+// the number of folded events is reported by the next event emitted
+type aggregator struct {
+	window  time.Duration
+	clock   clock.PassiveClock
+	lock    sync.Mutex
+	entries map[Info]*aggregate
+}
+
+func newAggregator(window time.Duration, clock clock.PassiveClock) *aggregator {
+	return &aggregator{
+		window:  window,
+		clock:   clock,
+		entries: map[Info]*aggregate{},
+	}
+}
+
+// aggregate returns the event to emit, or false if the event was folded into a previous one
+func (a *aggregator) aggregate(info Info) (Info, bool) {
+	if a.window <= 0 {
+		return info, true
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	now := a.clock.Now()
+	entry := a.entries[info]
+	if entry != nil && now.Sub(entry.start) < a.window {
+		entry.count++
+		return info, false
+	}
+	a.entries[info] = &aggregate{start: now}
+	if entry != nil && entry.count > 0 {
+		return withAggregatedCount(info, entry.count), true
+	}
+	return info, true
+}
+
+// expire removes the entries older than the window and returns the events summarising the folded ones
+func (a *aggregator) expire() []Info {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	now := a.clock.Now()
+	var infos []Info
+	for info, entry := range a.entries {
+		if now.Sub(entry.start) >= a.window {
+			delete(a.entries, info)
+			if entry.count > 0 {
+				infos = append(infos, withAggregatedCount(info, entry.count))
+			}
+		}
+	}
+	return infos
+}
+
+func withAggregatedCount(info Info, count int) Info {
+	info.Message = fmt.Sprintf("%s (%d similar events aggregated)", info.Message, count)
+	return info
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov1informers "github.com/kyverno/kyverno/pkg/client/informers/externalversions/kyverno/v1"
 	kyvernov1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
@@ -14,8 +15,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
 )
 
 const (
@@ -23,6 +27,26 @@ const (
 	workQueueRetryLimit = 3
 )
 
+// values of the policies.kyverno.io/events annotation
+const (
+	// EventsAll emits all events for the policy, this is the default
+	EventsAll = "all"
+	// EventsFailures only emits PolicyViolation and PolicyError events for the policy
+	EventsFailures = "failures"
+	// EventsNone disables events for the policy
+	EventsNone = "none"
+)
+
+// Options configures how events are limited before being emitted
+type Options struct {
+	// RateLimitQPS is the number of events per second allowed for each reason, zero disables rate limiting
+	RateLimitQPS float64
+	// RateLimitBurst is the burst of events allowed for each reason
+	RateLimitBurst int
+	// AggregationWindow is the window during which identical events are folded, zero disables aggregation
+	AggregationWindow time.Duration
+}
+
 // generator generate events
 type generator struct {
 	client dclient.Interface
@@ -42,6 +66,12 @@ type generator struct {
 	mutateExistingRecorder record.EventRecorder
 
 	maxQueuedEvents int
+	// rate limiters per event reason
+	limiters map[Reason]flowcontrol.RateLimiter
+	// folds identical events
+	aggregator *aggregator
+
+	metrics generatorMetrics
 
 	log logr.Logger
 }
@@ -64,8 +94,15 @@ func NewEventGenerator(
 	cpInformer kyvernov1informers.ClusterPolicyInformer,
 	pInformer kyvernov1informers.PolicyInformer,
 	maxQueuedEvents int,
+	options Options,
 	log logr.Logger,
 ) Controller {
+	limiters := map[Reason]flowcontrol.RateLimiter{}
+	if options.RateLimitQPS > 0 {
+		for _, reason := range []Reason{PolicyViolation, PolicyApplied, PolicyError, PolicySkipped, PolicyConflict, PolicyNotIdempotent} {
+			limiters[reason] = flowcontrol.NewTokenBucketRateLimiter(float32(options.RateLimitQPS), options.RateLimitBurst)
+		}
+	}
 	gen := generator{
 		client:                 client,
 		cpLister:               cpInformer.Lister(),
@@ -76,6 +113,9 @@ func NewEventGenerator(
 		genPolicyRecorder:      NewRecorder(GeneratePolicyController, client.GetEventsInterface()),
 		mutateExistingRecorder: NewRecorder(MutateExistingController, client.GetEventsInterface()),
 		maxQueuedEvents:        maxQueuedEvents,
+		limiters:               limiters,
+		aggregator:             newAggregator(options.AggregationWindow, clock.RealClock{}),
+		metrics:                newGeneratorMetrics(log),
 		log:                    log,
 	}
 	return &gen
@@ -86,11 +126,6 @@ func (gen *generator) Add(infos ...Info) {
 	logger := gen.log
 
 	logger.V(3).Info("generating events", "count", len(infos))
-	if gen.maxQueuedEvents == 0 || gen.queue.Len() > gen.maxQueuedEvents {
-		logger.V(2).Info("exceeds the event queue limit, dropping the event", "maxQueuedEvents", gen.maxQueuedEvents, "current size", gen.queue.Len())
-		return
-	}
-
 	for _, info := range infos {
 		if info.Name == "" {
 			// dont create event for resources with generateName
@@ -98,7 +133,59 @@ func (gen *generator) Add(infos ...Info) {
 			logger.V(3).Info("skipping event creation for resource without a name", "kind", info.Kind, "name", info.Name, "namespace", info.Namespace)
 			continue
 		}
-		gen.queue.Add(info)
+		if !gen.isEnabled(info) {
+			logger.V(4).Info("events are disabled for the policy, dropping the event", "policy", info.Policy, "reason", info.Reason)
+			gen.metrics.recordDropped(context.TODO(), info, dropCauseDisabled)
+			continue
+		}
+		info, ok := gen.aggregator.aggregate(info)
+		if !ok {
+			gen.metrics.recordAggregated(context.TODO(), info)
+			continue
+		}
+		if limiter := gen.limiters[info.Reason]; limiter != nil && !limiter.TryAccept() {
+			logger.V(4).Info("exceeds the event rate limit, dropping the event", "reason", info.Reason)
+			gen.metrics.recordDropped(context.TODO(), info, dropCauseRateLimited)
+			continue
+		}
+		gen.enqueue(info)
+	}
+}
+
+func (gen *generator) enqueue(info Info) {
+	if gen.maxQueuedEvents == 0 || gen.queue.Len() > gen.maxQueuedEvents {
+		gen.log.V(2).Info("exceeds the event queue limit, dropping the event", "maxQueuedEvents", gen.maxQueuedEvents, "current size", gen.queue.Len())
+		gen.metrics.recordDropped(context.TODO(), info, dropCauseQueueFull)
+		return
+	}
+	gen.queue.Add(info)
+}
+
+// isEnabled checks the events annotation of the policy the event relates to
+func (gen *generator) isEnabled(info Info) bool {
+	if info.Policy == "" {
+		return true
+	}
+	var policy kyvernov1.PolicyInterface
+	namespace, name, err := cache.SplitMetaNamespaceKey(info.Policy)
+	if err != nil {
+		return true
+	}
+	if namespace == "" {
+		policy, err = gen.cpLister.Get(name)
+	} else {
+		policy, err = gen.pLister.Policies(namespace).Get(name)
+	}
+	if err != nil {
+		return true
+	}
+	switch policy.GetAnnotations()[kyvernov1.AnnotationPolicyEvents] {
+	case EventsNone:
+		return false
+	case EventsFailures:
+		return info.Reason == PolicyViolation || info.Reason == PolicyError
+	default:
+		return true
 	}
 }
 
@@ -113,9 +200,19 @@ func (gen *generator) Run(ctx context.Context, workers int) {
 	for i := 0; i < workers; i++ {
 		go wait.UntilWithContext(ctx, gen.runWorker, time.Second)
 	}
+	if gen.aggregator.window > 0 {
+		go wait.UntilWithContext(ctx, gen.flushAggregated, gen.aggregator.window)
+	}
 	<-ctx.Done()
 }
 
+// flushAggregated emits the events summarising the aggregated events of expired windows
+func (gen *generator) flushAggregated(ctx context.Context) {
+	for _, info := range gen.aggregator.expire() {
+		gen.enqueue(info)
+	}
+}
+
 func (gen *generator) runWorker(ctx context.Context) {
 	for gen.processNextWorkItem() {
 	}
@@ -0,0 +1,80 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned/fake"
+	kyvernoinformers "github.com/kyverno/kyverno/pkg/client/informers/externalversions"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func newTestGenerator(t *testing.T, clock *clocktesting.FakePassiveClock, policies ...*kyvernov1.ClusterPolicy) *generator {
+	informers := kyvernoinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	for _, policy := range policies {
+		assert.NilError(t, informers.Kyverno().V1().ClusterPolicies().Informer().GetIndexer().Add(policy))
+	}
+	return &generator{
+		cpLister:        informers.Kyverno().V1().ClusterPolicies().Lister(),
+		pLister:         informers.Kyverno().V1().Policies().Lister(),
+		queue:           workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
+		maxQueuedEvents: 1000,
+		limiters:        map[Reason]flowcontrol.RateLimiter{},
+		aggregator:      newAggregator(time.Minute, clock),
+		metrics:         newGeneratorMetrics(logr.Discard()),
+		log:             logr.Discard(),
+	}
+}
+
+func Test_PolicyEventsAnnotation(t *testing.T) {
+	policy := func(name, events string) *kyvernov1.ClusterPolicy {
+		return &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{kyvernov1.AnnotationPolicyEvents: events},
+		}}
+	}
+	gen := newTestGenerator(t, clocktesting.NewFakePassiveClock(time.Now()), policy("quiet", EventsNone), policy("failures", EventsFailures))
+	gen.Add(
+		Info{Kind: "Pod", Name: "a", Reason: PolicyViolation, Policy: "quiet"},
+		Info{Kind: "Pod", Name: "b", Reason: PolicyApplied, Policy: "failures"},
+		Info{Kind: "Pod", Name: "c", Reason: PolicyViolation, Policy: "failures"},
+		Info{Kind: "Pod", Name: "d", Reason: PolicyApplied, Policy: "missing"},
+	)
+	assert.Equal(t, gen.queue.Len(), 2)
+}
+
+func Test_AggregateEvents(t *testing.T) {
+	clock := clocktesting.NewFakePassiveClock(time.Now())
+	gen := newTestGenerator(t, clock)
+	info := Info{Kind: "Pod", Name: "a", Reason: PolicyViolation, Message: "denied"}
+	gen.Add(info, info, info)
+	assert.Equal(t, gen.queue.Len(), 1)
+	clock.SetTime(clock.Now().Add(time.Minute))
+	gen.Add(info)
+	assert.Equal(t, gen.queue.Len(), 2)
+	gen.queue.Get()
+	aggregated, _ := gen.queue.Get()
+	assert.Equal(t, aggregated.(Info).Message, "denied (2 similar events aggregated)")
+	// folded events are summarised when the window expires
+	gen.Add(info)
+	clock.SetTime(clock.Now().Add(time.Minute))
+	assert.Equal(t, len(gen.aggregator.expire()), 1)
+	assert.Equal(t, len(gen.aggregator.entries), 0)
+}
+
+func Test_RateLimitEvents(t *testing.T) {
+	gen := newTestGenerator(t, clocktesting.NewFakePassiveClock(time.Now()))
+	gen.aggregator.window = 0
+	gen.limiters[PolicyViolation] = flowcontrol.NewFakeNeverRateLimiter()
+	gen.Add(
+		Info{Kind: "Pod", Name: "a", Reason: PolicyViolation},
+		Info{Kind: "Pod", Name: "a", Reason: PolicyApplied},
+	)
+	assert.Equal(t, gen.queue.Len(), 1)
+}
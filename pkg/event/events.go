@@ -17,6 +17,7 @@ func NewPolicyFailEvent(source Source, reason Reason, engineResponse *engineapi.
 		Reason:    reason,
 		Source:    source,
 		Message:   buildPolicyEventMessage(ruleResp, engineResponse.GetResourceSpec(), blocked),
+		Policy:    getPolicyKey(engineResponse.Policy),
 	}
 }
 
@@ -40,6 +41,13 @@ func buildPolicyEventMessage(resp *engineapi.RuleResponse, resource engineapi.Re
 	return b.String()
 }
 
+func getPolicyKey(policy kyvernov1.PolicyInterface) string {
+	if policy.IsNamespaced() {
+		return policy.GetNamespace() + "/" + policy.GetName()
+	}
+	return policy.GetName()
+}
+
 func getPolicyKind(policy kyvernov1.PolicyInterface) string {
 	if policy.IsNamespaced() {
 		return "Policy"
@@ -65,6 +73,7 @@ func NewPolicyAppliedEvent(source Source, engineResponse *engineapi.EngineRespon
 		Reason:    PolicyApplied,
 		Source:    source,
 		Message:   bldr.String(),
+		Policy:    getPolicyKey(engineResponse.Policy),
 	}
 }
 
@@ -83,6 +92,47 @@ func NewResourceViolationEvent(source Source, reason Reason, engineResponse *eng
 		Reason:    reason,
 		Source:    source,
 		Message:   bldr.String(),
+		Policy:    getPolicyKey(engineResponse.Policy),
+	}
+}
+
+// NewPolicyConflictEvent builds the event of a policy overwriting a path of the resource set by another policy
+func NewPolicyConflictEvent(source Source, policy kyvernov1.PolicyInterface, overwritten kyvernov1.PolicyInterface, resource engineapi.ResourceSpec, path string) Info {
+	var bldr strings.Builder
+	if resource.Namespace != "" {
+		fmt.Fprintf(&bldr, "%s %s/%s", resource.Kind, resource.Namespace, resource.Name)
+	} else {
+		fmt.Fprintf(&bldr, "%s %s", resource.Kind, resource.Name)
+	}
+	fmt.Fprintf(&bldr, ": path %s set by %s %s was overwritten", path, getPolicyKind(overwritten), getPolicyKey(overwritten))
+	return Info{
+		Kind:      getPolicyKind(policy),
+		Name:      policy.GetName(),
+		Namespace: policy.GetNamespace(),
+		Reason:    PolicyConflict,
+		Source:    source,
+		Message:   bldr.String(),
+		Policy:    getPolicyKey(policy),
+	}
+}
+
+// NewPolicyNotIdempotentEvent builds the event of mutate rules producing patches when applied to their own output
+func NewPolicyNotIdempotentEvent(source Source, policy kyvernov1.PolicyInterface, resource engineapi.ResourceSpec, rules ...string) Info {
+	var bldr strings.Builder
+	if resource.Namespace != "" {
+		fmt.Fprintf(&bldr, "%s %s/%s", resource.Kind, resource.Namespace, resource.Name)
+	} else {
+		fmt.Fprintf(&bldr, "%s %s", resource.Kind, resource.Name)
+	}
+	fmt.Fprintf(&bldr, ": mutate rules %s are not idempotent, applying them again produced further patches", strings.Join(rules, ", "))
+	return Info{
+		Kind:      getPolicyKind(policy),
+		Name:      policy.GetName(),
+		Namespace: policy.GetNamespace(),
+		Reason:    PolicyNotIdempotent,
+		Source:    source,
+		Message:   bldr.String(),
+		Policy:    getPolicyKey(policy),
 	}
 }
 
@@ -99,6 +149,7 @@ func NewBackgroundFailedEvent(err error, policy, rule string, source Source, r *
 		Source:    source,
 		Reason:    PolicyError,
 		Message:   fmt.Sprintf("policy %s/%s error: %v", policy, rule, err),
+		Policy:    policy,
 	})
 
 	return events
@@ -118,6 +169,7 @@ func NewBackgroundSuccessEvent(policy, rule string, source Source, r *unstructur
 		Source:    source,
 		Reason:    PolicyApplied,
 		Message:   msg,
+		Policy:    policy,
 	})
 
 	return events
@@ -138,6 +190,7 @@ func NewPolicyExceptionEvents(engineResponse *engineapi.EngineResponse, ruleResp
 		Namespace: engineResponse.PolicyResponse.Policy.Namespace,
 		Reason:    PolicySkipped,
 		Message:   policyMessage,
+		Policy:    getPolicyKey(engineResponse.Policy),
 	}
 	exceptionEvent := Info{
 		Kind:      "PolicyException",
@@ -145,6 +198,7 @@ func NewPolicyExceptionEvents(engineResponse *engineapi.EngineResponse, ruleResp
 		Namespace: exceptionNamespace,
 		Reason:    PolicySkipped,
 		Message:   exceptionMessage,
+		Policy:    getPolicyKey(engineResponse.Policy),
 	}
 	return []Info{policyEvent, exceptionEvent}
 }
@@ -8,4 +8,6 @@ type Info struct {
 	Reason    Reason
 	Message   string
 	Source    Source
+	// Policy is the key of the policy the event relates to
+	Policy string
 }
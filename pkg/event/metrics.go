@@ -0,0 +1,65 @@
+package event
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+)
+
+// reasons why an event is dropped
+const (
+	dropCauseQueueFull   = "queue_full"
+	dropCauseRateLimited = "rate_limited"
+	dropCauseDisabled    = "disabled"
+)
+
+type generatorMetrics struct {
+	droppedTotal    syncint64.Counter
+	aggregatedTotal syncint64.Counter
+}
+
+func newGeneratorMetrics(logger logr.Logger) generatorMetrics {
+	meter := global.MeterProvider().Meter(metrics.MeterName)
+	droppedTotal, err := meter.SyncInt64().Counter(
+		"kyverno_events_dropped",
+		instrument.WithDescription("can be used to track the number of events not emitted, by reason and cause (queue_full, rate_limited, disabled)"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_events_dropped")
+	}
+	aggregatedTotal, err := meter.SyncInt64().Counter(
+		"kyverno_events_aggregated",
+		instrument.WithDescription("can be used to track the number of repeated events folded into a single event, by reason"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_events_aggregated")
+	}
+	return generatorMetrics{
+		droppedTotal:    droppedTotal,
+		aggregatedTotal: aggregatedTotal,
+	}
+}
+
+func (m generatorMetrics) recordDropped(ctx context.Context, info Info, cause string) {
+	if m.droppedTotal != nil {
+		m.droppedTotal.Add(ctx, 1,
+			attribute.String("reason", string(info.Reason)),
+			attribute.String("event_source", string(info.Source)),
+			attribute.String("cause", cause),
+		)
+	}
+}
+
+func (m generatorMetrics) recordAggregated(ctx context.Context, info Info) {
+	if m.aggregatedTotal != nil {
+		m.aggregatedTotal.Add(ctx, 1,
+			attribute.String("reason", string(info.Reason)),
+			attribute.String("event_source", string(info.Source)),
+		)
+	}
+}
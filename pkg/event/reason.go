@@ -8,4 +8,7 @@ const (
 	PolicyApplied   Reason = "PolicyApplied"
 	PolicyError     Reason = "PolicyError"
 	PolicySkipped   Reason = "PolicySkipped"
+	PolicyConflict  Reason = "PolicyConflict"
+	// PolicyNotIdempotent is reported when mutate rules produce patches when applied to their own output
+	PolicyNotIdempotent Reason = "PolicyNotIdempotent"
 )
@@ -0,0 +1,55 @@
+package lint
+
+import (
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// deprecatedOperators maps deprecated condition operators to their replacement
+var deprecatedOperators = map[string]string{
+	"Equal":    "Equals",
+	"NotEqual": "NotEquals",
+	"In":       "AllIn or AnyIn",
+	"NotIn":    "AllNotIn or AnyNotIn",
+}
+
+func lintOperators(l *linter, path *field.Path, _ kyvernov1.Rule, raw map[string]interface{}) {
+	walk(path, raw, func(p *field.Path, value interface{}) {
+		condition, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if _, ok := condition["key"]; !ok {
+			return
+		}
+		if op, ok := condition["operator"].(string); ok {
+			if replacement, ok := deprecatedOperators[op]; ok {
+				l.report(DeprecatedOperator, p.Child("operator"), "operator %s is deprecated, use %s instead", op, replacement)
+			}
+		}
+	})
+}
+
+func lintAPICalls(l *linter, path *field.Path, rule kyvernov1.Rule, _ map[string]interface{}) {
+	if rule.RawAnyAllConditions == nil {
+		lintContextAPICalls(l, path.Child("context"), rule.Context, "rule")
+	}
+	for i, fe := range rule.Validation.ForEachValidation {
+		if fe.AnyAllConditions == nil {
+			lintContextAPICalls(l, path.Child("validate", "foreach").Index(i).Child("context"), fe.Context, "foreach")
+		}
+	}
+	for i, fe := range rule.Mutation.ForEachMutation {
+		if fe.AnyAllConditions == nil {
+			lintContextAPICalls(l, path.Child("mutate", "foreach").Index(i).Child("context"), fe.Context, "foreach")
+		}
+	}
+}
+
+func lintContextAPICalls(l *linter, path *field.Path, entries []kyvernov1.ContextEntry, scope string) {
+	for i, entry := range entries {
+		if entry.APICall != nil {
+			l.report(APICallWithoutPreconditions, path.Index(i), "context entry %s makes an API call for every admission request, add preconditions to the %s to limit it", entry.Name, scope)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package lint
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/anchor"
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// mutateWrite is a field of the resource set by a mutate rule
+type mutateWrite struct {
+	rule  string
+	kinds []string
+	// path is the path of the field in the resource, array elements are identified by name, * matches any element or key
+	path []string
+	// source is the location of the write in the policy
+	source *field.Path
+}
+
+// Conflicts reports the mutate rules of the policy setting fields also set by mutate rules of the other policies
+// matching the same kinds, the resulting value depends on the order policies are applied in
+func Conflicts(policy kyvernov1.PolicyInterface, others ...kyvernov1.PolicyInterface) []Finding {
+	writes := mutateWrites(policy)
+	if len(writes) == 0 {
+		return nil
+	}
+	l := &linter{policy: policy}
+	type reported struct {
+		source string
+		policy string
+		rule   string
+	}
+	seen := map[reported]bool{}
+	for _, other := range others {
+		// namespaced policies only apply to resources of their namespace
+		if policy.IsNamespaced() && other.IsNamespaced() && policy.GetNamespace() != other.GetNamespace() {
+			continue
+		}
+		if policyName(policy) == policyName(other) {
+			continue
+		}
+		for _, otherWrite := range mutateWrites(other) {
+			for _, write := range writes {
+				if !kindsOverlap(write.kinds, otherWrite.kinds) || !pathsOverlap(write.path, otherWrite.path) {
+					continue
+				}
+				key := reported{source: write.source.String(), policy: policyName(other), rule: otherWrite.rule}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				l.rule = write.rule
+				l.report(MutateConflict, write.source, "sets %s also set by policy %s rule %s, the result depends on the order policies are applied in",
+					strings.Join(write.path, "."), policyName(other), otherWrite.rule)
+			}
+		}
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].Path < l.findings[j].Path
+	})
+	return l.findings
+}
+
+// mutateWrites returns the fields set by the mutate rules of the policy, mutations of existing resources are ignored
+func mutateWrites(policy kyvernov1.PolicyInterface) []mutateWrite {
+	var writes []mutateWrite
+	rulesPath := field.NewPath("spec").Child("rules")
+	for i, rule := range policy.GetSpec().Rules {
+		if !rule.HasMutate() || len(rule.Mutation.Targets) > 0 {
+			continue
+		}
+		raw, err := toMap(rule)
+		if err != nil {
+			continue
+		}
+		mutation, _ := raw["mutate"].(map[string]interface{})
+		var kinds []string
+		for _, filter := range append(rule.MatchResources.Any, rule.MatchResources.All...) {
+			kinds = append(kinds, kindNames(filter.Kinds)...)
+		}
+		kinds = append(kinds, kindNames(rule.MatchResources.Kinds)...)
+		collect := func(path []string, source *field.Path) {
+			writes = append(writes, mutateWrite{rule: rule.Name, kinds: kinds, path: path, source: source})
+		}
+		mutationWrites(mutation, rulesPath.Index(i).Child("mutate"), collect)
+	}
+	return writes
+}
+
+// mutationWrites collects the fields set by the patches of a mutation or of a foreach mutation
+func mutationWrites(mutation map[string]interface{}, source *field.Path, collect func([]string, *field.Path)) {
+	if patch, ok := mutation["patchStrategicMerge"]; ok {
+		strategicMergeWrites(patch, nil, source.Child("patchStrategicMerge"), collect)
+	}
+	if patches, ok := mutation["patchesJson6902"].(string); ok {
+		jsonPatchWrites(patches, source.Child("patchesJson6902"), collect)
+	}
+	if foreach, ok := mutation["foreach"].([]interface{}); ok {
+		for i, element := range foreach {
+			if element, ok := element.(map[string]interface{}); ok {
+				mutationWrites(element, source.Child("foreach").Index(i), collect)
+			}
+		}
+	}
+}
+
+// strategicMergeWrites collects the leaves of a strategic merge patch, conditions are not writes
+func strategicMergeWrites(patch interface{}, path []string, source *field.Path, collect func([]string, *field.Path)) {
+	switch typed := patch.(type) {
+	case map[string]interface{}:
+		if len(typed) == 0 {
+			collect(path, source)
+			return
+		}
+		for key, value := range typed {
+			element := key
+			if a := anchor.Parse(key); a != nil {
+				if !anchor.IsAddIfNotPresent(a) {
+					continue
+				}
+				element = a.Key()
+			}
+			if strings.HasPrefix(element, "$") {
+				continue
+			}
+			strategicMergeWrites(value, append(path[:len(path):len(path)], pathElement(element)), source.Child(key), collect)
+		}
+	case []interface{}:
+		objects := false
+		for i, value := range typed {
+			if object, ok := value.(map[string]interface{}); ok {
+				objects = true
+				strategicMergeWrites(value, append(path[:len(path):len(path)], listElement(object)), source.Index(i), collect)
+			}
+		}
+		if !objects {
+			collect(path, source)
+		}
+	default:
+		collect(path, source)
+	}
+}
+
+// listElement returns the name of a list element patched by a strategic merge patch, elements are merged by name,
+// it returns a wildcard if the element has no name or its name matches several elements
+func listElement(element map[string]interface{}) string {
+	for key, value := range element {
+		if a := anchor.Parse(key); a != nil {
+			key = a.Key()
+		}
+		if key != "name" {
+			continue
+		}
+		name, ok := value.(string)
+		if !ok || wildcard.ContainsWildcard(name) {
+			return "*"
+		}
+		return pathElement(name)
+	}
+	return "*"
+}
+
+// jsonPatchWrites collects the paths changed by JSON patches, elements appended to arrays don't change existing fields
+func jsonPatchWrites(patches string, source *field.Path, collect func([]string, *field.Path)) {
+	data, err := yaml.YAMLToJSON([]byte(patches))
+	if err != nil {
+		return
+	}
+	var operations []struct {
+		Op   string `json:"op"`
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return
+	}
+	for _, operation := range operations {
+		if operation.Op == "test" || operation.Path == "" {
+			continue
+		}
+		var path []string
+		for _, element := range strings.Split(strings.TrimPrefix(operation.Path, "/"), "/") {
+			path = append(path, pathElement(strings.ReplaceAll(strings.ReplaceAll(element, "~1", "/"), "~0", "~")))
+		}
+		if operation.Op == "add" && path[len(path)-1] == "-" {
+			continue
+		}
+		collect(path, source)
+	}
+}
+
+// pathElement replaces elements depending on variables with a wildcard
+func pathElement(element string) string {
+	if strings.Contains(element, "{{") {
+		return "*"
+	}
+	return element
+}
+
+// pathsOverlap returns true if a path is a parent of the other or the same path
+func pathsOverlap(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] && a[i] != "*" && b[i] != "*" {
+			return false
+		}
+	}
+	return true
+}
+
+// kindsOverlap returns true if a kind of a rule can be matched by the kinds of the other rule
+func kindsOverlap(a, b []string) bool {
+	for _, kind := range a {
+		if wildcard.CheckPatterns(b, kind) {
+			return true
+		}
+	}
+	for _, kind := range b {
+		if wildcard.CheckPatterns(a, kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Check describes a lint check, IDs are stable across releases and can be used to filter findings
+type Check struct {
+	ID          string
+	Name        string
+	Description string
+	Severity    Severity
+}
+
+// Finding is a problem reported by a lint check
+type Finding struct {
+	CheckID  string   `json:"checkID"`
+	Severity Severity `json:"severity"`
+	Policy   string   `json:"policy"`
+	Rule     string   `json:"rule,omitempty"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+var (
+	UndefinedVariable = Check{
+		ID:          "KL001",
+		Name:        "undefined-variable",
+		Description: "Variables must reference built-in variables or context entries defined in the rule.",
+		Severity:    SeverityError,
+	}
+	UnmatchableRule = Check{
+		ID:          "KL002",
+		Name:        "unmatchable-rule",
+		Description: "The match and exclude blocks of a rule must not leave an empty set of resources.",
+		Severity:    SeverityError,
+	}
+	PatternNeverPasses = Check{
+		ID:          "KL003",
+		Name:        "pattern-never-passes",
+		Description: "Validation patterns must be satisfiable by at least one value.",
+		Severity:    SeverityError,
+	}
+	DeprecatedOperator = Check{
+		ID:          "KL004",
+		Name:        "deprecated-operator",
+		Description: "Conditions should not use deprecated operators.",
+		Severity:    SeverityWarning,
+	}
+	BackgroundRequestVariable = Check{
+		ID:          "KL005",
+		Name:        "background-request-variable",
+		Description: "Policies using request variables other than request.object should set background to false.",
+		Severity:    SeverityWarning,
+	}
+	APICallWithoutPreconditions = Check{
+		ID:          "KL006",
+		Name:        "apicall-without-preconditions",
+		Description: "Rules making API calls should use preconditions to limit the requests they are evaluated for.",
+		Severity:    SeverityWarning,
+	}
+	MutateConflict = Check{
+		ID:          "KL007",
+		Name:        "mutate-conflict",
+		Description: "Mutate rules of different policies matching the same kinds should not set the same fields.",
+		Severity:    SeverityWarning,
+	}
+)
+
+type ruleLinter func(*linter, *field.Path, kyvernov1.Rule, map[string]interface{})
+
+var linters = []ruleLinter{
+	lintVariables,
+	lintMatchExclude,
+	lintPatterns,
+	lintOperators,
+	lintAPICalls,
+}
+
+// Checks returns all the lint checks, ordered by ID
+func Checks() []Check {
+	return []Check{
+		UndefinedVariable,
+		UnmatchableRule,
+		PatternNeverPasses,
+		DeprecatedOperator,
+		BackgroundRequestVariable,
+		APICallWithoutPreconditions,
+		MutateConflict,
+	}
+}
+
+type linter struct {
+	policy   kyvernov1.PolicyInterface
+	rule     string
+	findings []Finding
+}
+
+func (l *linter) report(check Check, path *field.Path, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{
+		CheckID:  check.ID,
+		Severity: check.Severity,
+		Policy:   policyName(l.policy),
+		Rule:     l.rule,
+		Path:     path.String(),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Lint runs the lint checks on the rules of a policy as they are written, autogen rules are not linted
+func Lint(policy kyvernov1.PolicyInterface) []Finding {
+	l := &linter{policy: policy}
+	rulesPath := field.NewPath("spec").Child("rules")
+	for i, rule := range policy.GetSpec().Rules {
+		l.rule = rule.Name
+		raw, err := toMap(rule)
+		if err != nil {
+			continue
+		}
+		for _, lint := range linters {
+			lint(l, rulesPath.Index(i), rule, raw)
+		}
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		if l.findings[i].Path != l.findings[j].Path {
+			return l.findings[i].Path < l.findings[j].Path
+		}
+		return l.findings[i].CheckID < l.findings[j].CheckID
+	})
+	return l.findings
+}
+
+func policyName(policy kyvernov1.PolicyInterface) string {
+	if policy.GetNamespace() != "" {
+		return policy.GetNamespace() + "/" + policy.GetName()
+	}
+	return policy.GetName()
+}
+
+func toMap(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// walk calls fn for every value of the document, keys are visited in order
+func walk(path *field.Path, value interface{}, fn func(*field.Path, interface{})) {
+	fn(path, value)
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typed))
+		for k := range typed {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walk(path.Child(k), typed[k], fn)
+		}
+	case []interface{}:
+		for i, v := range typed {
+			walk(path.Index(i), v, fn)
+		}
+	}
+}
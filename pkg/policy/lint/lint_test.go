@@ -0,0 +1,331 @@
+package lint
+
+import (
+	"testing"
+
+	yamlutils "github.com/kyverno/kyverno/pkg/utils/yaml"
+	"gotest.tools/assert"
+)
+
+func Test_Lint(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   string
+		expected []Finding
+	}{
+		{
+			name: "clean",
+			policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: clean
+spec:
+  rules:
+  - name: check
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    context:
+    - name: cm
+      configMap:
+        name: config
+        namespace: default
+    validate:
+      message: "{{ cm.data.message }} {{ request.object.metadata.name }}"
+      pattern:
+        spec:
+          containers:
+          - resources:
+              limits:
+                memory: ">=10Mi & <=1Gi"
+`,
+		},
+		{
+			name: "undefined variable and background",
+			policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: vars
+spec:
+  rules:
+  - name: check
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    validate:
+      message: "{{ to_upper(unknown.name) }} {{ request.operation }}"
+      deny: {}
+`,
+			expected: []Finding{
+				{CheckID: "KL001", Severity: SeverityError, Policy: "vars", Rule: "check", Path: "spec.rules[0].validate.message"},
+				{CheckID: "KL005", Severity: SeverityWarning, Policy: "vars", Rule: "check", Path: "spec.rules[0].validate.message"},
+			},
+		},
+		{
+			name: "unmatchable rules",
+			policy: `
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: match
+spec:
+  rules:
+  - name: all
+    match:
+      all:
+      - resources:
+          kinds: [Pod]
+      - resources:
+          kinds: [Service]
+    validate:
+      deny: {}
+  - name: excluded
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+          namespaces: [kube-system]
+    exclude:
+      any:
+      - resources:
+          namespaces: ["kube-*"]
+    validate:
+      deny: {}
+`,
+			expected: []Finding{
+				{CheckID: "KL002", Severity: SeverityError, Policy: "match", Rule: "all", Path: "spec.rules[0].match.all"},
+				{CheckID: "KL002", Severity: SeverityError, Policy: "match", Rule: "excluded", Path: "spec.rules[1].exclude"},
+			},
+		},
+		{
+			name: "patterns, operators and api calls",
+			policy: `
+apiVersion: kyverno.io/v1
+kind: Policy
+metadata:
+  name: misc
+  namespace: test
+spec:
+  background: false
+  rules:
+  - name: check
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    context:
+    - name: pods
+      apiCall:
+        urlPath: /api/v1/pods
+    validate:
+      pattern:
+        spec:
+          replicas: ">10 & <5 | 5-1"
+      deny:
+        conditions:
+          any:
+          - key: "{{ pods.items | length(@) }}"
+            operator: In
+            value: [1]
+`,
+			expected: []Finding{
+				{CheckID: "KL006", Severity: SeverityWarning, Policy: "test/misc", Rule: "check", Path: "spec.rules[0].context[0]"},
+				{CheckID: "KL004", Severity: SeverityWarning, Policy: "test/misc", Rule: "check", Path: "spec.rules[0].validate.deny.conditions.any[0].operator"},
+				{CheckID: "KL003", Severity: SeverityError, Policy: "test/misc", Rule: "check", Path: "spec.rules[0].validate.pattern.spec.replicas"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policies, err := yamlutils.GetPolicy([]byte(tc.policy))
+			assert.NilError(t, err)
+			findings := Lint(policies[0])
+			for i := range findings {
+				assert.Assert(t, findings[i].Message != "")
+				findings[i].Message = ""
+			}
+			assert.DeepEqual(t, findings, tc.expected)
+		})
+	}
+}
+
+func Test_neverPasses(t *testing.T) {
+	testCases := map[string]bool{
+		"*":                  false,
+		">1 & <3":            false,
+		">=2 & <=2":          false,
+		">2 & <2":            true,
+		"10-1":               true,
+		"1-10":               false,
+		">1Gi & <512Mi":      true,
+		">1h & <30m":         true,
+		">1h & <30Mi":        false,
+		"a & b":              true,
+		"a & b | c":          false,
+		">{{ foo }} & <1":    false,
+		"<0 | 1!-5":          false,
+		"!nginx & !busybox":  false,
+		"nginx:* & *:latest": false,
+	}
+	for pattern, expected := range testCases {
+		assert.Equal(t, neverPasses(pattern), expected, pattern)
+	}
+}
+
+func Test_Conflicts(t *testing.T) {
+	policies, err := yamlutils.GetPolicy([]byte(`
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: run-as-user
+spec:
+  rules:
+  - name: set-user
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    mutate:
+      patchStrategicMerge:
+        spec:
+          securityContext:
+            runAsUser: 1000
+          containers:
+          - (name): "*"
+            +(imagePullPolicy): IfNotPresent
+---
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: security-context
+spec:
+  rules:
+  - name: set-context
+    match:
+      any:
+      - resources:
+          kinds: [v1/Pod]
+    mutate:
+      patchesJson6902: |-
+        - op: replace
+          path: /spec/securityContext
+          value: {"runAsNonRoot": true}
+        - op: add
+          path: /spec/containers/-
+          value: {"name": "sidecar", "image": "sidecar"}
+  - name: pull-policy
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    mutate:
+      foreach:
+      - list: request.object.spec.containers
+        patchesJson6902: |-
+          - op: add
+            path: /spec/containers/{{elementIndex}}/imagePullPolicy
+            value: Always
+---
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: services
+spec:
+  rules:
+  - name: set-context
+    match:
+      any:
+      - resources:
+          kinds: [Service]
+    mutate:
+      patchStrategicMerge:
+        spec:
+          securityContext:
+            runAsUser: 1000
+`))
+	assert.NilError(t, err)
+	findings := Conflicts(policies[0], policies[1:]...)
+	assert.DeepEqual(t, findings, []Finding{{
+		CheckID:  "KL007",
+		Severity: SeverityWarning,
+		Policy:   "run-as-user",
+		Rule:     "set-user",
+		Path:     "spec.rules[0].mutate.patchStrategicMerge.spec.containers[0].+(imagePullPolicy)",
+		Message:  "sets spec.containers.*.imagePullPolicy also set by policy security-context rule pull-policy, the result depends on the order policies are applied in",
+	}, {
+		CheckID:  "KL007",
+		Severity: SeverityWarning,
+		Policy:   "run-as-user",
+		Rule:     "set-user",
+		Path:     "spec.rules[0].mutate.patchStrategicMerge.spec.securityContext.runAsUser",
+		Message:  "sets spec.securityContext.runAsUser also set by policy security-context rule set-context, the result depends on the order policies are applied in",
+	}})
+	assert.Equal(t, len(Conflicts(policies[2], policies[1])), 0)
+}
+
+func Test_ConflictsSidecars(t *testing.T) {
+	policies, err := yamlutils.GetPolicy([]byte(`
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: logging-sidecar
+spec:
+  rules:
+  - name: add-sidecar
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    mutate:
+      patchStrategicMerge:
+        spec:
+          containers:
+          - name: logging
+            image: fluent-bit
+---
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: proxy-sidecar
+spec:
+  rules:
+  - name: add-sidecar
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    mutate:
+      patchStrategicMerge:
+        spec:
+          containers:
+          - name: proxy
+            image: envoy
+---
+apiVersion: kyverno.io/v1
+kind: ClusterPolicy
+metadata:
+  name: proxy-version
+spec:
+  rules:
+  - name: set-image
+    match:
+      any:
+      - resources:
+          kinds: [Pod]
+    mutate:
+      patchStrategicMerge:
+        spec:
+          containers:
+          - name: proxy
+            image: envoy:v1.25
+`))
+	assert.NilError(t, err)
+	assert.Equal(t, len(Conflicts(policies[0], policies[1])), 0)
+	findings := Conflicts(policies[1], policies[2])
+	assert.Equal(t, len(findings), 2)
+	assert.Equal(t, findings[0].Message, "sets spec.containers.proxy.image also set by policy proxy-version rule set-image, the result depends on the order policies are applied in")
+}
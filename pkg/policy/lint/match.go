@@ -0,0 +1,118 @@
+package lint
+
+import (
+	"reflect"
+	"strings"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func lintMatchExclude(l *linter, path *field.Path, rule kyvernov1.Rule, _ map[string]interface{}) {
+	match, exclude := rule.MatchResources, rule.ExcludeResources
+	if len(match.All) > 1 {
+		if kinds, ok := intersect(match.All, func(rd kyvernov1.ResourceDescription) []string { return kindNames(rd.Kinds) }); ok && kinds.Len() == 0 {
+			l.report(UnmatchableRule, path.Child("match", "all"), "match.all filters have no kind in common, the rule can never match")
+		}
+		if namespaces, ok := intersect(match.All, func(rd kyvernov1.ResourceDescription) []string { return rd.Namespaces }); ok && namespaces.Len() == 0 {
+			l.report(UnmatchableRule, path.Child("match", "all"), "match.all filters have no namespace in common, the rule can never match")
+		}
+		return
+	}
+	matchFilters := filters(match)
+	excludeFilters := filters(exclude)
+	if len(matchFilters) == 0 || len(excludeFilters) == 0 {
+		return
+	}
+	for _, m := range matchFilters {
+		covered := false
+		for _, e := range excludeFilters {
+			if excludes(e, m) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return
+		}
+	}
+	l.report(UnmatchableRule, path.Child("exclude"), "every resource matched by the rule is excluded, the rule can never match")
+}
+
+// filters returns the filters of a match or exclude block, any of them matching is enough for the block to match
+func filters(m kyvernov1.MatchResources) kyvernov1.ResourceFilters {
+	if len(m.Any) > 0 {
+		return m.Any
+	}
+	if len(m.All) == 1 {
+		return m.All
+	}
+	if len(m.All) > 1 {
+		return nil
+	}
+	legacy := kyvernov1.ResourceFilter{UserInfo: m.UserInfo, ResourceDescription: m.ResourceDescription}
+	if legacy.IsEmpty() {
+		return nil
+	}
+	return kyvernov1.ResourceFilters{legacy}
+}
+
+// excludes returns true if every resource matched by filter m is also matched by filter e,
+// only exclude filters restricted to kinds and namespaces are considered
+func excludes(e, m kyvernov1.ResourceFilter) bool {
+	restricted := kyvernov1.ResourceFilter{ResourceDescription: kyvernov1.ResourceDescription{Kinds: e.Kinds, Namespaces: e.Namespaces}}
+	if !reflect.DeepEqual(e, restricted) || (len(e.Kinds) == 0 && len(e.Namespaces) == 0) {
+		return false
+	}
+	return covers(kindNames(e.Kinds), kindNames(m.Kinds)) && covers(e.Namespaces, m.Namespaces)
+}
+
+// covers returns true if every value is matched by one of the patterns, no patterns match everything
+func covers(patterns, values []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if len(values) == 0 {
+		return sets.New(patterns...).Has("*")
+	}
+	for _, value := range values {
+		if !wildcard.CheckPatterns(patterns, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// intersect computes the intersection of the values of the filters,
+// it returns false when a filter doesn't restrict the values or uses wildcards
+func intersect(filters kyvernov1.ResourceFilters, values func(kyvernov1.ResourceDescription) []string) (sets.Set[string], bool) {
+	var result sets.Set[string]
+	for _, f := range filters {
+		v := values(f.ResourceDescription)
+		if len(v) == 0 {
+			continue
+		}
+		for _, s := range v {
+			if wildcard.ContainsWildcard(s) {
+				return nil, false
+			}
+		}
+		if result == nil {
+			result = sets.New(v...)
+		} else {
+			result = result.Intersection(sets.New(v...))
+		}
+	}
+	return result, result != nil
+}
+
+// kindNames strips the group and version from kinds
+func kindNames(kinds []string) []string {
+	names := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		names = append(names, kind[strings.LastIndex(kind, "/")+1:])
+	}
+	return names
+}
@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/operator"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func lintPatterns(l *linter, path *field.Path, _ kyvernov1.Rule, raw map[string]interface{}) {
+	validate, ok := raw["validate"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	lintValidatePatterns(l, path.Child("validate"), validate)
+}
+
+func lintValidatePatterns(l *linter, path *field.Path, validate map[string]interface{}) {
+	for _, key := range []string{"pattern", "anyPattern"} {
+		if pattern, ok := validate[key]; ok {
+			walk(path.Child(key), pattern, func(p *field.Path, value interface{}) {
+				if s, ok := value.(string); ok && neverPasses(s) {
+					l.report(PatternNeverPasses, p, "pattern %q can never be satisfied", s)
+				}
+			})
+		}
+	}
+	if foreach, ok := validate["foreach"].([]interface{}); ok {
+		for i, fe := range foreach {
+			if m, ok := fe.(map[string]interface{}); ok {
+				lintValidatePatterns(l, path.Child("foreach").Index(i), m)
+			}
+		}
+	}
+}
+
+// neverPasses returns true if none of the alternatives of a string pattern can be satisfied
+func neverPasses(pattern string) bool {
+	if variables.RegexVariables.MatchString(pattern) {
+		return false
+	}
+	for _, alternative := range strings.Split(pattern, "|") {
+		if satisfiable(alternative) {
+			return false
+		}
+	}
+	return true
+}
+
+type bound struct {
+	value     float64
+	inclusive bool
+}
+
+// satisfiable checks the conditions of an alternative (joined with &) for contradictions,
+// numeric bounds are compared when they are all quantities or all durations
+func satisfiable(alternative string) bool {
+	lower := bound{value: math.Inf(-1), inclusive: true}
+	upper := bound{value: math.Inf(1), inclusive: true}
+	unit := ""
+	equal := ""
+	for _, condition := range strings.Split(alternative, "&") {
+		condition = strings.TrimSpace(condition)
+		op := operator.GetOperatorFromStringPattern(condition)
+		var left, right string
+		switch op {
+		case operator.NotInRange:
+			continue
+		case operator.InRange:
+			match := operator.InRangeRegex.FindStringSubmatch(condition)
+			left, right = match[1], match[2]
+		case operator.Equal:
+			if wildcard.ContainsWildcard(condition) {
+				continue
+			}
+			if equal != "" && equal != condition {
+				return false
+			}
+			equal = condition
+			continue
+		case operator.NotEqual:
+			continue
+		default:
+			left = strings.TrimSpace(condition[len(op):])
+		}
+		l, lu, ok := parseNumber(left)
+		if !ok || (unit != "" && lu != unit) {
+			return true
+		}
+		unit = lu
+		switch op {
+		case operator.InRange:
+			r, ru, ok := parseNumber(right)
+			if !ok || ru != unit {
+				return true
+			}
+			lower = maxBound(lower, bound{l, true})
+			upper = minBound(upper, bound{r, true})
+		case operator.More:
+			lower = maxBound(lower, bound{l, false})
+		case operator.MoreEqual:
+			lower = maxBound(lower, bound{l, true})
+		case operator.Less:
+			upper = minBound(upper, bound{l, false})
+		case operator.LessEqual:
+			upper = minBound(upper, bound{l, true})
+		}
+	}
+	if lower.value > upper.value {
+		return false
+	}
+	return lower.value != upper.value || (lower.inclusive && upper.inclusive)
+}
+
+// parseNumber parses a duration or a quantity, in the same order as the engine does,
+// the returned unit tells which one was parsed
+func parseNumber(s string) (float64, string, bool) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return float64(d), "duration", true
+	}
+	if q, err := resource.ParseQuantity(s); err == nil {
+		return q.AsApproximateFloat64(), "quantity", true
+	}
+	return 0, "", false
+}
+
+func maxBound(a, b bound) bound {
+	if b.value > a.value || (b.value == a.value && !b.inclusive) {
+		return b
+	}
+	return a
+}
+
+func minBound(a, b bound) bound {
+	if b.value < a.value || (b.value == a.value && !b.inclusive) {
+		return b
+	}
+	return a
+}
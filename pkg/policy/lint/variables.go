@@ -0,0 +1,116 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+
+	gojmespath "github.com/jmespath/go-jmespath"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/engine/variables"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// builtinVariables are the root variables always available to rules
+var builtinVariables = regexp.MustCompile(`^(request|serviceAccountName|serviceAccountNamespace|images|image|target|element\d*|elementIndex\d*)$`)
+
+func lintVariables(l *linter, path *field.Path, rule kyvernov1.Rule, _ map[string]interface{}) {
+	raw, err := toMap(rule)
+	if err != nil {
+		return
+	}
+	// variables in attestation conditions are evaluated against the attestation statements
+	if verifyImages, ok := raw["verifyImages"].([]interface{}); ok {
+		for _, vi := range verifyImages {
+			if attestations, ok := vi.(map[string]interface{})["attestations"].([]interface{}); ok {
+				for _, attestation := range attestations {
+					delete(attestation.(map[string]interface{}), "conditions")
+				}
+			}
+		}
+	}
+	entries := contextEntryNames(raw)
+	background := l.policy.GetSpec().BackgroundProcessingEnabled()
+	walk(path, raw, func(p *field.Path, value interface{}) {
+		s, ok := value.(string)
+		if !ok {
+			return
+		}
+		for _, match := range variables.RegexVariables.FindAllStringSubmatch(s, -1) {
+			expression := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(match[2], "{{"), "}}"))
+			ast, err := gojmespath.NewParser().Parse(expression)
+			if err != nil {
+				continue
+			}
+			for _, ref := range references(ast) {
+				root := ref[0]
+				if !builtinVariables.MatchString(root) && !entries[root] {
+					l.report(UndefinedVariable, p, "variable %s references %s which is neither a built-in variable nor a context entry", match[2], root)
+				}
+				if background && root == "request" && len(ref) > 1 && ref[1] != "object" {
+					l.report(BackgroundRequestVariable, p, "variable %s is not available in background scans, set spec.background to false", match[2])
+				}
+			}
+		}
+	})
+}
+
+// contextEntryNames collects the names of the context entries declared in the rule, including foreach ones
+func contextEntryNames(raw map[string]interface{}) map[string]bool {
+	names := map[string]bool{}
+	walk(nil, raw, func(_ *field.Path, value interface{}) {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		if entries, ok := m["context"].([]interface{}); ok {
+			for _, entry := range entries {
+				if e, ok := entry.(map[string]interface{}); ok {
+					if name, ok := e["name"].(string); ok {
+						names[name] = true
+					}
+				}
+			}
+		}
+	})
+	return names
+}
+
+// references returns the fields read from the root of the expression, with at most two path segments
+func references(node gojmespath.ASTNode) [][]string {
+	switch node.NodeType {
+	case gojmespath.ASTField:
+		if name, ok := node.Value.(string); ok {
+			return [][]string{{name}}
+		}
+	case gojmespath.ASTSubexpression:
+		left := references(node.Children[0])
+		if len(left) == 1 && len(left[0]) == 1 && node.Children[1].NodeType == gojmespath.ASTField {
+			if name, ok := node.Children[1].Value.(string); ok {
+				return [][]string{{left[0][0], name}}
+			}
+		}
+		return left
+	case gojmespath.ASTIndexExpression,
+		gojmespath.ASTProjection,
+		gojmespath.ASTValueProjection,
+		gojmespath.ASTFilterProjection,
+		gojmespath.ASTFlatten,
+		gojmespath.ASTPipe:
+		// the right hand side is evaluated against the result of the left hand side
+		return references(node.Children[0])
+	case gojmespath.ASTFunctionExpression,
+		gojmespath.ASTComparator,
+		gojmespath.ASTOrExpression,
+		gojmespath.ASTAndExpression,
+		gojmespath.ASTNotExpression,
+		gojmespath.ASTMultiSelectList,
+		gojmespath.ASTMultiSelectHash,
+		gojmespath.ASTKeyValPair:
+		var refs [][]string
+		for _, child := range node.Children {
+			refs = append(refs, references(child)...)
+		}
+		return refs
+	}
+	return nil
+}
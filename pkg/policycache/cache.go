@@ -1,8 +1,13 @@
 package policycache
 
 import (
+	"sync"
+
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	policyutils "github.com/kyverno/kyverno/pkg/utils/policy"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
 	"github.com/kyverno/kyverno/pkg/utils/wildcard"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // Cache get method use for to get policy names and mostly use to test cache testcases
@@ -11,28 +16,78 @@ type Cache interface {
 	Set(string, kyvernov1.PolicyInterface, map[string]string)
 	// Unset removes a policy from the cache
 	Unset(string)
+	// Has returns true if a policy is in the cache
+	Has(string) bool
 	// GetPolicies returns all policies that apply to a namespace, including cluster-wide policies
 	// If the namespace is empty, only cluster-wide policies are returned
 	GetPolicies(PolicyType, string, string) []kyvernov1.PolicyInterface
+	// GetScopeID returns the webhook scope identifier of a policy
+	// The scope is computed once per policy revision
+	GetScopeID(kyvernov1.PolicyInterface) string
+}
+
+type scopeEntry struct {
+	resourceVersion string
+	id              string
 }
 
 type cache struct {
-	store store
+	store  store
+	lock   sync.RWMutex
+	scopes map[types.UID]scopeEntry
+	uids   map[string]types.UID
 }
 
 // NewCache create a new Cache
 func NewCache() Cache {
 	return &cache{
-		store: newPolicyCache(),
+		store:  newPolicyCache(),
+		scopes: map[types.UID]scopeEntry{},
+		uids:   map[string]types.UID{},
 	}
 }
 
 func (c *cache) Set(key string, policy kyvernov1.PolicyInterface, subresourceGVKToKind map[string]string) {
 	c.store.set(key, policy, subresourceGVKToKind)
+	c.setScope(policy)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.uids[key] = policy.GetUID()
 }
 
 func (c *cache) Unset(key string) {
 	c.store.unset(key)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if uid, ok := c.uids[key]; ok {
+		delete(c.scopes, uid)
+		delete(c.uids, key)
+	}
+}
+
+func (c *cache) GetScopeID(policy kyvernov1.PolicyInterface) string {
+	c.lock.RLock()
+	entry, ok := c.scopes[policy.GetUID()]
+	c.lock.RUnlock()
+	if ok && entry.resourceVersion == policy.GetResourceVersion() {
+		return entry.id
+	}
+	return c.setScope(policy)
+}
+
+func (c *cache) setScope(policy kyvernov1.PolicyInterface) string {
+	id := scopeutils.Compute(policy).ID()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.scopes[policy.GetUID()] = scopeEntry{
+		resourceVersion: policy.GetResourceVersion(),
+		id:              id,
+	}
+	return id
+}
+
+func (c *cache) Has(key string) bool {
+	return c.store.has(key)
 }
 
 func (c *cache) GetPolicies(pkey PolicyType, kind, nspace string) []kyvernov1.PolicyInterface {
@@ -53,6 +108,11 @@ func (c *cache) GetPolicies(pkey PolicyType, kind, nspace string) []kyvernov1.Po
 		result = filterPolicies(pkey, result, nspace, kind)
 	}
 
+	// mutate policies are applied in order, one after the other
+	if pkey == Mutate {
+		policyutils.SortByOrder(result)
+	}
+
 	return result
 }
 
@@ -6,7 +6,9 @@ import (
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/autogen"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
 	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubecache "k8s.io/client-go/tools/cache"
 )
 
@@ -1261,3 +1263,49 @@ func Test_Get_Policies_Validate_Failure_Action_Overrides(t *testing.T) {
 	}
 
 }
+
+func Test_Get_Policies_Mutate_Order(t *testing.T) {
+	cache := NewCache()
+	var order int32 = -1
+	for _, name := range []string{"d", "c", "b", "a"} {
+		policy := newMutatePolicy(t)
+		policy.SetName(name)
+		if name == "c" {
+			policy.Spec.Order = &order
+		}
+		key, _ := kubecache.MetaNamespaceKeyFunc(policy)
+		cache.Set(key, policy, make(map[string]string))
+	}
+	nsPolicy := newNsMutatePolicy(t)
+	nsPolicy.SetName("a")
+	key, _ := kubecache.MetaNamespaceKeyFunc(nsPolicy)
+	cache.Set(key, nsPolicy, make(map[string]string))
+
+	var names []string
+	for _, policy := range cache.GetPolicies(Mutate, "StatefulSet", nsPolicy.GetNamespace()) {
+		names = append(names, policy.GetNamespace()+"/"+policy.GetName())
+	}
+	assert.DeepEqual(t, names, []string{"/c", "/a", "logger/a", "/b", "/d"})
+}
+
+func Test_GetScopeID(t *testing.T) {
+	c := NewCache().(*cache)
+	policy := newPolicy(t)
+	policy.SetUID("uid")
+	policy.SetResourceVersion("1")
+	key, _ := kubecache.MetaNamespaceKeyFunc(policy)
+	c.Set(key, policy, make(map[string]string))
+	id := c.GetScopeID(policy)
+	assert.Equal(t, id, scopeutils.Compute(policy).ID())
+	assert.Equal(t, c.scopes[policy.GetUID()], scopeEntry{resourceVersion: "1", id: id})
+
+	// a new revision is computed again
+	updated := policy.DeepCopy()
+	updated.SetResourceVersion("2")
+	updated.Spec.Rules[0].MatchResources.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}}
+	assert.Equal(t, c.GetScopeID(updated), scopeutils.Compute(updated).ID())
+	assert.Equal(t, c.scopes[policy.GetUID()].resourceVersion, "2")
+
+	c.Unset(key)
+	assert.Equal(t, len(c.scopes), 0)
+}
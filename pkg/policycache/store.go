@@ -15,6 +15,8 @@ type store interface {
 	set(string, kyvernov1.PolicyInterface, map[string]string)
 	// unset removes a policy from the cache
 	unset(string)
+	// has returns true if a policy is in the cache
+	has(string) bool
 	// get finds policies that match a given type, gvk and namespace
 	get(PolicyType, string, string) []kyvernov1.PolicyInterface
 }
@@ -44,6 +46,12 @@ func (pc *policyCache) unset(key string) {
 	logger.V(4).Info("policy is removed from cache", "key", key)
 }
 
+func (pc *policyCache) has(key string) bool {
+	pc.lock.RLock()
+	defer pc.lock.RUnlock()
+	return pc.store.has(key)
+}
+
 func (pc *policyCache) get(pkey PolicyType, kind, nspace string) []kyvernov1.PolicyInterface {
 	pc.lock.RLock()
 	defer pc.lock.RUnlock()
@@ -146,6 +154,11 @@ func (m *policyMap) unset(key string) {
 	}
 }
 
+func (m *policyMap) has(key string) bool {
+	_, ok := m.policies[key]
+	return ok
+}
+
 func (m *policyMap) get(key PolicyType, gvk, namespace string) []kyvernov1.PolicyInterface {
 	kind := computeKind(gvk)
 	var result []kyvernov1.PolicyInterface
@@ -16,6 +16,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/google"
 	gcrremote "github.com/google/go-containerregistry/pkg/v1/remote"
+	kconfig "github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/tracing"
 	"github.com/sigstore/cosign/pkg/oci/remote"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -72,8 +73,11 @@ type client struct {
 type config struct {
 	keychain            authn.Keychain
 	transport           *http.Transport
+	roundTripper        http.RoundTripper
 	pullSecretRefresher func(context.Context, *client) error
 	tracing             bool
+	metrics             bool
+	metricsConfig       kconfig.MetricsConfiguration
 }
 
 // Option is an option to initialize registry client.
@@ -95,8 +99,14 @@ func New(options ...Option) (Client, error) {
 		transport:           cfg.transport,
 		pullSecretRefresher: cfg.pullSecretRefresher,
 	}
+	if cfg.roundTripper != nil {
+		c.transport = cfg.roundTripper
+	}
+	if cfg.metrics {
+		c.transport = newMetricsTransport(c.transport, cfg.metricsConfig)
+	}
 	if cfg.tracing {
-		c.transport = tracing.Transport(cfg.transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan))
+		c.transport = tracing.Transport(c.transport, otelhttp.WithFilter(tracing.RequestFilterIsInSpan))
 	}
 	return c, nil
 }
@@ -145,6 +155,14 @@ func WithLocalKeychain() Option {
 	}
 }
 
+// WithTransport replaces the http transport used to communicate with registries.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *config) error {
+		c.roundTripper = transport
+		return nil
+	}
+}
+
 // WithTracing enables tracing in the http client.
 func WithTracing() Option {
 	return func(c *config) error {
@@ -153,6 +171,16 @@ func WithTracing() Option {
 	}
 }
 
+// WithMetrics enables metrics in the http client, the namespace filters of the metrics configuration
+// apply to the namespace recorded in the request context with NamespaceContext.
+func WithMetrics(metricsConfig kconfig.MetricsConfiguration) Option {
+	return func(c *config) error {
+		c.metrics = true
+		c.metricsConfig = metricsConfig
+		return nil
+	}
+}
+
 // BuildRemoteOption builds remote.Option based on client.
 func (c *client) BuildRemoteOption(ctx context.Context) remote.Option {
 	return remote.WithRemoteOptions(
@@ -172,7 +200,7 @@ func (c *client) FetchImageDescriptor(ctx context.Context, imageRef string) (*gc
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse image reference: %s, error: %v", imageRef, err)
 	}
-	desc, err := gcrremote.Get(parsedRef, gcrremote.WithAuthFromKeychain(c.keychain), gcrremote.WithContext(ctx))
+	desc, err := gcrremote.Get(parsedRef, gcrremote.WithAuthFromKeychain(c.keychain), gcrremote.WithTransport(c.transport), gcrremote.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch image reference: %s, error: %v", imageRef, err)
 	}
@@ -0,0 +1,70 @@
+package registryclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	kconfig "github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+)
+
+// meterName must match metrics.MeterName, the metrics package can't be imported here because it depends on the engine api
+const meterName = "kyverno"
+
+type namespaceKey struct{}
+
+// NamespaceContext returns a context recording the namespace of the policy the registry requests are sent for
+func NamespaceContext(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// metricsTransport records the duration of the requests sent to registries
+type metricsTransport struct {
+	base          http.RoundTripper
+	metricsConfig kconfig.MetricsConfiguration
+	duration      syncfloat64.Histogram
+}
+
+func newMetricsTransport(base http.RoundTripper, metricsConfig kconfig.MetricsConfiguration) http.RoundTripper {
+	meter := global.MeterProvider().Meter(meterName)
+	duration, err := meter.SyncFloat64().Histogram(
+		"kyverno_registry_request_duration_seconds",
+		instrument.WithDescription("can be used to track the latencies (in seconds) associated with the requests sent to image registries, by registry host"),
+	)
+	if err != nil {
+		logging.WithName("RegistryClient").Error(err, "Failed to create instrument, kyverno_registry_request_duration_seconds")
+		return base
+	}
+	return &metricsTransport{
+		base:          base,
+		metricsConfig: metricsConfig,
+		duration:      duration,
+	}
+}
+
+func (t *metricsTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	startTime := time.Now()
+	response, err := t.base.RoundTrip(request)
+	namespace, _ := request.Context().Value(namespaceKey{}).(string)
+	if t.metricsConfig != nil && !t.metricsConfig.CheckNamespace(namespace) {
+		return response, err
+	}
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(response.StatusCode)
+	}
+	t.duration.Record(
+		request.Context(),
+		time.Since(startTime).Seconds(),
+		attribute.String("registry", request.URL.Host),
+		attribute.String("method", request.Method),
+		attribute.String("status_code", status),
+	)
+	return response, err
+}
@@ -0,0 +1,55 @@
+package registryclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kconfig "github.com/kyverno/kyverno/pkg/config"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gotest.tools/assert"
+)
+
+// metricsConfiguration excludes a namespace from metrics
+type metricsConfiguration struct {
+	kconfig.MetricsConfiguration
+	excluded string
+}
+
+func (c metricsConfiguration) CheckNamespace(namespace string) bool {
+	return namespace != c.excluded
+}
+
+func TestMetricsTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	duration, err := provider.Meter(meterName).SyncFloat64().Histogram("kyverno_registry_request_duration_seconds")
+	assert.NilError(t, err)
+	transport := &metricsTransport{base: http.DefaultTransport, metricsConfig: metricsConfiguration{excluded: "excluded"}, duration: duration}
+	for _, namespace := range []string{"included", "excluded"} {
+		request, err := http.NewRequestWithContext(NamespaceContext(context.Background(), namespace), http.MethodGet, server.URL+"/v2/", nil)
+		assert.NilError(t, err)
+		response, err := transport.RoundTrip(request)
+		assert.NilError(t, err)
+		response.Body.Close()
+	}
+
+	data, err := reader.Collect(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, len(data.ScopeMetrics), 1)
+	histogram := data.ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram)
+	assert.Equal(t, len(histogram.DataPoints), 1)
+	point := histogram.DataPoints[0]
+	assert.Equal(t, point.Count, uint64(1))
+	status, _ := point.Attributes.Value(attribute.Key("status_code"))
+	assert.Equal(t, status.AsString(), "404")
+	registry, _ := point.Attributes.Value(attribute.Key("registry"))
+	assert.Equal(t, registry.AsString(), server.Listener.Addr().String())
+}
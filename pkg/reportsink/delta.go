@@ -0,0 +1,65 @@
+package reportsink
+
+import (
+	"sort"
+
+	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Delta is a change of the aggregated policy results of a namespace,
+// the namespace is empty for cluster wide results
+type Delta struct {
+	Namespace string                                    `json:"namespace,omitempty"`
+	Timestamp metav1.Time                               `json:"timestamp"`
+	New       []policyreportv1alpha2.PolicyReportResult `json:"new,omitempty"`
+	Resolved  []policyreportv1alpha2.PolicyReportResult `json:"resolved,omitempty"`
+}
+
+func (d Delta) IsEmpty() bool {
+	return len(d.New) == 0 && len(d.Resolved) == 0
+}
+
+// ComputeDelta compares the results of a namespace before and after aggregation,
+// a result whose status changed is reported both as resolved (old status) and new (new status)
+func ComputeDelta(namespace string, before, after []policyreportv1alpha2.PolicyReportResult) Delta {
+	delta := Delta{Namespace: namespace, Timestamp: metav1.Now()}
+	previous := indexResults(before)
+	current := indexResults(after)
+	for _, key := range sortedKeys(current) {
+		if _, ok := previous[key]; !ok {
+			delta.New = append(delta.New, current[key])
+		}
+	}
+	for _, key := range sortedKeys(previous) {
+		if _, ok := current[key]; !ok {
+			delta.Resolved = append(delta.Resolved, previous[key])
+		}
+	}
+	return delta
+}
+
+func resultKey(result policyreportv1alpha2.PolicyReportResult) string {
+	key := result.Policy + "/" + result.Rule + "/" + string(result.Result)
+	for _, resource := range result.Resources {
+		key += "/" + string(resource.UID)
+	}
+	return key
+}
+
+func indexResults(results []policyreportv1alpha2.PolicyReportResult) map[string]policyreportv1alpha2.PolicyReportResult {
+	index := make(map[string]policyreportv1alpha2.PolicyReportResult, len(results))
+	for _, result := range results {
+		index[resultKey(result)] = result
+	}
+	return index
+}
+
+func sortedKeys(m map[string]policyreportv1alpha2.PolicyReportResult) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
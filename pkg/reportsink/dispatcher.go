@@ -0,0 +1,139 @@
+package reportsink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/controllers"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Sink delivers deltas to an external system
+type Sink interface {
+	// Name identifies the sink in logs
+	Name() string
+	// Send delivers a delta, errors wrapped with Permanent are not retried
+	Send(context.Context, Delta) error
+}
+
+// Interface to publish deltas
+type Interface interface {
+	Publish(Delta)
+}
+
+// Dispatcher publishes deltas to sinks, each sink has its own queue so that a slow sink
+// doesn't delay the others, deltas are delivered in order
+type Dispatcher interface {
+	controllers.Controller
+	Interface
+}
+
+// DefaultBackoff is the retry policy used when delivering a delta fails
+var DefaultBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+	Cap:      time.Minute,
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as not worth retrying
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+type dispatcher struct {
+	logger  logr.Logger
+	backoff wait.Backoff
+	queues  []*sinkQueue
+}
+
+type sinkQueue struct {
+	sink   Sink
+	deltas chan Delta
+}
+
+// NewDispatcher creates a dispatcher for the given sinks, queueSize is the number of deltas
+// buffered per sink, deltas published when a queue is full are dropped
+func NewDispatcher(logger logr.Logger, backoff wait.Backoff, queueSize int, sinks ...Sink) Dispatcher {
+	d := &dispatcher{
+		logger:  logger,
+		backoff: backoff,
+	}
+	for _, sink := range sinks {
+		d.queues = append(d.queues, &sinkQueue{sink: sink, deltas: make(chan Delta, queueSize)})
+	}
+	return d
+}
+
+func (d *dispatcher) Publish(delta Delta) {
+	if delta.IsEmpty() {
+		return
+	}
+	for _, queue := range d.queues {
+		select {
+		case queue.deltas <- delta:
+		default:
+			d.logger.Error(errors.New("queue is full"), "dropping report delta", "sink", queue.sink.Name(), "namespace", delta.Namespace)
+		}
+	}
+}
+
+func (d *dispatcher) Run(ctx context.Context, _ int) {
+	logger := d.logger
+	logger.Info("start")
+	defer logger.Info("shutting down")
+	var wg sync.WaitGroup
+	for _, queue := range d.queues {
+		wg.Add(1)
+		go func(queue *sinkQueue) {
+			defer wg.Done()
+			logger := logger.WithValues("sink", queue.sink.Name())
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delta := <-queue.deltas:
+					if err := d.deliver(ctx, queue.sink, delta); err != nil {
+						logger.Error(err, "failed to deliver report delta", "namespace", delta.Namespace, "new", len(delta.New), "resolved", len(delta.Resolved))
+					}
+				}
+			}
+		}(queue)
+	}
+	wg.Wait()
+}
+
+func (d *dispatcher) deliver(ctx context.Context, sink Sink, delta Delta) error {
+	backoff := d.backoff
+	for {
+		err := sink.Send(ctx, delta)
+		if err == nil || isPermanent(err) {
+			return err
+		}
+		if backoff.Steps <= 1 {
+			return err
+		}
+		d.logger.V(3).Info("retrying report delta delivery", "sink", sink.Name(), "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
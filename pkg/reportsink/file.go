@@ -0,0 +1,35 @@
+package reportsink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	osutils "github.com/kyverno/kyverno/pkg/utils/os"
+)
+
+type fileSink struct {
+	writer io.Writer
+}
+
+// NewFileSink creates a sink appending deltas as JSON lines to the given file,
+// the file is rotated when it grows over maxSize bytes (0 disables rotation),
+// rotated files are suffixed with .1 (most recent) up to .<maxBackups>
+func NewFileSink(path string, maxSize int64, maxBackups int) Sink {
+	return &fileSink{
+		writer: osutils.NewRotatingFile(path, maxSize, maxBackups),
+	}
+}
+
+func (s *fileSink) Name() string {
+	return "file"
+}
+
+func (s *fileSink) Send(_ context.Context, delta Delta) error {
+	line, err := json.Marshal(delta)
+	if err != nil {
+		return Permanent(err)
+	}
+	_, err = s.writer.Write(append(line, '\n'))
+	return err
+}
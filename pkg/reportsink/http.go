@@ -0,0 +1,105 @@
+package reportsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	// CloudEventType is the type of the cloud events sent by the cloud events sink
+	CloudEventType = "io.kyverno.policyreport.delta.v1"
+	// DefaultCloudEventSource is the source of the cloud events when none is configured
+	DefaultCloudEventSource = "kyverno/reports-controller"
+)
+
+type httpSink struct {
+	name    string
+	url     string
+	client  *http.Client
+	headers func(Delta) map[string]string
+}
+
+// NewWebhookSink creates a sink posting deltas as JSON to the given url
+func NewWebhookSink(url string, headers map[string]string, client *http.Client) Sink {
+	return &httpSink{
+		name:   "webhook",
+		url:    url,
+		client: defaultClient(client),
+		headers: func(Delta) map[string]string {
+			return headers
+		},
+	}
+}
+
+// NewCloudEventsSink creates a sink posting deltas as cloud events (HTTP binary content mode) to the given url
+func NewCloudEventsSink(url string, source string, client *http.Client) Sink {
+	if source == "" {
+		source = DefaultCloudEventSource
+	}
+	return &httpSink{
+		name:   "cloudevents",
+		url:    url,
+		client: defaultClient(client),
+		headers: func(delta Delta) map[string]string {
+			headers := map[string]string{
+				"ce-specversion": "1.0",
+				"ce-id":          string(uuid.NewUUID()),
+				"ce-source":      source,
+				"ce-type":        CloudEventType,
+				"ce-time":        delta.Timestamp.UTC().Format(time.RFC3339Nano),
+			}
+			if delta.Namespace != "" {
+				headers["ce-subject"] = delta.Namespace
+			}
+			return headers
+		},
+	}
+}
+
+func defaultClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+func (s *httpSink) Name() string {
+	return s.name
+}
+
+func (s *httpSink) Send(ctx context.Context, delta Delta) error {
+	body, err := json.Marshal(delta)
+	if err != nil {
+		return Permanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers(delta) {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("%s returned status %s", s.url, resp.Status)
+	// client errors won't be fixed by retrying, except timeouts and throttling
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+package reportsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func result(policy, rule string, status policyreportv1alpha2.PolicyResult, uid string) policyreportv1alpha2.PolicyReportResult {
+	return policyreportv1alpha2.PolicyReportResult{
+		Policy:    policy,
+		Rule:      rule,
+		Result:    status,
+		Resources: []corev1.ObjectReference{{UID: types.UID(uid)}},
+	}
+}
+
+var testBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 5}
+
+func Test_ComputeDelta(t *testing.T) {
+	before := []policyreportv1alpha2.PolicyReportResult{
+		result("p", "r1", policyreportv1alpha2.StatusFail, "a"),
+		result("p", "r2", policyreportv1alpha2.StatusPass, "a"),
+		result("p", "r1", policyreportv1alpha2.StatusFail, "b"),
+	}
+	after := []policyreportv1alpha2.PolicyReportResult{
+		result("p", "r1", policyreportv1alpha2.StatusPass, "a"),
+		result("p", "r2", policyreportv1alpha2.StatusPass, "a"),
+		result("p", "r1", policyreportv1alpha2.StatusFail, "c"),
+	}
+	delta := ComputeDelta("default", before, after)
+	assert.Equal(t, delta.Namespace, "default")
+	assert.Equal(t, len(delta.New), 2)
+	assert.Equal(t, len(delta.Resolved), 2)
+	assert.Equal(t, delta.New[0].Result, policyreportv1alpha2.PolicyResult(policyreportv1alpha2.StatusFail))
+	assert.Equal(t, delta.New[0].Resources[0].UID, types.UID("c"))
+	assert.Equal(t, delta.New[1].Result, policyreportv1alpha2.PolicyResult(policyreportv1alpha2.StatusPass))
+	assert.Equal(t, delta.Resolved[0].Resources[0].UID, types.UID("a"))
+	assert.Equal(t, delta.Resolved[1].Resources[0].UID, types.UID("b"))
+	assert.Assert(t, ComputeDelta("default", after, after).IsEmpty())
+}
+
+func Test_WebhookSinkRetries(t *testing.T) {
+	var calls int32
+	received := make(chan Delta, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Authorization"), "Bearer token")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var delta Delta
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&delta))
+		received <- delta
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := NewDispatcher(logr.Discard(), testBackoff, 10, NewWebhookSink(server.URL, map[string]string{"Authorization": "Bearer token"}, nil))
+	go dispatcher.Run(ctx, 1)
+	dispatcher.Publish(Delta{Namespace: "empty"})
+	dispatcher.Publish(ComputeDelta("default", nil, []policyreportv1alpha2.PolicyReportResult{result("p", "r", policyreportv1alpha2.StatusFail, "a")}))
+	select {
+	case delta := <-received:
+		assert.Equal(t, delta.Namespace, "default")
+		assert.Equal(t, len(delta.New), 1)
+	case <-time.After(5 * time.Second):
+		t.Fatal("delta not delivered")
+	}
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(2))
+}
+
+func Test_CloudEventsSink(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer server.Close()
+	sink := NewCloudEventsSink(server.URL, "", nil)
+	assert.NilError(t, sink.Send(context.Background(), ComputeDelta("default", nil, nil)))
+	assert.Equal(t, header.Get("ce-specversion"), "1.0")
+	assert.Equal(t, header.Get("ce-type"), CloudEventType)
+	assert.Equal(t, header.Get("ce-source"), DefaultCloudEventSource)
+	assert.Equal(t, header.Get("ce-subject"), "default")
+	assert.Assert(t, header.Get("ce-id") != "")
+	assert.Equal(t, header.Get("Content-Type"), "application/json")
+}
+
+func Test_PermanentError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	d := NewDispatcher(logr.Discard(), testBackoff, 1).(*dispatcher)
+	err := d.deliver(context.Background(), NewWebhookSink(server.URL, nil, nil), Delta{})
+	assert.Assert(t, isPermanent(err))
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(1))
+}
+
+func Test_FileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deltas.jsonl")
+	delta := ComputeDelta("default", nil, []policyreportv1alpha2.PolicyReportResult{result("p", "r", policyreportv1alpha2.StatusFail, "a")})
+	line, err := json.Marshal(delta)
+	assert.NilError(t, err)
+	// room for two lines per file
+	sink := NewFileSink(path, int64(2*(len(line)+1)), 2)
+	for i := 0; i < 7; i++ {
+		assert.NilError(t, sink.Send(context.Background(), delta))
+	}
+	countLines := func(path string) int {
+		f, err := os.Open(path)
+		assert.NilError(t, err)
+		defer f.Close()
+		count := 0
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var decoded Delta
+			assert.NilError(t, json.Unmarshal(scanner.Bytes(), &decoded))
+			count++
+		}
+		return count
+	}
+	assert.Equal(t, countLines(path), 1)
+	assert.Equal(t, countLines(path+".1"), 2)
+	assert.Equal(t, countLines(path+".2"), 2)
+	_, err = os.Stat(path + ".3")
+	assert.Assert(t, os.IsNotExist(err))
+}
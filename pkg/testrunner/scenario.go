@@ -148,9 +148,10 @@ func runTestCase(t *testing.T, tc TestCase) bool {
 	policyContext := engine.NewPolicyContext().WithPolicy(policy).WithNewResource(*resource)
 	eng := engine.NewEngine(
 		config.NewDefaultConfiguration(),
+		config.NewDefaultMetricsConfiguration(),
 		nil,
 		registryclient.NewOrDie(),
-		engine.LegacyContextLoaderFactory(nil),
+		engine.LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()),
 		nil,
 	)
 	er := eng.Mutate(
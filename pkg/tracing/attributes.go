@@ -13,6 +13,12 @@ const (
 	PolicyNameKey      = attribute.Key("kyverno.policy.name")
 	PolicyNamespaceKey = attribute.Key("kyverno.policy.namespace")
 	RuleNameKey        = attribute.Key("kyverno.rule.name")
+	RuleTypeKey        = attribute.Key("kyverno.rule.type")
+	RuleStatusKey      = attribute.Key("kyverno.rule.status")
+	RuleMessageKey     = attribute.Key("kyverno.rule.message")
+	// context entry attributes
+	ContextEntryNameKey = attribute.Key("kyverno.context.entry.name")
+	ContextEntryTypeKey = attribute.Key("kyverno.context.entry.type")
 	// admission resource attributes
 	// ResourceNameKey       = attribute.Key("admission.resource.name")
 	// ResourceNamespaceKey  = attribute.Key("admission.resource.namespace")
@@ -17,8 +17,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-// NewTraceConfig generates the initial tracing configuration with 'address' as the endpoint to connect to the Opentelemetry Collector
-func NewTraceConfig(log logr.Logger, tracerName, address, certs string, kubeClient kubernetes.Interface) (func(), error) {
+// NewTraceConfig generates the initial tracing configuration with 'address' as the endpoint to connect to the Opentelemetry Collector,
+// root spans are sampled according to 'sampleRatio', child spans follow the decision of their parent
+func NewTraceConfig(log logr.Logger, tracerName, address, certs string, sampleRatio float64, kubeClient kubernetes.Interface) (func(), error) {
 	ctx := context.Background()
 	var client otlptrace.Client
 	if certs != "" {
@@ -59,6 +60,7 @@ func NewTraceConfig(log logr.Logger, tracerName, address, certs string, kubeClie
 	tp := sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(traceExp),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
 	)
 	// set global propagator to tracecontext (the default is no-op).
 	otel.SetTextMapPropagator(propagation.TraceContext{})
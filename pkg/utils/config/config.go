@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	"github.com/kyverno/kyverno/pkg/client/clientset/versioned"
+	"github.com/kyverno/kyverno/pkg/config"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Controller identifies the controller loading a KyvernoConfiguration
+type Controller string
+
+const (
+	Admission  Controller = "admission"
+	Background Controller = "background"
+	Cleanup    Controller = "cleanup"
+	Reports    Controller = "reports"
+)
+
+// Settings converts a KyvernoConfiguration into the configuration settings of a controller, unset values get their defaults
+func Settings(kyvernoConfiguration *kyvernov2alpha1.KyvernoConfiguration, controller Controller) config.Settings {
+	spec := kyvernoConfiguration.Spec
+	settings := config.Settings{
+		DefaultRegistry:               "docker.io",
+		EnableDefaultRegistryMutation: true,
+		ExcludeGroupRole:              spec.ExcludeGroups,
+		ResourceVersion:               "KyvernoConfiguration/" + kyvernoConfiguration.GetResourceVersion(),
+	}
+	if spec.DefaultRegistry != "" {
+		settings.DefaultRegistry = spec.DefaultRegistry
+	}
+	if spec.EnableDefaultRegistryMutation != nil {
+		settings.EnableDefaultRegistryMutation = *spec.EnableDefaultRegistryMutation
+	}
+	settings.Filters = filters(spec.ResourceFilters...)
+	if spec.Admission != nil {
+		settings.ExcludeUsername = spec.Admission.ExcludeUsernames
+		for _, webhook := range spec.Admission.Webhooks {
+			settings.Webhooks = append(settings.Webhooks, config.WebhookConfig{
+				NamespaceSelector: webhook.NamespaceSelector,
+				ObjectSelector:    webhook.ObjectSelector,
+			})
+		}
+	}
+	if spec.Reports != nil {
+		settings.GenerateSuccessEvents = spec.Reports.GenerateSuccessEvents
+	}
+	// controller sections only apply to their controller
+	if controller == Background && spec.Background != nil {
+		settings.Filters = append(settings.Filters, filters(spec.Background.ResourceFilters...)...)
+	}
+	if controller == Cleanup && spec.Cleanup != nil {
+		settings.Filters = append(settings.Filters, filters(spec.Cleanup.ResourceFilters...)...)
+	}
+	return settings
+}
+
+// Load loads the KyvernoConfiguration into the configuration if it is valid, it returns the validation errors otherwise
+func Load(configuration config.Configuration, kyvernoConfiguration *kyvernov2alpha1.KyvernoConfiguration, controller Controller) field.ErrorList {
+	if errs := kyvernoConfiguration.Validate(); len(errs) != 0 {
+		return errs
+	}
+	configuration.LoadSettings(Settings(kyvernoConfiguration, controller))
+	return nil
+}
+
+// LoadFromClient fetches the KyvernoConfiguration and loads it into the configuration,
+// the configuration is unchanged if the KyvernoConfiguration doesn't exist or is invalid
+func LoadFromClient(ctx context.Context, configuration config.Configuration, client versioned.Interface, controller Controller) error {
+	kyvernoConfiguration, err := client.KyvernoV2alpha1().KyvernoConfigurations().Get(ctx, config.KyvernoConfigurationName(), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if errs := Load(configuration, kyvernoConfiguration, controller); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+	return nil
+}
+
+func filters(resourceFilters ...kyvernov2alpha1.ResourceFilter) []config.Filter {
+	var result []config.Filter
+	for _, filter := range resourceFilters {
+		result = append(result, config.Filter{
+			Group:             filter.Group,
+			Version:           filter.Version,
+			Kind:              wildcardIfEmpty(filter.Kind),
+			Subresource:       filter.Subresource,
+			Namespace:         wildcardIfEmpty(filter.Namespace),
+			Name:              wildcardIfEmpty(filter.Name),
+			Selector:          selector(filter.Selector),
+			NamespaceSelector: selector(filter.NamespaceSelector),
+			Usernames:         filter.Usernames,
+			UserGroups:        filter.UserGroups,
+		})
+	}
+	return result
+}
+
+// selector converts a validated label selector, nil selects any labels
+func selector(labelSelector *metav1.LabelSelector) labels.Selector {
+	if labelSelector == nil {
+		return nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return labels.Nothing()
+	}
+	return selector
+}
+
+func wildcardIfEmpty(value string) string {
+	if value == "" {
+		return "*"
+	}
+	return value
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"testing"
+
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	"github.com/kyverno/kyverno/pkg/config"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Load(t *testing.T) {
+	disabled := false
+	kyvernoConfiguration := &kyvernov2alpha1.KyvernoConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyverno"},
+		Spec: kyvernov2alpha1.KyvernoConfigurationSpec{
+			DefaultRegistry:               "registry.example.com",
+			EnableDefaultRegistryMutation: &disabled,
+			ResourceFilters: []kyvernov2alpha1.ResourceFilter{
+				{Kind: "Event"},
+				{Namespace: "kube-system"},
+			},
+			ExcludeGroups: []string{"system:masters"},
+			Admission: &kyvernov2alpha1.AdmissionConfiguration{
+				ExcludeUsernames: []string{"admin"},
+				Webhooks: []kyvernov2alpha1.WebhookConfiguration{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				}},
+			},
+			Reports: &kyvernov2alpha1.ReportsConfiguration{GenerateSuccessEvents: true},
+		},
+	}
+	cfg := config.NewDefaultConfiguration()
+	errs := Load(cfg, kyvernoConfiguration, Admission)
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, cfg.GetDefaultRegistry(), "registry.example.com")
+	assert.Equal(t, cfg.GetEnableDefaultRegistryMutation(), false)
+	assert.Assert(t, cfg.ToFilter("Event", "default", "test"))
+	assert.Assert(t, cfg.ToFilter("Pod", "kube-system", "test"))
+	assert.Assert(t, !cfg.ToFilter("Pod", "default", "test"))
+	assert.DeepEqual(t, cfg.GetExcludeGroupRole(), []string{"system:masters", "system:serviceaccounts:kube-system", "system:nodes", "system:kube-scheduler"})
+	assert.DeepEqual(t, cfg.GetExcludeUsername(), []string{"admin"})
+	assert.Equal(t, len(cfg.GetWebhooks()), 1)
+	assert.Equal(t, cfg.GetGenerateSuccessEvents(), true)
+
+	// unset values get their defaults
+	errs = Load(cfg, &kyvernov2alpha1.KyvernoConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "kyverno"}}, Admission)
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, cfg.GetDefaultRegistry(), "docker.io")
+	assert.Equal(t, cfg.GetEnableDefaultRegistryMutation(), true)
+	assert.Assert(t, !cfg.ToFilter("Event", "default", "test"))
+	assert.Equal(t, cfg.GetGenerateSuccessEvents(), false)
+}
+
+func Test_Load_ResourceFilters(t *testing.T) {
+	kyvernoConfiguration := &kyvernov2alpha1.KyvernoConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyverno"},
+		Spec: kyvernov2alpha1.KyvernoConfigurationSpec{
+			ResourceFilters: []kyvernov2alpha1.ResourceFilter{
+				{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/managed-by": "operator"}}},
+				{Kind: "Pod", NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "infra"}}},
+				{Group: "apps", Version: "v1", Subresource: "scale"},
+				{Usernames: []string{"system:serviceaccount:operators:*"}},
+				{Kind: "ConfigMap", UserGroups: []string{"ops"}},
+			},
+		},
+	}
+	cfg := config.NewDefaultConfiguration()
+	errs := Load(cfg, kyvernoConfiguration, Admission)
+	assert.Equal(t, len(errs), 0)
+	// label selector
+	assert.Assert(t, cfg.ToFilterResource(config.ResourceInfo{Kind: "Secret", Namespace: "default", Name: "test", Labels: map[string]string{"app.kubernetes.io/managed-by": "operator"}}))
+	assert.Assert(t, !cfg.ToFilterResource(config.ResourceInfo{Kind: "Secret", Namespace: "default", Name: "test", Labels: map[string]string{"app.kubernetes.io/managed-by": "helm"}}))
+	// namespace selector, unknown namespace labels don't match
+	assert.Assert(t, cfg.ToFilterResource(config.ResourceInfo{Kind: "Pod", Namespace: "infra", Name: "test", NamespaceLabels: map[string]string{"team": "infra"}}))
+	assert.Assert(t, !cfg.ToFilterResource(config.ResourceInfo{Kind: "Pod", Namespace: "apps", Name: "test", NamespaceLabels: map[string]string{"team": "apps"}}))
+	assert.Assert(t, !cfg.ToFilter("Pod", "infra", "test"))
+	// group, version and subresource
+	assert.Assert(t, cfg.ToFilterResource(config.ResourceInfo{Group: "apps", Version: "v1", Kind: "Deployment", Subresource: "scale", Namespace: "default", Name: "test"}))
+	assert.Assert(t, !cfg.ToFilterResource(config.ResourceInfo{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "default", Name: "test"}))
+	// usernames and user groups, resources without request don't match
+	assert.Assert(t, cfg.ToFilterResource(config.ResourceInfo{Kind: "Secret", Namespace: "default", Name: "test", Username: "system:serviceaccount:operators:controller"}))
+	assert.Assert(t, !cfg.ToFilterResource(config.ResourceInfo{Kind: "Secret", Namespace: "default", Name: "test", Username: "admin"}))
+	assert.Assert(t, cfg.ToFilterResource(config.ResourceInfo{Kind: "ConfigMap", Namespace: "default", Name: "test", UserGroups: []string{"system:authenticated", "ops"}}))
+	assert.Assert(t, !cfg.ToFilterResource(config.ResourceInfo{Kind: "Secret", Namespace: "default", Name: "test", UserGroups: []string{"ops"}}))
+	assert.Assert(t, !cfg.ToFilter("ConfigMap", "default", "test"))
+}
+
+func Test_Load_ControllerSections(t *testing.T) {
+	kyvernoConfiguration := &kyvernov2alpha1.KyvernoConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyverno"},
+		Spec: kyvernov2alpha1.KyvernoConfigurationSpec{
+			ResourceFilters: []kyvernov2alpha1.ResourceFilter{{Kind: "Event"}},
+			Background: &kyvernov2alpha1.BackgroundConfiguration{
+				ResourceFilters: []kyvernov2alpha1.ResourceFilter{{Namespace: "generated"}},
+			},
+			Cleanup: &kyvernov2alpha1.CleanupConfiguration{
+				ResourceFilters: []kyvernov2alpha1.ResourceFilter{{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"keep": "true"}}}},
+			},
+		},
+	}
+	kept := config.ResourceInfo{Kind: "Pod", Namespace: "default", Name: "test", Labels: map[string]string{"keep": "true"}}
+	for _, controller := range []Controller{Admission, Reports} {
+		cfg := config.NewDefaultConfiguration()
+		assert.Equal(t, len(Load(cfg, kyvernoConfiguration, controller)), 0)
+		assert.Assert(t, cfg.ToFilter("Event", "default", "test"))
+		assert.Assert(t, !cfg.ToFilter("Pod", "generated", "test"))
+		assert.Assert(t, !cfg.ToFilterResource(kept))
+	}
+	cfg := config.NewDefaultConfiguration()
+	assert.Equal(t, len(Load(cfg, kyvernoConfiguration, Background)), 0)
+	assert.Assert(t, cfg.ToFilter("Event", "default", "test"))
+	assert.Assert(t, cfg.ToFilter("Pod", "generated", "test"))
+	assert.Assert(t, !cfg.ToFilterResource(kept))
+	cfg = config.NewDefaultConfiguration()
+	assert.Equal(t, len(Load(cfg, kyvernoConfiguration, Cleanup)), 0)
+	assert.Assert(t, cfg.ToFilter("Event", "default", "test"))
+	assert.Assert(t, !cfg.ToFilter("Pod", "generated", "test"))
+	assert.Assert(t, cfg.ToFilterResource(kept))
+}
+
+func Test_Load_Invalid(t *testing.T) {
+	kyvernoConfiguration := &kyvernov2alpha1.KyvernoConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "kyverno"},
+		Spec: kyvernov2alpha1.KyvernoConfigurationSpec{
+			DefaultRegistry: "Not A Registry",
+			ResourceFilters: []kyvernov2alpha1.ResourceFilter{{}},
+			Admission: &kyvernov2alpha1.AdmissionConfiguration{
+				Webhooks: []kyvernov2alpha1.WebhookConfiguration{{
+					ObjectSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}}},
+				}},
+			},
+			Cleanup: &kyvernov2alpha1.CleanupConfiguration{
+				ResourceFilters: []kyvernov2alpha1.ResourceFilter{{}},
+			},
+		},
+	}
+	cfg := config.NewDefaultConfiguration()
+	errs := Load(cfg, kyvernoConfiguration, Admission)
+	assert.Equal(t, len(errs), 4)
+	assert.Equal(t, errs[0].Field, "spec.defaultRegistry")
+	assert.Equal(t, errs[1].Field, "spec.resourceFilters[0]")
+	assert.Equal(t, errs[2].Field, "spec.admission.webhooks[0].objectSelector")
+	assert.Equal(t, errs[3].Field, "spec.cleanup.resourceFilters[0]")
+	// the configuration is unchanged
+	assert.Equal(t, cfg.GetDefaultRegistry(), "docker.io")
+	assert.Equal(t, len(cfg.GetWebhooks()), 0)
+}
@@ -0,0 +1,48 @@
+package json
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Redacted is the value replacing redacted data
+const Redacted = "**REDACTED**"
+
+// Redact replaces the values found at the given dot separated path in a decoded JSON object,
+// path elements match map keys or array indices, '*' matches any key or index
+func Redact(object interface{}, path string) {
+	if path = strings.TrimSpace(path); path != "" {
+		redact(object, strings.Split(path, "."))
+	}
+}
+
+func redact(object interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	last := len(path) == 1
+	switch typed := object.(type) {
+	case map[string]interface{}:
+		for key, value := range typed {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+			if last {
+				typed[key] = Redacted
+			} else {
+				redact(value, path[1:])
+			}
+		}
+	case []interface{}:
+		for i, value := range typed {
+			if path[0] != "*" && path[0] != strconv.Itoa(i) {
+				continue
+			}
+			if last {
+				typed[i] = Redacted
+			} else {
+				redact(value, path[1:])
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	object := map[string]interface{}{
+		"patches": []interface{}{
+			map[string]interface{}{"path": "/a", "value": "1"},
+			map[string]interface{}{"path": "/b", "value": "2"},
+		},
+	}
+	Redact(object, "patches.1.value")
+	patches := object["patches"].([]interface{})
+	assert.Equal(t, "1", patches[0].(map[string]interface{})["value"])
+	assert.Equal(t, Redacted, patches[1].(map[string]interface{})["value"])
+	Redact(object, "patches.*.path")
+	assert.Equal(t, Redacted, patches[0].(map[string]interface{})["path"])
+	assert.Equal(t, Redacted, patches[1].(map[string]interface{})["path"])
+	Redact(object, "missing.path")
+	Redact(object, "")
+	assert.Len(t, object, 1)
+}
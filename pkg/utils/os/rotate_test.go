@@ -0,0 +1,40 @@
+package os
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.log")
+	writer := NewRotatingFile(path, 10, 2)
+	for _, entry := range []string{"aaaaaa\n", "bbb\n", "cccccc\n", "dddddd\n", "eeeeee\n"} {
+		n, err := writer.Write([]byte(entry))
+		assert.NoError(t, err)
+		assert.Equal(t, len(entry), n)
+	}
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "eeeeee\n", read(path))
+	assert.Equal(t, "dddddd\n", read(backupName(path, 1)))
+	assert.Equal(t, "cccccc\n", read(backupName(path, 2)))
+	_, err := os.Stat(backupName(path, 3))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotatingFileExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.log")
+	assert.NoError(t, os.WriteFile(path, []byte("existing\n"), 0o600))
+	writer := NewRotatingFile(path, 10, 0)
+	_, err := writer.Write([]byte("new\n"))
+	assert.NoError(t, err)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "new\n", string(data))
+}
@@ -0,0 +1,26 @@
+package policy
+
+import (
+	"sort"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+)
+
+// Less reports whether policy a mutates resources before policy b,
+// by their order then by name and namespace when they have the same order
+func Less(a, b kyvernov1.PolicyInterface) bool {
+	if a.GetSpec().GetOrder() != b.GetSpec().GetOrder() {
+		return a.GetSpec().GetOrder() < b.GetSpec().GetOrder()
+	}
+	if a.GetName() != b.GetName() {
+		return a.GetName() < b.GetName()
+	}
+	return a.GetNamespace() < b.GetNamespace()
+}
+
+// SortByOrder sorts policies in the order they mutate resources
+func SortByOrder(policies []kyvernov1.PolicyInterface) {
+	sort.SliceStable(policies, func(i, j int) bool {
+		return Less(policies[i], policies[j])
+	})
+}
@@ -5,10 +5,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
 	kyvernov1alpha2 "github.com/kyverno/kyverno/api/kyverno/v1alpha2"
+	policyreportv1alpha2 "github.com/kyverno/kyverno/api/policyreport/v1alpha2"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -26,6 +28,8 @@ const (
 	LabelPrefixPolicy        = LabelDomainPolicy + "/"
 	//	aggregated admission report label
 	LabelAggregatedReport = "audit.kyverno.io/report.aggregate"
+	//	result labels
+	LabelPrefixResult = "audit.kyverno.io/result."
 )
 
 func IsPolicyLabel(label string) bool {
@@ -101,6 +105,23 @@ func SetPolicyLabel(report kyvernov1alpha2.ReportInterface, policy kyvernov1.Pol
 	controllerutils.SetLabel(report, PolicyLabel(policy), policy.GetResourceVersion())
 }
 
+// SetResultLabels sets one label per result status present in the report, the value being the number of results
+func SetResultLabels(report kyvernov1alpha2.ReportInterface, results ...policyreportv1alpha2.PolicyReportResult) {
+	summary := CalculateSummary(results)
+	counts := map[string]int{
+		policyreportv1alpha2.StatusPass:  summary.Pass,
+		policyreportv1alpha2.StatusFail:  summary.Fail,
+		policyreportv1alpha2.StatusWarn:  summary.Warn,
+		policyreportv1alpha2.StatusError: summary.Error,
+		policyreportv1alpha2.StatusSkip:  summary.Skip,
+	}
+	for status, count := range counts {
+		if count > 0 {
+			controllerutils.SetLabel(report, LabelPrefixResult+status, strconv.Itoa(count))
+		}
+	}
+}
+
 func GetResourceUid(report metav1.Object) types.UID {
 	return types.UID(report.GetLabels()[LabelResourceUid])
 }
@@ -1,6 +1,10 @@
 package runtime
 
 import (
+	"errors"
+	"sort"
+	"sync"
+
 	"github.com/go-logr/logr"
 	"github.com/kyverno/kyverno/pkg/config"
 	"github.com/kyverno/kyverno/pkg/tls"
@@ -10,12 +14,32 @@ import (
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 )
 
+// ReadinessCheck returns an error when a component is not ready
+type ReadinessCheck func() error
+
+// ComponentStatus is the readiness of a component
+type ComponentStatus struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// Status is the readiness of the runtime and its components
+type Status struct {
+	Ready      bool              `json:"ready"`
+	Components []ComponentStatus `json:"components"`
+}
+
 type Runtime interface {
 	IsDebug() bool
 	IsReady() bool
 	IsLive() bool
 	IsRollingUpdate() bool
 	IsGoingDown() bool
+	// AddReadinessCheck registers a component that must be ready for the runtime to be ready
+	AddReadinessCheck(name string, check ReadinessCheck)
+	// Status returns the readiness of every component
+	Status() Status
 }
 
 type runtime struct {
@@ -23,6 +47,8 @@ type runtime struct {
 	deploymentLister appsv1listers.DeploymentLister
 	certValidator    tls.CertValidator
 	logger           logr.Logger
+	lock             sync.RWMutex
+	checks           map[string]ReadinessCheck
 }
 
 func NewRuntime(
@@ -36,6 +62,7 @@ func NewRuntime(
 		serverIP:         serverIP,
 		deploymentLister: deploymentInformer.Lister(),
 		certValidator:    certValidator,
+		checks:           map[string]ReadinessCheck{},
 	}
 }
 
@@ -48,7 +75,38 @@ func (c *runtime) IsLive() bool {
 }
 
 func (c *runtime) IsReady() bool {
-	return c.validateCertificates()
+	return c.Status().Ready
+}
+
+func (c *runtime) AddReadinessCheck(name string, check ReadinessCheck) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.checks[name] = check
+}
+
+func (c *runtime) Status() Status {
+	c.lock.RLock()
+	checks := map[string]ReadinessCheck{
+		"certificates": c.validateCertificates,
+	}
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.lock.RUnlock()
+	status := Status{Ready: true}
+	for name, check := range checks {
+		component := ComponentStatus{Name: name, Ready: true}
+		if err := check(); err != nil {
+			component.Ready = false
+			component.Message = err.Error()
+			status.Ready = false
+		}
+		status.Components = append(status.Components, component)
+	}
+	sort.Slice(status.Components, func(i, j int) bool {
+		return status.Components[i].Name < status.Components[j].Name
+	})
+	return status
 }
 
 func (c *runtime) IsRollingUpdate() bool {
@@ -93,11 +151,14 @@ func (c *runtime) getDeployment() (*appsv1.Deployment, error) {
 	return c.deploymentLister.Deployments(config.KyvernoNamespace()).Get(config.KyvernoDeploymentName())
 }
 
-func (c *runtime) validateCertificates() bool {
+func (c *runtime) validateCertificates() error {
 	validity, err := c.certValidator.ValidateCert()
 	if err != nil {
 		c.logger.Error(err, "failed to validate certificates")
-		return false
+		return err
+	}
+	if !validity {
+		return errors.New("certificates are not valid")
 	}
-	return validity
+	return nil
 }
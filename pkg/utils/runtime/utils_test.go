@@ -0,0 +1,40 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"gotest.tools/assert"
+)
+
+type certValidator bool
+
+func (v certValidator) ValidateCert() (bool, error) {
+	return bool(v), nil
+}
+
+func Test_Status(t *testing.T) {
+	r := &runtime{
+		logger:        logr.Discard(),
+		certValidator: certValidator(true),
+		checks:        map[string]ReadinessCheck{},
+	}
+	assert.Assert(t, r.IsReady())
+	policyCacheErr := errors.New("1 policies are not loaded in the policy cache")
+	r.AddReadinessCheck("policycache", func() error { return policyCacheErr })
+	r.AddReadinessCheck("informers", func() error { return nil })
+	status := r.Status()
+	assert.Assert(t, !status.Ready)
+	assert.Assert(t, !r.IsReady())
+	assert.DeepEqual(t, status.Components, []ComponentStatus{
+		{Name: "certificates", Ready: true},
+		{Name: "informers", Ready: true},
+		{Name: "policycache", Ready: false, Message: policyCacheErr.Error()},
+	})
+	r.AddReadinessCheck("policycache", func() error { return nil })
+	r.certValidator = certValidator(false)
+	status = r.Status()
+	assert.Assert(t, !status.Ready)
+	assert.DeepEqual(t, status.Components[0], ComponentStatus{Name: "certificates", Ready: false, Message: "certificates are not valid"})
+}
@@ -0,0 +1,21 @@
+package scope
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the scope identifier of the webhook that received the request
+func NewContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the scope identifier carried by the context, empty if the webhook is not scoped
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
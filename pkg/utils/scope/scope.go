@@ -0,0 +1,237 @@
+package scope
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/autogen"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Unrestricted is the scope of policies that can apply to any request
+var Unrestricted = Scope{}
+
+// Scope describes the admission requests a policy can apply to, in terms of webhook selectors.
+// A nil selector means the policy is not restricted on that dimension.
+type Scope struct {
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	ObjectSelector    *metav1.LabelSelector `json:"objectSelector,omitempty"`
+}
+
+// ID returns a short identifier of the scope, policies with the same scope have the same identifier
+func (s Scope) ID() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		// marshalling label selectors can't fail
+		panic(err)
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write(data)
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// Compute derives the scope of a policy from the match and exclude blocks of its rules.
+// The computed scope is always a superset of the requests the policy can apply to,
+// when a block can't be expressed with label selectors the corresponding dimension is left unrestricted.
+func Compute(policy kyvernov1.PolicyInterface) Scope {
+	var result *Scope
+	for _, rule := range autogen.ComputeRules(policy) {
+		// generate rules also watch the generated and cloned resources, they are not bound by the match block
+		if rule.HasGenerate() {
+			return Unrestricted
+		}
+		s := ruleScope(rule)
+		if result == nil {
+			result = &s
+		} else {
+			result = &Scope{
+				NamespaceSelector: union(result.NamespaceSelector, s.NamespaceSelector),
+				ObjectSelector:    union(result.ObjectSelector, s.ObjectSelector),
+			}
+		}
+	}
+	if result == nil {
+		result = &Scope{}
+	}
+	// namespaced policies only apply to resources in the policy namespace
+	if policy.IsNamespaced() {
+		result.NamespaceSelector = And(result.NamespaceSelector, namespacesSelector(policy.GetNamespace()))
+	}
+	return *result
+}
+
+// And returns a label selector matching the labels selected by both a and b
+func And(a, b *metav1.LabelSelector) *metav1.LabelSelector {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	result := a.DeepCopy()
+	for key, value := range b.MatchLabels {
+		if existing, ok := result.MatchLabels[key]; ok && existing != value {
+			result.MatchExpressions = append(result.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      key,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{value},
+			})
+			continue
+		}
+		if result.MatchLabels == nil {
+			result.MatchLabels = map[string]string{}
+		}
+		result.MatchLabels[key] = value
+	}
+	for _, expression := range b.MatchExpressions {
+		result.MatchExpressions = append(result.MatchExpressions, *expression.DeepCopy())
+	}
+	return result
+}
+
+func ruleScope(rule kyvernov1.Rule) Scope {
+	// the api server evaluates the namespace selector against the namespace itself for namespace requests,
+	// kyverno doesn't, we can't restrict namespaces in this case
+	restrictNamespaces := true
+	for _, gvk := range rule.MatchResources.GetKinds() {
+		if _, kind := kubeutils.GetKindFromGVK(gvk); wildcard.Match(kind, "Namespace") {
+			restrictNamespaces = false
+		}
+	}
+	var result Scope
+	match := rule.MatchResources
+	if len(match.Any) > 0 {
+		for i, filter := range match.Any {
+			s := filterScope(filter.ResourceDescription, restrictNamespaces)
+			if i == 0 {
+				result = s
+			} else {
+				result.NamespaceSelector = union(result.NamespaceSelector, s.NamespaceSelector)
+				result.ObjectSelector = union(result.ObjectSelector, s.ObjectSelector)
+			}
+		}
+	} else if len(match.All) > 0 {
+		for _, filter := range match.All {
+			s := filterScope(filter.ResourceDescription, restrictNamespaces)
+			result.NamespaceSelector = And(result.NamespaceSelector, s.NamespaceSelector)
+			result.ObjectSelector = And(result.ObjectSelector, s.ObjectSelector)
+		}
+	} else {
+		result = filterScope(match.ResourceDescription, restrictNamespaces)
+	}
+	if restrictNamespaces {
+		if excluded := excludedNamespaces(rule.ExcludeResources); len(excluded) > 0 {
+			result.NamespaceSelector = And(result.NamespaceSelector, &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      corev1.LabelMetadataName,
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   sets.List(excluded),
+				}},
+			})
+		}
+	}
+	return result
+}
+
+func filterScope(filter kyvernov1.ResourceDescription, restrictNamespaces bool) Scope {
+	var result Scope
+	if usableSelector(filter.Selector) {
+		result.ObjectSelector = filter.Selector.DeepCopy()
+	}
+	if restrictNamespaces {
+		if usableSelector(filter.NamespaceSelector) {
+			result.NamespaceSelector = filter.NamespaceSelector.DeepCopy()
+		}
+		if len(filter.Namespaces) > 0 && !containsWildcard(filter.Namespaces...) {
+			result.NamespaceSelector = And(result.NamespaceSelector, namespacesSelector(filter.Namespaces...))
+		}
+	}
+	return result
+}
+
+// excludedNamespaces returns the namespaces excluded regardless of the resource and the user sending the request
+func excludedNamespaces(exclude kyvernov1.MatchResources) sets.Set[string] {
+	excluded := sets.New[string]()
+	add := func(filter kyvernov1.ResourceFilter) {
+		if !filter.UserInfo.IsEmpty() || len(filter.Namespaces) == 0 || containsWildcard(filter.Namespaces...) {
+			return
+		}
+		description := filter.ResourceDescription
+		description.Namespaces = nil
+		if description.IsEmpty() {
+			excluded.Insert(filter.Namespaces...)
+		}
+	}
+	if len(exclude.Any) > 0 {
+		for _, filter := range exclude.Any {
+			add(filter)
+		}
+	} else if len(exclude.All) == 1 {
+		add(exclude.All[0])
+	} else if len(exclude.All) == 0 {
+		add(kyvernov1.ResourceFilter{UserInfo: exclude.UserInfo, ResourceDescription: exclude.ResourceDescription})
+	}
+	return excluded
+}
+
+// union returns a label selector matching the labels selected by a or b, nil if it can't be expressed
+func union(a, b *metav1.LabelSelector) *metav1.LabelSelector {
+	if a == nil || b == nil {
+		return nil
+	}
+	if equality.Semantic.DeepEqual(a, b) {
+		return a
+	}
+	if left, ok := selectedNamespaces(a); ok {
+		if right, ok := selectedNamespaces(b); ok {
+			return namespacesSelector(sets.List(left.Union(right))...)
+		}
+	}
+	return nil
+}
+
+// selectedNamespaces returns the namespace names if the selector only selects namespaces by name
+func selectedNamespaces(selector *metav1.LabelSelector) (sets.Set[string], bool) {
+	if len(selector.MatchLabels) != 0 || len(selector.MatchExpressions) != 1 {
+		return nil, false
+	}
+	expression := selector.MatchExpressions[0]
+	if expression.Key != corev1.LabelMetadataName || expression.Operator != metav1.LabelSelectorOpIn {
+		return nil, false
+	}
+	return sets.New(expression.Values...), true
+}
+
+func namespacesSelector(namespaces ...string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      corev1.LabelMetadataName,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   sets.List(sets.New(namespaces...)),
+		}},
+	}
+}
+
+func usableSelector(selector *metav1.LabelSelector) bool {
+	if selector == nil || kubeutils.LabelSelectorContainsWildcard(selector) {
+		return false
+	}
+	_, err := metav1.LabelSelectorAsSelector(selector)
+	return err == nil
+}
+
+func containsWildcard(values ...string) bool {
+	for _, value := range values {
+		if wildcard.ContainsWildcard(value) {
+			return true
+		}
+	}
+	return false
+}
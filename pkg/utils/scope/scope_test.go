@@ -0,0 +1,142 @@
+package scope
+
+import (
+	"context"
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPolicy(namespace string, rules ...kyvernov1.Rule) kyvernov1.PolicyInterface {
+	spec := kyvernov1.Spec{Rules: rules}
+	if namespace != "" {
+		return &kyvernov1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace}, Spec: spec}
+	}
+	return &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "test"}, Spec: spec}
+}
+
+func validateRule(match, exclude kyvernov1.MatchResources) kyvernov1.Rule {
+	return kyvernov1.Rule{
+		Name:             "check",
+		MatchResources:   match,
+		ExcludeResources: exclude,
+		Validation:       kyvernov1.Validation{Message: "message"},
+	}
+}
+
+func names(operator metav1.LabelSelectorOperator, values ...string) metav1.LabelSelectorRequirement {
+	return metav1.LabelSelectorRequirement{Key: corev1.LabelMetadataName, Operator: operator, Values: values}
+}
+
+func Test_Compute(t *testing.T) {
+	appSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	teamSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+	tests := []struct {
+		name   string
+		policy kyvernov1.PolicyInterface
+		want   Scope
+	}{{
+		name: "selectors",
+		policy: newPolicy("", validateRule(kyvernov1.MatchResources{
+			ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Pod"}, Selector: appSelector, NamespaceSelector: teamSelector},
+		}, kyvernov1.MatchResources{})),
+		want: Scope{NamespaceSelector: teamSelector, ObjectSelector: appSelector},
+	}, {
+		name: "namespaces and exclusions",
+		policy: newPolicy("", validateRule(kyvernov1.MatchResources{
+			Any: kyvernov1.ResourceFilters{
+				{ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Pod"}, Namespaces: []string{"b"}}},
+				{ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Pod"}, Namespaces: []string{"a"}}},
+			},
+		}, kyvernov1.MatchResources{
+			Any: kyvernov1.ResourceFilters{
+				{ResourceDescription: kyvernov1.ResourceDescription{Namespaces: []string{"kube-system"}}},
+				{ResourceDescription: kyvernov1.ResourceDescription{Namespaces: []string{"a"}, Names: []string{"web"}}},
+			},
+		})),
+		want: Scope{NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{names(metav1.LabelSelectorOpIn, "a", "b"), names(metav1.LabelSelectorOpNotIn, "kube-system")},
+		}},
+	}, {
+		name: "any with different selectors",
+		policy: newPolicy("", validateRule(kyvernov1.MatchResources{
+			Any: kyvernov1.ResourceFilters{
+				{ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Pod"}, Selector: appSelector}},
+				{ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Pod"}, Selector: teamSelector}},
+			},
+		}, kyvernov1.MatchResources{})),
+		want: Unrestricted,
+	}, {
+		name: "all",
+		policy: newPolicy("", validateRule(kyvernov1.MatchResources{
+			All: kyvernov1.ResourceFilters{
+				{ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Pod"}, Selector: appSelector}},
+				{ResourceDescription: kyvernov1.ResourceDescription{Selector: teamSelector}},
+			},
+		}, kyvernov1.MatchResources{})),
+		want: Scope{ObjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web", "team": "a"}}},
+	}, {
+		name: "wildcard selector",
+		policy: newPolicy("", validateRule(kyvernov1.MatchResources{
+			ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Pod"}, Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "*"}}},
+		}, kyvernov1.MatchResources{})),
+		want: Unrestricted,
+	}, {
+		name: "namespace kind",
+		policy: newPolicy("", validateRule(kyvernov1.MatchResources{
+			ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Namespace"}, Namespaces: []string{"a"}, NamespaceSelector: teamSelector},
+		}, kyvernov1.MatchResources{})),
+		want: Unrestricted,
+	}, {
+		name: "namespaced policy",
+		policy: newPolicy("test", validateRule(kyvernov1.MatchResources{
+			ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"ConfigMap"}, Selector: appSelector},
+		}, kyvernov1.MatchResources{})),
+		want: Scope{
+			NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{names(metav1.LabelSelectorOpIn, "test")}},
+			ObjectSelector:    appSelector,
+		},
+	}, {
+		name: "generate",
+		policy: newPolicy("", kyvernov1.Rule{
+			Name: "generate",
+			MatchResources: kyvernov1.MatchResources{
+				ResourceDescription: kyvernov1.ResourceDescription{Kinds: []string{"Namespace"}, Selector: appSelector},
+			},
+			Generation: kyvernov1.Generation{ResourceSpec: kyvernov1.ResourceSpec{Kind: "ConfigMap", Name: "test"}},
+		}),
+		want: Unrestricted,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(tt.policy)
+			assert.DeepEqual(t, got, tt.want)
+			assert.Equal(t, got.ID(), tt.want.ID())
+		})
+	}
+}
+
+func Test_And(t *testing.T) {
+	got := And(
+		&metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		&metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}, MatchExpressions: []metav1.LabelSelectorRequirement{names(metav1.LabelSelectorOpIn, "a")}},
+	)
+	assert.DeepEqual(t, got, &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app": "web"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"db"}},
+			names(metav1.LabelSelectorOpIn, "a"),
+		},
+	})
+	assert.Assert(t, And(nil, nil) == nil)
+}
+
+func Test_Context(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, FromContext(ctx), "")
+	assert.Equal(t, FromContext(NewContext(ctx, "")), "")
+	assert.Equal(t, FromContext(NewContext(ctx, "abc")), "abc")
+}
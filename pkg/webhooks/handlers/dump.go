@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
+	jsonutils "github.com/kyverno/kyverno/pkg/utils/json"
 	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	"github.com/kyverno/kyverno/pkg/utils/wildcard"
 	admissionv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,28 +19,87 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-func (inner AdmissionHandler) WithDump(enabled bool) AdmissionHandler {
+// sensitiveAnnotations are redacted from the objects of all kinds
+var sensitiveAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"openshift.io/token-secret.value",
+}
+
+// DumpOptions configures the admission payloads being dumped
+type DumpOptions struct {
+	// RedactedPaths are dot separated paths in the dumped request whose values are redacted ('*' matches any key or index)
+	RedactedPaths []string
+	// SampleRatio is the ratio of requests dumped (between 0 and 1), zero disables dumps
+	SampleRatio float64
+	// Kinds restricts dumps to requests for the given kinds, wildcards are supported
+	Kinds []string
+	// Namespaces restricts dumps to requests in the given namespaces, wildcards are supported
+	Namespaces []string
+}
+
+func (o DumpOptions) matches(request *admissionv1.AdmissionRequest) bool {
+	if len(o.Kinds) != 0 && !wildcard.CheckPatterns(o.Kinds, request.Kind.Kind) {
+		return false
+	}
+	if len(o.Namespaces) != 0 && !wildcard.CheckPatterns(o.Namespaces, request.Namespace) {
+		return false
+	}
+	return o.SampleRatio >= 1 || rand.Float64() < o.SampleRatio //nolint:gosec
+}
+
+func (inner AdmissionHandler) WithDump(enabled bool, options DumpOptions) AdmissionHandler {
 	if !enabled {
 		return inner
 	}
-	return inner.withDump().WithTrace("DUMP")
+	return inner.withDump(options).WithTrace("DUMP")
 }
 
-func (inner AdmissionHandler) withDump() AdmissionHandler {
+func (inner AdmissionHandler) withDump(options DumpOptions) AdmissionHandler {
 	return func(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, startTime time.Time) *admissionv1.AdmissionResponse {
 		response := inner(ctx, logger, request, startTime)
-		dumpPayload(logger, request, response)
+		if options.matches(request) {
+			dumpPayload(logger, request, response, options.RedactedPaths...)
+		}
 		return response
 	}
 }
 
-func dumpPayload(logger logr.Logger, request *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse) {
+func dumpPayload(logger logr.Logger, request *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse, redactedPaths ...string) {
 	reqPayload, err := newAdmissionRequestPayload(request)
 	if err != nil {
 		logger.Error(err, "Failed to extract resources")
-	} else {
-		logger.Info("Logging admission request and response payload ", "AdmissionRequest", reqPayload, "AdmissionResponse", response)
+		return
+	}
+	redactedRequest, err := redactPaths(reqPayload, redactedPaths...)
+	if err != nil {
+		logger.Error(err, "Failed to redact payload")
+		return
+	}
+	if response != nil && len(response.Patch) != 0 && strings.EqualFold(request.Kind.Kind, "Secret") {
+		// patches may contain secret data
+		redactedResponse := *response
+		redactedResponse.Patch = []byte(jsonutils.Redacted)
+		response = &redactedResponse
+	}
+	logger.Info("Logging admission request and response payload ", "AdmissionRequest", redactedRequest, "AdmissionResponse", response)
+}
+
+func redactPaths(payload *admissionRequestPayload, paths ...string) (interface{}, error) {
+	if len(paths) == 0 {
+		return payload, nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var object interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
 	}
+	for _, path := range paths {
+		jsonutils.Redact(object, path)
+	}
+	return object, nil
 }
 
 // admissionRequestPayload holds a copy of the AdmissionRequest payload
@@ -90,21 +153,46 @@ func newAdmissionRequestPayload(request *admissionv1.AdmissionRequest) (*admissi
 }
 
 func redactPayload(payload *admissionRequestPayload) (*admissionRequestPayload, error) {
-	if strings.EqualFold(payload.Kind.Kind, "Secret") {
-		if payload.Object.Object != nil {
-			obj, err := kubeutils.RedactSecret(&payload.Object)
+	for _, object := range []*unstructured.Unstructured{&payload.Object, &payload.OldObject} {
+		if object.Object == nil {
+			continue
+		}
+		switch {
+		case strings.EqualFold(payload.Kind.Kind, "Secret"):
+			redacted, err := kubeutils.RedactSecret(object)
 			if err != nil {
 				return nil, err
 			}
-			payload.Object = obj
-		}
-		if payload.OldObject.Object != nil {
-			oldObj, err := kubeutils.RedactSecret(&payload.OldObject)
-			if err != nil {
+			*object = redacted
+			if stringData, ok := object.Object["stringData"].(map[string]interface{}); ok {
+				for key := range stringData {
+					stringData[key] = jsonutils.Redacted
+				}
+			}
+		case strings.EqualFold(payload.Kind.Kind, "TokenRequest"):
+			if err := redactField(object, "status", "token"); err != nil {
+				return nil, err
+			}
+		case strings.EqualFold(payload.Kind.Kind, "TokenReview"):
+			if err := redactField(object, "spec", "token"); err != nil {
 				return nil, err
 			}
-			payload.OldObject = oldObj
+		}
+		if annotations := object.GetAnnotations(); annotations != nil {
+			for _, key := range sensitiveAnnotations {
+				if _, ok := annotations[key]; ok {
+					annotations[key] = jsonutils.Redacted
+				}
+			}
+			object.SetAnnotations(annotations)
 		}
 	}
 	return payload, nil
 }
+
+func redactField(object *unstructured.Unstructured, fields ...string) error {
+	if _, found, _ := unstructured.NestedFieldNoCopy(object.Object, fields...); found {
+		return unstructured.SetNestedField(object.Object, jsonutils.Redacted, fields...)
+	}
+	return nil
+}
@@ -5,8 +5,11 @@ import (
 	"testing"
 
 	datautils "github.com/kyverno/kyverno/pkg/utils/data"
+	jsonutils "github.com/kyverno/kyverno/pkg/utils/json"
 	"gotest.tools/assert"
 	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func Test_RedactPayload(t *testing.T) {
@@ -174,3 +177,39 @@ func Test_RedactPayload(t *testing.T) {
 		})
 	}
 }
+
+func Test_RedactSensitiveFields(t *testing.T) {
+	secret := []byte(`{"kind":"Secret","apiVersion":"v1","metadata":{"name":"s","namespace":"default"},"stringData":{"password":"secret"}}`)
+	pod := []byte(`{"kind":"Pod","apiVersion":"v1","metadata":{"name":"p","namespace":"default","labels":{"app":"web"},"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}","team":"a"}}}`)
+	tokenRequest := []byte(`{"kind":"TokenRequest","apiVersion":"authentication.k8s.io/v1","metadata":{"name":"sa"},"status":{"token":"abc"}}`)
+
+	payload, err := newAdmissionRequestPayload(&admissionv1.AdmissionRequest{Kind: metav1.GroupVersionKind{Kind: "Secret"}, Object: runtime.RawExtension{Raw: secret}})
+	assert.NilError(t, err)
+	assert.Equal(t, payload.Object.Object["stringData"].(map[string]interface{})["password"], jsonutils.Redacted)
+
+	payload, err = newAdmissionRequestPayload(&admissionv1.AdmissionRequest{Kind: metav1.GroupVersionKind{Kind: "Pod"}, Object: runtime.RawExtension{Raw: pod}})
+	assert.NilError(t, err)
+	assert.Equal(t, payload.Object.GetAnnotations()["kubectl.kubernetes.io/last-applied-configuration"], jsonutils.Redacted)
+	assert.Equal(t, payload.Object.GetAnnotations()["team"], "a")
+
+	redacted, err := redactPaths(payload, "object.metadata.labels", "userInfo")
+	assert.NilError(t, err)
+	object := redacted.(map[string]interface{})
+	assert.Equal(t, object["userInfo"], jsonutils.Redacted)
+	assert.Equal(t, object["object"].(map[string]interface{})["metadata"].(map[string]interface{})["labels"], jsonutils.Redacted)
+
+	payload, err = newAdmissionRequestPayload(&admissionv1.AdmissionRequest{Kind: metav1.GroupVersionKind{Kind: "TokenRequest"}, Object: runtime.RawExtension{Raw: tokenRequest}})
+	assert.NilError(t, err)
+	assert.Equal(t, payload.Object.Object["status"].(map[string]interface{})["token"], jsonutils.Redacted)
+}
+
+func Test_DumpOptionsMatches(t *testing.T) {
+	request := &admissionv1.AdmissionRequest{Kind: metav1.GroupVersionKind{Kind: "Pod"}, Namespace: "team-a"}
+	assert.Assert(t, DumpOptions{SampleRatio: 1}.matches(request))
+	assert.Assert(t, DumpOptions{SampleRatio: 1, Kinds: []string{"Po*"}, Namespaces: []string{"team-*"}}.matches(request))
+	assert.Assert(t, !DumpOptions{SampleRatio: 1, Kinds: []string{"Deployment"}}.matches(request))
+	assert.Assert(t, !DumpOptions{SampleRatio: 1, Namespaces: []string{"kube-system"}}.matches(request))
+	assert.Assert(t, !DumpOptions{SampleRatio: 0.000000001}.matches(request))
+	// a zero ratio disables dumps
+	assert.Assert(t, !DumpOptions{}.matches(request))
+}
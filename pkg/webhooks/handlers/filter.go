@@ -2,17 +2,20 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/kyverno/kyverno/pkg/config"
 	webhookutils "github.com/kyverno/kyverno/pkg/webhooks/utils"
 	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 )
 
-func (inner AdmissionHandler) WithFilter(configuration config.Configuration) AdmissionHandler {
-	return inner.withFilter(configuration).WithTrace("FILTER")
+func (inner AdmissionHandler) WithFilter(configuration config.Configuration, nsLister corev1listers.NamespaceLister) AdmissionHandler {
+	return inner.withFilter(configuration, nsLister).WithTrace("FILTER")
 }
 
 func (inner AdmissionHandler) WithOperationFilter(operations ...admissionv1.Operation) AdmissionHandler {
@@ -23,9 +26,9 @@ func (inner AdmissionHandler) WithSubResourceFilter(subresources ...string) Admi
 	return inner.withSubResourceFilter(subresources...).WithTrace("SUBRESOURCE")
 }
 
-func (inner AdmissionHandler) withFilter(c config.Configuration) AdmissionHandler {
+func (inner AdmissionHandler) withFilter(c config.Configuration, nsLister corev1listers.NamespaceLister) AdmissionHandler {
 	return func(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, startTime time.Time) *admissionv1.AdmissionResponse {
-		if c.ToFilter(request.Kind.Kind, request.Namespace, request.Name) {
+		if c.ToFilterResource(resourceInfo(request, nsLister)) {
 			return nil
 		}
 		for _, username := range c.GetExcludeUsername() {
@@ -40,6 +43,39 @@ func (inner AdmissionHandler) withFilter(c config.Configuration) AdmissionHandle
 	}
 }
 
+// resourceInfo describes the resource of an admission request to be matched against the resource filters
+func resourceInfo(request *admissionv1.AdmissionRequest, nsLister corev1listers.NamespaceLister) config.ResourceInfo {
+	info := config.ResourceInfo{
+		Group:       request.Kind.Group,
+		Version:     request.Kind.Version,
+		Kind:        request.Kind.Kind,
+		Subresource: request.SubResource,
+		Namespace:   request.Namespace,
+		Name:        request.Name,
+		Username:    request.UserInfo.Username,
+		UserGroups:  request.UserInfo.Groups,
+	}
+	object := request.Object.Raw
+	if len(object) == 0 {
+		object = request.OldObject.Raw
+	}
+	if len(object) != 0 {
+		var metadata metav1.PartialObjectMetadata
+		if err := json.Unmarshal(object, &metadata); err == nil {
+			info.Labels = metadata.GetLabels()
+		}
+	}
+	if request.Namespace != "" && nsLister != nil {
+		if namespace, err := nsLister.Get(request.Namespace); err == nil {
+			info.NamespaceLabels = map[string]string{}
+			for key, value := range namespace.GetLabels() {
+				info.NamespaceLabels[key] = value
+			}
+		}
+	}
+	return info
+}
+
 func (inner AdmissionHandler) withOperationFilter(operations ...admissionv1.Operation) AdmissionHandler {
 	allowed := sets.New[string]()
 	for _, operation := range operations {
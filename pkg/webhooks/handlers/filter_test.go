@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/config"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+)
+
+func newNamespaceLister(t *testing.T, namespaces ...*corev1.Namespace) corev1listers.NamespaceLister {
+	informer := kubeinformers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0).Core().V1().Namespaces()
+	for _, namespace := range namespaces {
+		assert.NilError(t, informer.Informer().GetIndexer().Add(namespace))
+	}
+	return informer.Lister()
+}
+
+func newRequest(operation admissionv1.Operation, object string, oldObject string) *admissionv1.AdmissionRequest {
+	request := &admissionv1.AdmissionRequest{
+		Kind:        metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		SubResource: "scale",
+		Namespace:   "team-a",
+		Name:        "app",
+		Operation:   operation,
+		UserInfo:    authenticationv1.UserInfo{Username: "alice", Groups: []string{"developers"}},
+	}
+	if object != "" {
+		request.Object = runtime.RawExtension{Raw: []byte(object)}
+	}
+	if oldObject != "" {
+		request.OldObject = runtime.RawExtension{Raw: []byte(oldObject)}
+	}
+	return request
+}
+
+func Test_resourceInfo(t *testing.T) {
+	nsLister := newNamespaceLister(t, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}},
+	})
+	tests := []struct {
+		name     string
+		request  *admissionv1.AdmissionRequest
+		nsLister corev1listers.NamespaceLister
+		want     config.ResourceInfo
+	}{{
+		name:     "create",
+		request:  newRequest(admissionv1.Create, `{"metadata":{"labels":{"app":"new"}}}`, ""),
+		nsLister: nsLister,
+		want: config.ResourceInfo{
+			Group:           "apps",
+			Version:         "v1",
+			Kind:            "Deployment",
+			Subresource:     "scale",
+			Namespace:       "team-a",
+			Name:            "app",
+			Labels:          map[string]string{"app": "new"},
+			NamespaceLabels: map[string]string{"team": "a"},
+			Username:        "alice",
+			UserGroups:      []string{"developers"},
+		},
+	}, {
+		name:     "update uses the new object labels",
+		request:  newRequest(admissionv1.Update, `{"metadata":{"labels":{"app":"new"}}}`, `{"metadata":{"labels":{"app":"old"}}}`),
+		nsLister: nsLister,
+		want: config.ResourceInfo{
+			Group:           "apps",
+			Version:         "v1",
+			Kind:            "Deployment",
+			Subresource:     "scale",
+			Namespace:       "team-a",
+			Name:            "app",
+			Labels:          map[string]string{"app": "new"},
+			NamespaceLabels: map[string]string{"team": "a"},
+			Username:        "alice",
+			UserGroups:      []string{"developers"},
+		},
+	}, {
+		name:     "delete uses the old object labels",
+		request:  newRequest(admissionv1.Delete, "", `{"metadata":{"labels":{"app":"old"}}}`),
+		nsLister: nsLister,
+		want: config.ResourceInfo{
+			Group:           "apps",
+			Version:         "v1",
+			Kind:            "Deployment",
+			Subresource:     "scale",
+			Namespace:       "team-a",
+			Name:            "app",
+			Labels:          map[string]string{"app": "old"},
+			NamespaceLabels: map[string]string{"team": "a"},
+			Username:        "alice",
+			UserGroups:      []string{"developers"},
+		},
+	}, {
+		name:     "unknown namespace",
+		request:  newRequest(admissionv1.Create, `{"metadata":{}}`, ""),
+		nsLister: newNamespaceLister(t),
+		want: config.ResourceInfo{
+			Group:       "apps",
+			Version:     "v1",
+			Kind:        "Deployment",
+			Subresource: "scale",
+			Namespace:   "team-a",
+			Name:        "app",
+			Username:    "alice",
+			UserGroups:  []string{"developers"},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, resourceInfo(tt.request, tt.nsLister), tt.want)
+		})
+	}
+}
+
+func Test_WithFilter(t *testing.T) {
+	nsLister := newNamespaceLister(t, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}},
+	})
+	selector := func(s string) labels.Selector {
+		selector, err := labels.Parse(s)
+		assert.NilError(t, err)
+		return selector
+	}
+	tests := []struct {
+		name    string
+		filter  config.Filter
+		request *admissionv1.AdmissionRequest
+		want    bool
+	}{{
+		name:    "label selector on deleted object",
+		filter:  config.Filter{Kind: "*", Namespace: "*", Name: "*", Selector: selector("app=old")},
+		request: newRequest(admissionv1.Delete, "", `{"metadata":{"labels":{"app":"old"}}}`),
+	}, {
+		name:    "label selector not matching",
+		filter:  config.Filter{Kind: "*", Namespace: "*", Name: "*", Selector: selector("app=old")},
+		request: newRequest(admissionv1.Update, `{"metadata":{"labels":{"app":"new"}}}`, `{"metadata":{"labels":{"app":"old"}}}`),
+		want:    true,
+	}, {
+		name:    "namespace selector",
+		filter:  config.Filter{Kind: "*", Namespace: "*", Name: "*", NamespaceSelector: selector("team=a")},
+		request: newRequest(admissionv1.Create, `{"metadata":{}}`, ""),
+	}, {
+		name:    "group, version and subresource",
+		filter:  config.Filter{Group: "apps", Version: "v1", Kind: "Deployment", Subresource: "scale", Namespace: "*", Name: "*"},
+		request: newRequest(admissionv1.Create, `{"metadata":{}}`, ""),
+	}, {
+		name:    "user groups not matching",
+		filter:  config.Filter{Kind: "*", Namespace: "*", Name: "*", UserGroups: []string{"system:*"}},
+		request: newRequest(admissionv1.Create, `{"metadata":{}}`, ""),
+		want:    true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configuration := config.NewDefaultConfiguration()
+			configuration.LoadSettings(config.Settings{Filters: []config.Filter{tt.filter}})
+			var called bool
+			inner := AdmissionHandler(func(context.Context, logr.Logger, *admissionv1.AdmissionRequest, time.Time) *admissionv1.AdmissionResponse {
+				called = true
+				return &admissionv1.AdmissionResponse{Allowed: true}
+			})
+			inner.withFilter(configuration, nsLister)(context.TODO(), logr.Discard(), tt.request, time.Now())
+			assert.Equal(t, called, tt.want)
+		})
+	}
+}
@@ -1,14 +1,34 @@
 package handlers
 
-import "net/http"
+import (
+	"encoding/json"
+	"net/http"
+
+	runtimeutils "github.com/kyverno/kyverno/pkg/utils/runtime"
+)
 
 func Probe(check func() bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if check != nil {
 			if !check() {
 				w.WriteHeader(http.StatusInternalServerError)
+				return
 			}
 		}
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+// Status writes the readiness status of components, the response code is an error if a component is not ready
+func Status(status func() runtimeutils.Status) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		current := status()
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if current.Ready {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(current)
+	}
+}
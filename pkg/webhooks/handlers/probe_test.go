@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	runtimeutils "github.com/kyverno/kyverno/pkg/utils/runtime"
+	"gotest.tools/assert"
+)
+
+func Test_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   runtimeutils.Status
+		wantCode int
+	}{{
+		name: "ready",
+		status: runtimeutils.Status{
+			Ready: true,
+			Components: []runtimeutils.ComponentStatus{
+				{Name: "policycache", Ready: true},
+				{Name: "webhooks", Ready: true},
+			},
+		},
+		wantCode: http.StatusOK,
+	}, {
+		name: "not ready",
+		status: runtimeutils.Status{
+			Ready: false,
+			Components: []runtimeutils.ComponentStatus{
+				{Name: "policycache", Ready: true},
+				{Name: "webhooks", Ready: false, Message: "webhook mutate has no CA bundle"},
+			},
+		},
+		wantCode: http.StatusServiceUnavailable,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/health/status", nil)
+			Status(func() runtimeutils.Status { return tt.status })(recorder, request)
+			assert.Equal(t, recorder.Code, tt.wantCode)
+			assert.Equal(t, recorder.Header().Get("Content-Type"), "application/json; charset=utf-8")
+			var got runtimeutils.Status
+			assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+			assert.DeepEqual(t, got, tt.status)
+		})
+	}
+}
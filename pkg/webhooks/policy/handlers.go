@@ -2,26 +2,40 @@ package policy
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	kyvernov1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	"github.com/kyverno/kyverno/pkg/openapi"
 	policyvalidate "github.com/kyverno/kyverno/pkg/policy"
+	"github.com/kyverno/kyverno/pkg/policy/lint"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
 	"github.com/kyverno/kyverno/pkg/webhooks"
 	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 type handlers struct {
 	client         dclient.Interface
 	openApiManager openapi.Manager
+	cpolLister     kyvernov1listers.ClusterPolicyLister
+	polLister      kyvernov1listers.PolicyLister
 }
 
-func NewHandlers(client dclient.Interface, openApiManager openapi.Manager) webhooks.PolicyHandlers {
+func NewHandlers(
+	client dclient.Interface,
+	openApiManager openapi.Manager,
+	cpolLister kyvernov1listers.ClusterPolicyLister,
+	polLister kyvernov1listers.PolicyLister,
+) webhooks.PolicyHandlers {
 	return &handlers{
 		client:         client,
 		openApiManager: openApiManager,
+		cpolLister:     cpolLister,
+		polLister:      polLister,
 	}
 }
 
@@ -34,10 +48,41 @@ func (h *handlers) Validate(ctx context.Context, logger logr.Logger, request *ad
 	warnings, err := policyvalidate.Validate(policy, h.client, false, h.openApiManager)
 	if err != nil {
 		logger.Error(err, "policy validation errors")
+	} else {
+		warnings = append(warnings, h.mutateConflicts(logger, policy)...)
 	}
 	return admissionutils.Response(request.UID, err, warnings...)
 }
 
+// mutateConflicts returns warnings for the mutate rules of the policy setting fields also set by installed policies
+func (h *handlers) mutateConflicts(logger logr.Logger, policy kyvernov1.PolicyInterface) []string {
+	if h.cpolLister == nil || h.polLister == nil || !policy.GetSpec().HasMutate() {
+		return nil
+	}
+	var installed []kyvernov1.PolicyInterface
+	cpols, err := h.cpolLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list cluster policies")
+		return nil
+	}
+	for _, cpol := range cpols {
+		installed = append(installed, cpol)
+	}
+	pols, err := h.polLister.List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "failed to list policies")
+		return nil
+	}
+	for _, pol := range pols {
+		installed = append(installed, pol)
+	}
+	var warnings []string
+	for _, finding := range lint.Conflicts(policy, installed...) {
+		warnings = append(warnings, fmt.Sprintf("mutate conflict: rule %s %s", finding.Rule, finding.Message))
+	}
+	return warnings
+}
+
 func (h *handlers) Mutate(_ context.Context, _ logr.Logger, _ *admissionv1.AdmissionRequest, _ time.Time) *admissionv1.AdmissionResponse {
 	return nil
 }
@@ -0,0 +1,124 @@
+package decisionlog
+
+import (
+	"encoding/json"
+	"time"
+
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Entry is the decision taken for an admission request
+type Entry struct {
+	Time           time.Time                 `json:"time"`
+	UID            types.UID                 `json:"uid"`
+	Webhook        string                    `json:"webhook"`
+	Operation      admissionv1.Operation     `json:"operation"`
+	User           authenticationv1.UserInfo `json:"user"`
+	Resource       Resource                  `json:"resource"`
+	Allowed        bool                      `json:"allowed"`
+	Message        string                    `json:"message,omitempty"`
+	Warnings       []string                  `json:"warnings,omitempty"`
+	Policies       []Policy                  `json:"policies,omitempty"`
+	Patches        []PatchOperation          `json:"patches,omitempty"`
+	LatencySeconds float64                   `json:"latencySeconds"`
+}
+
+// Resource identifies the resource of an admission request
+type Resource struct {
+	metav1.GroupVersionKind `json:",inline"`
+	Namespace               string `json:"namespace,omitempty"`
+	Name                    string `json:"name,omitempty"`
+	SubResource             string `json:"subResource,omitempty"`
+}
+
+// Policy holds the results of a policy matching an admission request
+type Policy struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Rules     []Rule `json:"rules,omitempty"`
+}
+
+// Rule holds the result of a rule
+type Rule struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// PatchOperation is a JSON patch operation applied to the resource
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+	// Policy is the key of the policy that set the path, if known
+	Policy string `json:"policy,omitempty"`
+}
+
+// NewEntry builds the decision entry of an admission request
+func NewEntry(
+	webhook string,
+	request *admissionv1.AdmissionRequest,
+	response *admissionv1.AdmissionResponse,
+	startTime time.Time,
+	engineResponses ...*engineapi.EngineResponse,
+) Entry {
+	entry := Entry{
+		Time:      startTime,
+		UID:       request.UID,
+		Webhook:   webhook,
+		Operation: request.Operation,
+		User:      request.UserInfo,
+		Resource: Resource{
+			GroupVersionKind: request.Kind,
+			Namespace:        request.Namespace,
+			Name:             request.Name,
+			SubResource:      request.SubResource,
+		},
+		LatencySeconds: time.Since(startTime).Seconds(),
+	}
+	if response != nil {
+		entry.Allowed = response.Allowed
+		entry.Warnings = response.Warnings
+		if response.Result != nil {
+			entry.Message = response.Result.Message
+		}
+		if len(response.Patch) > 0 {
+			var patches []PatchOperation
+			if err := json.Unmarshal(response.Patch, &patches); err == nil {
+				entry.Patches = patches
+			}
+		}
+	}
+	for _, engineResponse := range engineResponses {
+		if engineResponse == nil || len(engineResponse.PolicyResponse.Rules) == 0 {
+			continue
+		}
+		policy := Policy{
+			Namespace: engineResponse.PolicyResponse.Policy.Namespace,
+			Name:      engineResponse.PolicyResponse.Policy.Name,
+		}
+		for _, rule := range engineResponse.PolicyResponse.Rules {
+			policy.Rules = append(policy.Rules, Rule{
+				Name:    rule.Name,
+				Type:    string(rule.Type),
+				Status:  string(rule.Status),
+				Message: rule.Message,
+			})
+		}
+		entry.Policies = append(entry.Policies, policy)
+	}
+	return entry
+}
+
+// SetPatchOwners sets the policy of the patch operations from the owners of their path
+func (e *Entry) SetPatchOwners(owners map[string]string) {
+	for i := range e.Patches {
+		e.Patches[i].Policy = owners[e.Patches[i].Path]
+	}
+}
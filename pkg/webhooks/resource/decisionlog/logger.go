@@ -0,0 +1,73 @@
+package decisionlog
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	jsonutils "github.com/kyverno/kyverno/pkg/utils/json"
+)
+
+// Logger writes admission decisions
+type Logger interface {
+	Log(Entry)
+}
+
+type logger struct {
+	log            logr.Logger
+	writer         io.Writer
+	redactedFields []string
+	lock           sync.Mutex
+}
+
+// NewLogger creates a logger writing decisions as JSON lines,
+// redactedFields are dot separated paths in the JSON entries ('*' matches any key or index)
+// whose values are replaced, e.g. 'user.extra' or 'patches.*.value'
+func NewLogger(log logr.Logger, writer io.Writer, redactedFields ...string) Logger {
+	return &logger{
+		log:            log,
+		writer:         writer,
+		redactedFields: redactedFields,
+	}
+}
+
+func (l *logger) Log(entry Entry) {
+	line, err := l.marshal(entry)
+	if err != nil {
+		l.log.Error(err, "failed to marshal admission decision", "uid", entry.UID)
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if _, err := l.writer.Write(append(line, '\n')); err != nil {
+		l.log.Error(err, "failed to write admission decision", "uid", entry.UID)
+	}
+}
+
+func (l *logger) marshal(entry Entry) ([]byte, error) {
+	if strings.EqualFold(entry.Resource.Kind, "Secret") {
+		// patches may contain secret data
+		patches := make([]PatchOperation, 0, len(entry.Patches))
+		for _, patch := range entry.Patches {
+			if patch.Value != nil {
+				patch.Value = jsonutils.Redacted
+			}
+			patches = append(patches, patch)
+		}
+		entry.Patches = patches
+	}
+	data, err := json.Marshal(entry)
+	if err != nil || len(l.redactedFields) == 0 {
+		return data, err
+	}
+	var object interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for _, path := range l.redactedFields {
+		jsonutils.Redact(object, path)
+	}
+	return json.Marshal(object)
+}
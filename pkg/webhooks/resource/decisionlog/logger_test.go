@@ -0,0 +1,60 @@
+package decisionlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	jsonutils "github.com/kyverno/kyverno/pkg/utils/json"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_LogDecision(t *testing.T) {
+	request := &admissionv1.AdmissionRequest{
+		UID:       "uid",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Secret"},
+		Namespace: "default",
+		Name:      "creds",
+		Operation: admissionv1.Create,
+		UserInfo: authenticationv1.UserInfo{
+			Username: "alice",
+			Extra:    map[string]authenticationv1.ExtraValue{"token": {"abc"}},
+		},
+	}
+	response := &admissionv1.AdmissionResponse{
+		Allowed: true,
+		Patch:   []byte(`[{"op":"add","path":"/data/password","value":"c2VjcmV0"}]`),
+	}
+	engineResponse := &engineapi.EngineResponse{}
+	engineResponse.PolicyResponse.Policy.Name = "add-password"
+	engineResponse.PolicyResponse.Rules = []engineapi.RuleResponse{
+		{Name: "add", Type: engineapi.Mutation, Status: engineapi.RuleStatusPass, Message: "mutated"},
+	}
+	ctx := WithRecorder(context.TODO())
+	Record(ctx, engineResponse)
+
+	var buffer bytes.Buffer
+	logger := NewLogger(logr.Discard(), &buffer, "user.extra", "")
+	logger.Log(NewEntry("mutate", request, response, time.Now(), Recorded(ctx)...))
+
+	var entry map[string]interface{}
+	assert.NilError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	assert.Equal(t, entry["uid"], "uid")
+	assert.Equal(t, entry["allowed"], true)
+	assert.Equal(t, entry["user"].(map[string]interface{})["username"], "alice")
+	assert.Equal(t, entry["user"].(map[string]interface{})["extra"], jsonutils.Redacted)
+	assert.Equal(t, entry["resource"].(map[string]interface{})["kind"], "Secret")
+	patch := entry["patches"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, patch["path"], "/data/password")
+	assert.Equal(t, patch["value"], jsonutils.Redacted)
+	rule := entry["policies"].([]interface{})[0].(map[string]interface{})["rules"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, rule["name"], "add")
+	assert.Equal(t, rule["status"], "pass")
+}
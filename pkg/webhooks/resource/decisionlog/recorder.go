@@ -0,0 +1,65 @@
+package decisionlog
+
+import (
+	"context"
+	"sync"
+
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+)
+
+type recorderKey struct{}
+
+// recorder collects the engine responses computed while processing an admission request
+type recorder struct {
+	lock      sync.Mutex
+	responses []*engineapi.EngineResponse
+	owners    map[string]string
+}
+
+// WithRecorder returns a context collecting the engine responses passed to Record
+func WithRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, recorderKey{}, &recorder{})
+}
+
+// Record adds engine responses to the recorder of the context, if any
+func Record(ctx context.Context, responses ...*engineapi.EngineResponse) {
+	if r, ok := ctx.Value(recorderKey{}).(*recorder); ok {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		r.responses = append(r.responses, responses...)
+	}
+}
+
+// Recorded returns the engine responses recorded in the context
+func Recorded(ctx context.Context) []*engineapi.EngineResponse {
+	if r, ok := ctx.Value(recorderKey{}).(*recorder); ok {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		return r.responses
+	}
+	return nil
+}
+
+// RecordPatchOwners records the policies that set the paths of the response patch, keyed by path
+func RecordPatchOwners(ctx context.Context, owners map[string]string) {
+	if r, ok := ctx.Value(recorderKey{}).(*recorder); ok {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if r.owners == nil {
+			r.owners = map[string]string{}
+		}
+		for path, owner := range owners {
+			r.owners[path] = owner
+		}
+	}
+}
+
+// RecordedPatchOwners returns the patch owners recorded in the context
+func RecordedPatchOwners(ctx context.Context) map[string]string {
+	if r, ok := ctx.Value(recorderKey{}).(*recorder); ok {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		return r.owners
+	}
+	return nil
+}
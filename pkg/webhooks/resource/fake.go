@@ -58,9 +58,10 @@ func NewFakeHandlers(ctx context.Context, policyCache policycache.Cache) webhook
 		urUpdater:      webhookutils.NewUpdateRequestUpdater(kyvernoclient, urLister),
 		engine: engine.NewEngine(
 			configuration,
+			config.NewDefaultMetricsConfiguration(),
 			dclient,
 			rclient,
-			engine.LegacyContextLoaderFactory(configMapResolver),
+			engine.LegacyContextLoaderFactory(configMapResolver, config.NewDefaultMetricsConfiguration()),
 			peLister,
 		),
 	}
@@ -12,6 +12,7 @@ import (
 	kyvernov1beta1listers "github.com/kyverno/kyverno/pkg/client/listers/kyverno/v1beta1"
 	"github.com/kyverno/kyverno/pkg/clients/dclient"
 	"github.com/kyverno/kyverno/pkg/config"
+	"github.com/kyverno/kyverno/pkg/controllers/idempotency"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	enginectx "github.com/kyverno/kyverno/pkg/engine/context"
 	"github.com/kyverno/kyverno/pkg/event"
@@ -23,10 +24,13 @@ import (
 	engineutils "github.com/kyverno/kyverno/pkg/utils/engine"
 	jsonutils "github.com/kyverno/kyverno/pkg/utils/json"
 	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
 	"github.com/kyverno/kyverno/pkg/webhooks"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/decisionlog"
 	"github.com/kyverno/kyverno/pkg/webhooks/resource/generation"
 	"github.com/kyverno/kyverno/pkg/webhooks/resource/imageverification"
 	"github.com/kyverno/kyverno/pkg/webhooks/resource/mutation"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/responsecache"
 	"github.com/kyverno/kyverno/pkg/webhooks/resource/validation"
 	webhookgenerate "github.com/kyverno/kyverno/pkg/webhooks/updaterequest"
 	webhookutils "github.com/kyverno/kyverno/pkg/webhooks/utils"
@@ -62,6 +66,11 @@ type handlers struct {
 	urUpdater      webhookutils.UpdateRequestUpdater
 
 	admissionReports bool
+	decisionLogger   decisionlog.Logger
+	responseCache    responsecache.Cache
+
+	verifyMutationIdempotency bool
+	idempotencyRecorder       idempotency.Recorder
 }
 
 func NewHandlers(
@@ -80,6 +89,10 @@ func NewHandlers(
 	eventGen event.Interface,
 	openApiManager openapi.ValidateInterface,
 	admissionReports bool,
+	decisionLogger decisionlog.Logger,
+	responseCache responsecache.Cache,
+	verifyMutationIdempotency bool,
+	idempotencyRecorder idempotency.Recorder,
 ) webhooks.ResourceHandlers {
 	return &handlers{
 		engine:           engine,
@@ -99,25 +112,63 @@ func NewHandlers(
 		pcBuilder:        webhookutils.NewPolicyContextBuilder(configuration, client, rbLister, crbLister),
 		urUpdater:        webhookutils.NewUpdateRequestUpdater(kyvernoClient, urLister),
 		admissionReports: admissionReports,
+		decisionLogger:   decisionLogger,
+		responseCache:    responseCache,
+
+		verifyMutationIdempotency: verifyMutationIdempotency,
+		idempotencyRecorder:       idempotencyRecorder,
 	}
 }
 
 func (h *handlers) Validate(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, failurePolicy string, startTime time.Time) *admissionv1.AdmissionResponse {
+	return h.logDecision(ctx, "validate", request, startTime, func(ctx context.Context) *admissionv1.AdmissionResponse {
+		return h.validate(ctx, logger, request, failurePolicy, startTime)
+	})
+}
+
+func (h *handlers) Mutate(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, failurePolicy string, startTime time.Time) *admissionv1.AdmissionResponse {
+	return h.logDecision(ctx, "mutate", request, startTime, func(ctx context.Context) *admissionv1.AdmissionResponse {
+		return h.mutate(ctx, logger, request, failurePolicy, startTime)
+	})
+}
+
+// logDecision writes the decision taken by the handler to the decision log, if enabled
+func (h *handlers) logDecision(
+	ctx context.Context,
+	webhook string,
+	request *admissionv1.AdmissionRequest,
+	startTime time.Time,
+	handler func(context.Context) *admissionv1.AdmissionResponse,
+) *admissionv1.AdmissionResponse {
+	if h.decisionLogger == nil {
+		return handler(ctx)
+	}
+	ctx = decisionlog.WithRecorder(ctx)
+	response := handler(ctx)
+	entry := decisionlog.NewEntry(webhook, request, response, startTime, decisionlog.Recorded(ctx)...)
+	entry.SetPatchOwners(decisionlog.RecordedPatchOwners(ctx))
+	h.decisionLogger.Log(entry)
+	return response
+}
+
+func (h *handlers) validate(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, failurePolicy string, startTime time.Time) *admissionv1.AdmissionResponse {
 	kind := request.Kind.Kind
 	logger = logger.WithValues("kind", kind)
 	logger.V(4).Info("received an admission request in validating webhook")
+	scopeID := scopeutils.FromContext(ctx)
 
 	// timestamp at which this admission request got triggered
-	policies := filterPolicies(failurePolicy, h.pCache.GetPolicies(policycache.ValidateEnforce, kind, request.Namespace)...)
-	mutatePolicies := filterPolicies(failurePolicy, h.pCache.GetPolicies(policycache.Mutate, kind, request.Namespace)...)
-	generatePolicies := filterPolicies(failurePolicy, h.pCache.GetPolicies(policycache.Generate, kind, request.Namespace)...)
-	imageVerifyValidatePolicies := filterPolicies(failurePolicy, h.pCache.GetPolicies(policycache.VerifyImagesValidate, kind, request.Namespace)...)
+	policies := filterPolicies(h.pCache, failurePolicy, scopeID, h.pCache.GetPolicies(policycache.ValidateEnforce, kind, request.Namespace)...)
+	mutatePolicies := filterPolicies(h.pCache, failurePolicy, scopeID, h.pCache.GetPolicies(policycache.Mutate, kind, request.Namespace)...)
+	generatePolicies := filterPolicies(h.pCache, failurePolicy, scopeID, h.pCache.GetPolicies(policycache.Generate, kind, request.Namespace)...)
+	imageVerifyValidatePolicies := filterPolicies(h.pCache, failurePolicy, scopeID, h.pCache.GetPolicies(policycache.VerifyImagesValidate, kind, request.Namespace)...)
 	policies = append(policies, imageVerifyValidatePolicies...)
 
 	if len(policies) == 0 && len(mutatePolicies) == 0 && len(generatePolicies) == 0 {
 		logger.V(4).Info("no policies matched admission request")
 	}
-	if len(generatePolicies) == 0 && request.Operation == admissionv1.Update {
+	// generate policies are never scoped, only the unscoped webhooks handle generate source updates
+	if len(generatePolicies) == 0 && request.Operation == admissionv1.Update && (scopeID == "" || scopeID == scopeutils.Unrestricted.ID()) {
 		// handle generate source resource updates
 		gh := generation.NewGenerationHandler(logger, h.engine, h.client, h.kyvernoClient, h.nsLister, h.urLister, h.urGenerator, h.urUpdater, h.eventGen, h.metricsConfig)
 		go gh.HandleUpdatesForGenerateRules(context.TODO(), request, []kyvernov1.PolicyInterface{})
@@ -125,41 +176,56 @@ func (h *handlers) Validate(ctx context.Context, logger logr.Logger, request *ad
 
 	logger.V(4).Info("processing policies for validate admission request", "validate", len(policies), "mutate", len(mutatePolicies), "generate", len(generatePolicies))
 
+	namespaceLabels := h.namespaceLabels(logger, request)
+	// audit policies are processed in the background but are part of the request processing
+	auditPolicies := h.pCache.GetPolicies(policycache.ValidateAudit, kind, request.Namespace)
+	vh := validation.NewValidationHandler(logger, h.kyvernoClient, h.engine, h.pCache, h.pcBuilder, h.eventGen, h.admissionReports, h.metricsConfig, h.configuration)
+	cacheKey := h.responseCacheKey("validate", request, namespaceLabels, policies, auditPolicies, mutatePolicies, generatePolicies)
+	if response, engineResponses := h.cachedResponse(ctx, logger, "validate", cacheKey, request); response != nil {
+		vh.HandleCached(ctx, request, namespaceLabels, response.Allowed, engineResponses)
+		return response
+	}
+
 	policyContext, err := h.pcBuilder.Build(request)
 	if err != nil {
 		return errorResponse(logger, request.UID, err, "failed create policy context")
 	}
-
-	namespaceLabels := make(map[string]string)
-	if request.Kind.Kind != "Namespace" && request.Namespace != "" {
-		namespaceLabels = engineutils.GetNamespaceSelectorsFromNamespaceLister(request.Kind.Kind, request.Namespace, h.nsLister, logger)
-	}
 	policyContext = policyContext.WithNamespaceLabels(namespaceLabels)
-	vh := validation.NewValidationHandler(logger, h.kyvernoClient, h.engine, h.pCache, h.pcBuilder, h.eventGen, h.admissionReports, h.metricsConfig, h.configuration)
 
-	ok, msg, warnings := vh.HandleValidation(ctx, request, policies, policyContext, startTime)
+	ok, msg, warnings, engineResponses := vh.HandleValidation(ctx, request, policies, policyContext, startTime)
 	if !ok {
 		logger.Info("admission request denied")
-		return admissionutils.Response(request.UID, errors.New(msg), warnings...)
+		return h.cacheResponse(cacheKey, admissionutils.Response(request.UID, errors.New(msg), warnings...), engineResponses...)
 	}
 
 	defer h.handleDelete(logger, request)
 	go h.createUpdateRequests(logger, request, policyContext, generatePolicies, mutatePolicies, startTime)
 
-	return admissionutils.ResponseSuccess(request.UID, warnings...)
+	return h.cacheResponse(cacheKey, admissionutils.ResponseSuccess(request.UID, warnings...), engineResponses...)
 }
 
-func (h *handlers) Mutate(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, failurePolicy string, startTime time.Time) *admissionv1.AdmissionResponse {
+func (h *handlers) mutate(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, failurePolicy string, startTime time.Time) *admissionv1.AdmissionResponse {
 	kind := request.Kind.Kind
 	logger = logger.WithValues("kind", kind)
 	logger.V(4).Info("received an admission request in mutating webhook")
-	mutatePolicies := filterPolicies(failurePolicy, h.pCache.GetPolicies(policycache.Mutate, kind, request.Namespace)...)
-	verifyImagesPolicies := filterPolicies(failurePolicy, h.pCache.GetPolicies(policycache.VerifyImagesMutate, kind, request.Namespace)...)
+	scopeID := scopeutils.FromContext(ctx)
+	mutatePolicies := filterPolicies(h.pCache, failurePolicy, scopeID, h.pCache.GetPolicies(policycache.Mutate, kind, request.Namespace)...)
+	verifyImagesPolicies := filterPolicies(h.pCache, failurePolicy, scopeID, h.pCache.GetPolicies(policycache.VerifyImagesMutate, kind, request.Namespace)...)
 	if len(mutatePolicies) == 0 && len(verifyImagesPolicies) == 0 {
 		logger.V(4).Info("no policies matched mutate admission request")
 		return admissionutils.ResponseSuccess(request.UID)
 	}
 	logger.V(4).Info("processing policies for mutate admission request", "mutatePolicies", len(mutatePolicies), "verifyImagesPolicies", len(verifyImagesPolicies))
+	mh := mutation.NewMutationHandler(logger, h.engine, h.eventGen, h.openApiManager, h.nsLister, h.metricsConfig, h.verifyMutationIdempotency, h.idempotencyRecorder)
+	var cacheKey string
+	// image verification depends on registries and records its own reports, it is never cached
+	if h.responseCache != nil && len(verifyImagesPolicies) == 0 {
+		cacheKey = h.responseCacheKey("mutate", request, h.namespaceLabels(logger, request), mutatePolicies)
+		if response, engineResponses := h.cachedResponse(ctx, logger, "mutate", cacheKey, request); response != nil {
+			mh.HandleCached(ctx, request, engineResponses)
+			return response
+		}
+	}
 	policyContext, err := h.pcBuilder.Build(request)
 	if err != nil {
 		logger.Error(err, "failed to build policy context")
@@ -169,8 +235,7 @@ func (h *handlers) Mutate(ctx context.Context, logger logr.Logger, request *admi
 	if err := enginectx.MutateResourceWithImageInfo(request.Object.Raw, policyContext.JSONContext()); err != nil {
 		logger.Error(err, "failed to patch images info to resource, policies that mutate images may be impacted")
 	}
-	mh := mutation.NewMutationHandler(logger, h.engine, h.eventGen, h.openApiManager, h.nsLister, h.metricsConfig)
-	mutatePatches, mutateWarnings, err := mh.HandleMutation(ctx, request, mutatePolicies, policyContext, startTime)
+	mutatePatches, mutateWarnings, mutateEngineResponses, err := mh.HandleMutation(ctx, request, mutatePolicies, policyContext, startTime)
 	if err != nil {
 		logger.Error(err, "mutation failed")
 		return admissionutils.Response(request.UID, err)
@@ -192,7 +257,51 @@ func (h *handlers) Mutate(ctx context.Context, logger logr.Logger, request *admi
 	var warnings []string
 	warnings = append(warnings, mutateWarnings...)
 	warnings = append(warnings, imageVerifyWarnings...)
-	return admissionutils.MutationResponse(request.UID, patch, warnings...)
+	return h.cacheResponse(cacheKey, admissionutils.MutationResponse(request.UID, patch, warnings...), mutateEngineResponses...)
+}
+
+func (h *handlers) namespaceLabels(logger logr.Logger, request *admissionv1.AdmissionRequest) map[string]string {
+	if request.Kind.Kind != "Namespace" && request.Namespace != "" {
+		return engineutils.GetNamespaceSelectorsFromNamespaceLister(request.Kind.Kind, request.Namespace, h.nsLister, logger)
+	}
+	return map[string]string{}
+}
+
+// responseCacheKey returns the key of the request in the response cache, empty if the response can't be cached
+func (h *handlers) responseCacheKey(webhook string, request *admissionv1.AdmissionRequest, namespaceLabels map[string]string, policies ...[]kyvernov1.PolicyInterface) string {
+	if h.responseCache == nil {
+		return ""
+	}
+	var all []kyvernov1.PolicyInterface
+	for _, p := range policies {
+		all = append(all, p...)
+	}
+	key, ok := h.responseCache.Key(webhook, request, namespaceLabels, all...)
+	if !ok {
+		return ""
+	}
+	return key
+}
+
+// cachedResponse returns the response computed for an identical request and the engine responses
+// it was computed from, nil if none
+func (h *handlers) cachedResponse(ctx context.Context, logger logr.Logger, webhook string, key string, request *admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, []*engineapi.EngineResponse) {
+	if key == "" {
+		return nil, nil
+	}
+	response, engineResponses, ok := h.responseCache.Get(ctx, webhook, key, request.UID)
+	if !ok {
+		return nil, nil
+	}
+	logger.V(4).Info("reusing the response of an identical admission request")
+	return response, engineResponses
+}
+
+func (h *handlers) cacheResponse(key string, response *admissionv1.AdmissionResponse, engineResponses ...*engineapi.EngineResponse) *admissionv1.AdmissionResponse {
+	if key != "" {
+		h.responseCache.Add(key, response, engineResponses...)
+	}
+	return response
 }
 
 func (h *handlers) handleDelete(logger logr.Logger, request *admissionv1.AdmissionRequest) {
@@ -219,9 +328,13 @@ func (h *handlers) handleDelete(logger logr.Logger, request *admissionv1.Admissi
 	}
 }
 
-func filterPolicies(failurePolicy string, policies ...kyvernov1.PolicyInterface) []kyvernov1.PolicyInterface {
+func filterPolicies(pCache policycache.Cache, failurePolicy string, scopeID string, policies ...kyvernov1.PolicyInterface) []kyvernov1.PolicyInterface {
 	var results []kyvernov1.PolicyInterface
 	for _, policy := range policies {
+		// per policy webhooks only process the policies sharing their scope
+		if scopeID != "" && pCache.GetScopeID(policy) != scopeID {
+			continue
+		}
 		if failurePolicy == "fail" {
 			if policy.GetSpec().GetFailurePolicy() == kyvernov1.Fail {
 				results = append(results, policy)
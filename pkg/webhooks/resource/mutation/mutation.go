@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-logr/logr"
 	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/controllers/idempotency"
 	"github.com/kyverno/kyverno/pkg/engine"
 	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
 	"github.com/kyverno/kyverno/pkg/event"
@@ -16,7 +17,8 @@ import (
 	"github.com/kyverno/kyverno/pkg/tracing"
 	"github.com/kyverno/kyverno/pkg/utils"
 	engineutils "github.com/kyverno/kyverno/pkg/utils/engine"
-	jsonutils "github.com/kyverno/kyverno/pkg/utils/json"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/decisionlog"
 	webhookutils "github.com/kyverno/kyverno/pkg/webhooks/utils"
 	"go.opentelemetry.io/otel/trace"
 	admissionv1 "k8s.io/api/admission/v1"
@@ -29,7 +31,11 @@ type MutationHandler interface {
 	// HandleMutation handles validating webhook admission request
 	// If there are no errors in validating rule we apply generation rules
 	// patchedResource is the (resource + patches) after applying mutation rules
-	HandleMutation(context.Context, *admissionv1.AdmissionRequest, []kyvernov1.PolicyInterface, *engine.PolicyContext, time.Time) ([]byte, []string, error)
+	// It also returns the engine responses the patches were computed from
+	HandleMutation(context.Context, *admissionv1.AdmissionRequest, []kyvernov1.PolicyInterface, *engine.PolicyContext, time.Time) ([]byte, []string, []*engineapi.EngineResponse, error)
+	// HandleCached emits the metrics and events of a request whose response was computed
+	// for an identical request, from the engine responses of that request
+	HandleCached(context.Context, *admissionv1.AdmissionRequest, []*engineapi.EngineResponse)
 }
 
 func NewMutationHandler(
@@ -39,24 +45,31 @@ func NewMutationHandler(
 	openApiManager openapi.ValidateInterface,
 	nsLister corev1listers.NamespaceLister,
 	metrics metrics.MetricsConfigManager,
+	verifyIdempotency bool,
+	idempotencyRecorder idempotency.Recorder,
 ) MutationHandler {
 	return &mutationHandler{
-		log:            log,
-		engine:         engine,
-		eventGen:       eventGen,
-		openApiManager: openApiManager,
-		nsLister:       nsLister,
-		metrics:        metrics,
+		log:                 log,
+		engine:              engine,
+		eventGen:            eventGen,
+		openApiManager:      openApiManager,
+		nsLister:            nsLister,
+		metrics:             metrics,
+		verifyIdempotency:   verifyIdempotency,
+		idempotencyRecorder: idempotencyRecorder,
 	}
 }
 
 type mutationHandler struct {
-	log            logr.Logger
-	engine         engineapi.Engine
-	eventGen       event.Interface
-	openApiManager openapi.ValidateInterface
-	nsLister       corev1listers.NamespaceLister
-	metrics        metrics.MetricsConfigManager
+	log               logr.Logger
+	engine            engineapi.Engine
+	eventGen          event.Interface
+	openApiManager    openapi.ValidateInterface
+	nsLister          corev1listers.NamespaceLister
+	metrics           metrics.MetricsConfigManager
+	verifyIdempotency bool
+	// idempotencyRecorder reports non idempotent rules in the policy status, it can be nil
+	idempotencyRecorder idempotency.Recorder
 }
 
 func (h *mutationHandler) HandleMutation(
@@ -65,33 +78,51 @@ func (h *mutationHandler) HandleMutation(
 	policies []kyvernov1.PolicyInterface,
 	policyContext *engine.PolicyContext,
 	admissionRequestTimestamp time.Time,
-) ([]byte, []string, error) {
-	mutatePatches, mutateEngineResponses, err := h.applyMutations(ctx, request, policies, policyContext)
+) ([]byte, []string, []*engineapi.EngineResponse, error) {
+	mutatePatches, mutateEngineResponses, conflictWarnings, err := h.applyMutations(ctx, request, policies, policyContext)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	h.log.V(6).Info("", "generated patches", string(mutatePatches))
-	return mutatePatches, webhookutils.GetWarningMessages(mutateEngineResponses), nil
+	return mutatePatches, append(webhookutils.GetWarningMessages(mutateEngineResponses), conflictWarnings...), mutateEngineResponses, nil
+}
+
+func (h *mutationHandler) HandleCached(ctx context.Context, request *admissionv1.AdmissionRequest, engineResponses []*engineapi.EngineResponse) {
+	resource, err := kubeutils.BytesToUnstructured(request.Object.Raw)
+	if err != nil {
+		h.log.Error(err, "failed to convert object resource to unstructured format")
+		return
+	}
+	// conflicts are not cached, they are computed again from the policy patches
+	var patches []appliedPatches
+	for _, engineResponse := range engineResponses {
+		if policyPatches := engineResponse.GetPatches(); len(policyPatches) > 0 {
+			patches = append(patches, appliedPatches{policy: engineResponse.Policy, patches: policyPatches})
+		}
+	}
+	merged := mergePatches(request.Object.Raw, patches...)
+	h.emit(ctx, request, *resource, resource.GetDeletionTimestamp() != nil, merged.conflicts, engineResponses...)
 }
 
 // applyMutations handles mutating webhook admission request
-// return value: generated patches, triggered policies, engine responses correspdonding to the triggered policies
+// return value: generated patches, engine responses correspdonding to the triggered policies, conflicts between policies
 func (v *mutationHandler) applyMutations(
 	ctx context.Context,
 	request *admissionv1.AdmissionRequest,
 	policies []kyvernov1.PolicyInterface,
 	policyContext *engine.PolicyContext,
-) ([]byte, []*engineapi.EngineResponse, error) {
+) ([]byte, []*engineapi.EngineResponse, []string, error) {
 	if len(policies) == 0 {
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 
 	if isResourceDeleted(policyContext) && request.Operation == admissionv1.Update {
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 
-	var patches [][]byte
+	var patches []appliedPatches
 	var engineResponses []*engineapi.EngineResponse
+	var mutatePolicies []kyvernov1.PolicyInterface
 
 	for _, policy := range policies {
 		spec := policy.GetSpec()
@@ -112,7 +143,7 @@ func (v *mutationHandler) applyMutations(
 				}
 
 				if len(policyPatches) > 0 {
-					patches = append(patches, policyPatches...)
+					patches = append(patches, appliedPatches{policy: policy, patches: policyPatches})
 					rules := engineResponse.GetSuccessRules()
 					if len(rules) != 0 {
 						v.log.Info("mutation rules from policy applied successfully", "policy", policy.GetName(), "rules", rules)
@@ -121,34 +152,69 @@ func (v *mutationHandler) applyMutations(
 
 				policyContext = currentContext.WithNewResource(engineResponse.PatchedResource)
 				engineResponses = append(engineResponses, engineResponse)
-
-				// registering the kyverno_policy_results_total metric concurrently
-				go webhookutils.RegisterPolicyResultsMetricMutation(context.TODO(), v.log, v.metrics, string(request.Operation), policy, *engineResponse)
-				// registering the kyverno_policy_execution_duration_seconds metric concurrently
-				go webhookutils.RegisterPolicyExecutionDurationMetricMutate(context.TODO(), v.log, v.metrics, string(request.Operation), policy, *engineResponse)
+				mutatePolicies = append(mutatePolicies, policy)
 
 				return nil
 			},
 		)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
+	decisionlog.Record(ctx, engineResponses...)
+
+	var warnings []string
+	if v.verifyIdempotency && len(patches) > 0 {
+		warnings = append(warnings, v.checkIdempotency(ctx, request, policyContext, mutatePolicies)...)
+	}
 
 	// generate annotations
 	if annPatches := utils.GenerateAnnotationPatches(engineResponses, v.log); annPatches != nil {
-		patches = append(patches, annPatches...)
+		patches = append(patches, appliedPatches{patches: annPatches})
 	}
 
-	if !isResourceDeleted(policyContext) {
-		events := webhookutils.GenerateEvents(engineResponses, false)
-		v.eventGen.Add(events...)
+	merged := mergePatches(request.Object.Raw, patches...)
+	decisionlog.RecordPatchOwners(ctx, merged.owners)
+	for _, conflict := range merged.conflicts {
+		v.log.Info("policies set conflicting values", "path", conflict.path, "policy", policyKey(conflict.policy), "overwritten", policyKey(conflict.overwritten))
+		warnings = append(warnings, conflict.String())
 	}
 
-	logMutationResponse(patches, engineResponses, v.log)
+	v.emit(ctx, request, policyContext.NewResource(), isResourceDeleted(policyContext), merged.conflicts, engineResponses...)
+
+	logMutationResponse(merged.patch, engineResponses, v.log)
+
+	// the merged patch holds all the successful patches, if no patch is created, it is nil
+	return merged.patch, engineResponses, warnings, nil
+}
 
-	// patches holds all the successful patches, if no patch is created, it returns nil
-	return jsonutils.JoinPatches(patches...), engineResponses, nil
+// emit registers the metrics and events of the engine responses and of the conflicts between their patches
+func (v *mutationHandler) emit(
+	ctx context.Context,
+	request *admissionv1.AdmissionRequest,
+	resource unstructured.Unstructured,
+	deleted bool,
+	conflicts []patchConflict,
+	engineResponses ...*engineapi.EngineResponse,
+) {
+	for _, engineResponse := range engineResponses {
+		// registering the kyverno_policy_results_total metric concurrently
+		go webhookutils.RegisterPolicyResultsMetricMutation(context.TODO(), v.log, v.metrics, string(request.Operation), engineResponse.Policy, *engineResponse)
+		// registering the kyverno_policy_execution_duration_seconds metric concurrently
+		go webhookutils.RegisterPolicyExecutionDurationMetricMutate(context.TODO(), v.log, v.metrics, string(request.Operation), engineResponse.Policy, *engineResponse)
+	}
+	if deleted {
+		return
+	}
+	events := webhookutils.GenerateEvents(engineResponses, false)
+	for _, conflict := range conflicts {
+		events = append(events, event.NewPolicyConflictEvent(event.AdmissionController, conflict.policy, conflict.overwritten, engineapi.ResourceSpec{
+			Kind:      resource.GetKind(),
+			Namespace: resource.GetNamespace(),
+			Name:      resource.GetName(),
+		}, conflict.path))
+	}
+	v.eventGen.Add(events...)
 }
 
 func (h *mutationHandler) applyMutation(ctx context.Context, request *admissionv1.AdmissionRequest, policyContext *engine.PolicyContext) (*engineapi.EngineResponse, [][]byte, error) {
@@ -173,9 +239,52 @@ func (h *mutationHandler) applyMutation(ctx context.Context, request *admissionv
 	return engineResponse, policyPatches, nil
 }
 
-func logMutationResponse(patches [][]byte, engineResponses []*engineapi.EngineResponse, logger logr.Logger) {
-	if len(patches) != 0 {
-		logger.V(4).Info("created patches", "count", len(patches))
+// checkIdempotency applies the mutate policies again to the mutated resource and reports the rules producing
+// further patches with a warning, an event and a condition in the policy status
+func (h *mutationHandler) checkIdempotency(
+	ctx context.Context,
+	request *admissionv1.AdmissionRequest,
+	policyContext *engine.PolicyContext,
+	policies []kyvernov1.PolicyInterface,
+) []string {
+	if request.Kind.Kind != "Namespace" && request.Namespace != "" {
+		policyContext = policyContext.WithNamespaceLabels(engineutils.GetNamespaceSelectorsFromNamespaceLister(request.Kind.Kind, request.Namespace, h.nsLister, h.log))
+	}
+	nonIdempotentRules, err := engine.NonIdempotentRules(ctx, h.engine, policyContext, policies...)
+	if err != nil {
+		h.log.Error(err, "failed to verify mutation idempotency")
+		return nil
+	}
+	var warnings []string
+	var reported []kyvernov1.PolicyInterface
+	rulesByPolicy := map[kyvernov1.PolicyInterface][]string{}
+	for _, nonIdempotentRule := range nonIdempotentRules {
+		policy := nonIdempotentRule.Policy
+		if _, ok := rulesByPolicy[policy]; !ok {
+			reported = append(reported, policy)
+		}
+		rulesByPolicy[policy] = append(rulesByPolicy[policy], nonIdempotentRule.Rule)
+		h.log.Info("mutate rule is not idempotent", "policy", policyKey(policy), "rule", nonIdempotentRule.Rule)
+		warnings = append(warnings, fmt.Sprintf("mutate rule %s of policy %s is not idempotent, applying it again produced further patches", nonIdempotentRule.Rule, policyKey(policy)))
+	}
+	resource := policyContext.NewResource()
+	for _, policy := range reported {
+		rules := rulesByPolicy[policy]
+		h.eventGen.Add(event.NewPolicyNotIdempotentEvent(event.AdmissionController, policy, engineapi.ResourceSpec{
+			Kind:      resource.GetKind(),
+			Namespace: resource.GetNamespace(),
+			Name:      resource.GetName(),
+		}, rules...))
+		if h.idempotencyRecorder != nil {
+			h.idempotencyRecorder.Record(policy, rules...)
+		}
+	}
+	return warnings
+}
+
+func logMutationResponse(patch []byte, engineResponses []*engineapi.EngineResponse, logger logr.Logger) {
+	if len(patch) != 0 {
+		logger.V(4).Info("created patches", "size", len(patch))
 	}
 
 	// if any of the policies fails, print out the error
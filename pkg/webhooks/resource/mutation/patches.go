@@ -0,0 +1,178 @@
+package mutation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	jsonutils "github.com/kyverno/kyverno/pkg/utils/json"
+	diffpatch "github.com/mattbaird/jsonpatch"
+)
+
+// appliedPatches holds the patches produced by a policy, patches added by kyverno itself have no policy
+type appliedPatches struct {
+	policy  kyvernov1.PolicyInterface
+	patches [][]byte
+}
+
+// patchConflict is a path set by a policy and overwritten by a policy applied later
+type patchConflict struct {
+	path        string
+	policy      kyvernov1.PolicyInterface
+	overwritten kyvernov1.PolicyInterface
+}
+
+func (c patchConflict) String() string {
+	return fmt.Sprintf("mutation conflict: policy %s overwrote path %s set by policy %s", policyKey(c.policy), c.path, policyKey(c.overwritten))
+}
+
+// mergedPatch is the composition of the patches of several policies
+type mergedPatch struct {
+	patch []byte
+	// owners maps the paths of the patch to the key of the last policy that set them
+	owners    map[string]string
+	conflicts []patchConflict
+}
+
+// write is a change made by a policy
+type write struct {
+	path   string
+	op     string
+	value  interface{}
+	policy kyvernov1.PolicyInterface
+}
+
+// mergePatches composes the patches of the policies, in order, into a minimal patch between the original
+// and the final object. The patches are joined as they are when they can't be applied.
+func mergePatches(original []byte, patches ...appliedPatches) mergedPatch {
+	var all [][]byte
+	for _, p := range patches {
+		all = append(all, p.patches...)
+	}
+	result := mergedPatch{patch: jsonutils.JoinPatches(all...)}
+	if result.patch == nil {
+		return result
+	}
+	// the changes made by each policy are computed on the object patched by the previous ones
+	var writes []write
+	current := original
+	for _, p := range patches {
+		next, err := applyPatch(current, jsonutils.JoinPatches(p.patches...))
+		if err != nil {
+			return result
+		}
+		changes, err := diffpatch.CreatePatch(current, next)
+		if err != nil {
+			return result
+		}
+		for _, change := range changes {
+			writes = append(writes, write{path: change.Path, op: change.Operation, value: change.Value, policy: p.policy})
+		}
+		current = next
+	}
+	result.conflicts = detectConflicts(writes)
+	if minimal, ok := minimalPatch(original, current); ok && len(minimal) < len(result.patch) {
+		result.patch = minimal
+	}
+	var operations []jsonutils.PatchOperation
+	if err := json.Unmarshal(result.patch, &operations); err == nil {
+		result.owners = map[string]string{}
+		for _, operation := range operations {
+			if owner := pathOwner(operation.Path, writes); owner != nil {
+				result.owners[operation.Path] = policyKey(owner)
+			}
+		}
+	}
+	return result
+}
+
+// detectConflicts returns the paths changed by a policy and changed again by a different policy
+func detectConflicts(writes []write) []patchConflict {
+	var conflicts []patchConflict
+	seen := map[patchConflict]bool{}
+	for i, w := range writes {
+		if w.policy == nil {
+			continue
+		}
+		for _, earlier := range writes[:i] {
+			if earlier.policy == nil || policyKey(earlier.policy) == policyKey(w.policy) {
+				continue
+			}
+			if earlier.path != w.path && !strings.HasPrefix(earlier.path, w.path+"/") {
+				continue
+			}
+			// adding an element to an array doesn't overwrite the elements added before
+			if earlier.op == "add" && w.op == "add" && isArrayIndex(w.path) {
+				continue
+			}
+			conflict := patchConflict{path: earlier.path, policy: w.policy, overwritten: earlier.policy}
+			if !seen[conflict] {
+				seen[conflict] = true
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+	return conflicts
+}
+
+// minimalPatch returns the diff between the original and the patched object,
+// it returns false if the diff doesn't produce the patched object
+func minimalPatch(original []byte, patched []byte) ([]byte, bool) {
+	operations, err := diffpatch.CreatePatch(original, patched)
+	if err != nil {
+		return nil, false
+	}
+	if len(operations) == 0 {
+		return nil, true
+	}
+	minimal, err := json.Marshal(operations)
+	if err != nil {
+		return nil, false
+	}
+	check, err := applyPatch(original, minimal)
+	if err != nil || !jsonpatch.Equal(check, patched) {
+		return nil, false
+	}
+	return minimal, true
+}
+
+func applyPatch(document []byte, patch []byte) ([]byte, error) {
+	if patch == nil {
+		return document, nil
+	}
+	decoded, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return decoded.Apply(document)
+}
+
+func isArrayIndex(path string) bool {
+	_, err := strconv.Atoi(path[strings.LastIndex(path, "/")+1:])
+	return err == nil
+}
+
+// pathOwner returns the last policy that wrote the path, one of its parents or one of its children
+func pathOwner(path string, writes []write) kyvernov1.PolicyInterface {
+	for i := len(writes) - 1; i >= 0; i-- {
+		written := writes[i].path
+		if written == path || strings.HasPrefix(path, written+"/") || strings.HasPrefix(written, path+"/") {
+			return writes[i].policy
+		}
+	}
+	return nil
+}
+
+func policyKey(policy kyvernov1.PolicyInterface) string {
+	if policy == nil {
+		return ""
+	}
+	key := policy.GetName()
+	if policy.IsNamespaced() {
+		key = policy.GetNamespace() + "/" + key
+	}
+	return key
+}
@@ -0,0 +1,66 @@
+package mutation
+
+import (
+	"testing"
+
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"gotest.tools/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_mergePatches(t *testing.T) {
+	labels := &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "labels"}}
+	team := &kyvernov1.Policy{ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "default"}}
+	original := []byte(`{"metadata":{"name":"test","labels":{"app":"web"}},"spec":{"replicas":1}}`)
+
+	merged := mergePatches(original,
+		appliedPatches{policy: labels, patches: [][]byte{
+			[]byte(`{"op":"add","path":"/metadata/labels/team","value":"a"}`),
+			[]byte(`{"op":"replace","path":"/spec/replicas","value":2}`),
+		}},
+		appliedPatches{policy: team, patches: [][]byte{
+			[]byte(`{"op":"replace","path":"/metadata/labels/team","value":"b"}`),
+			[]byte(`{"op":"replace","path":"/spec/replicas","value":1}`),
+		}},
+	)
+	// the replicas are back to their original value
+	assert.Equal(t, string(merged.patch), `[{"op":"add","path":"/metadata/labels/team","value":"b"}]`)
+	assert.DeepEqual(t, merged.owners, map[string]string{"/metadata/labels/team": "default/team"})
+	var conflicts []string
+	for _, conflict := range merged.conflicts {
+		conflicts = append(conflicts, conflict.String())
+	}
+	assert.DeepEqual(t, conflicts, []string{
+		"mutation conflict: policy default/team overwrote path /metadata/labels/team set by policy labels",
+		"mutation conflict: policy default/team overwrote path /spec/replicas set by policy labels",
+	})
+}
+
+func Test_mergePatches_NoConflict(t *testing.T) {
+	first := &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "first"}}
+	second := &kyvernov1.ClusterPolicy{ObjectMeta: metav1.ObjectMeta{Name: "second"}}
+	original := []byte(`{"spec":{"containers":[{"name":"a"}]}}`)
+
+	merged := mergePatches(original,
+		appliedPatches{policy: first, patches: [][]byte{
+			[]byte(`{"op":"add","path":"/spec/containers/-","value":{"name":"b"}}`),
+		}},
+		appliedPatches{policy: second, patches: [][]byte{
+			[]byte(`{"op":"add","path":"/spec/containers/-","value":{"name":"c"}}`),
+		}},
+		appliedPatches{patches: [][]byte{
+			[]byte(`{"op":"add","path":"/metadata","value":{"annotations":{"policies.kyverno.io/last-applied-patches":""}}}`),
+		}},
+	)
+	assert.Equal(t, len(merged.conflicts), 0)
+	assert.Equal(t, merged.owners["/spec/containers/1"], "first")
+	assert.Equal(t, merged.owners["/spec/containers/2"], "second")
+	_, ok := merged.owners["/metadata"]
+	assert.Assert(t, !ok)
+
+	// patches that can't be applied are joined as they are
+	invalid := mergePatches(original, appliedPatches{policy: first, patches: [][]byte{
+		[]byte(`{"op":"replace","path":"/spec/missing","value":1}`),
+	}})
+	assert.Equal(t, string(invalid.patch), `[{"op":"replace","path":"/spec/missing","value":1}]`)
+}
@@ -0,0 +1,190 @@
+package responsecache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	"github.com/kyverno/kyverno/pkg/autogen"
+	"github.com/kyverno/kyverno/pkg/config"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+)
+
+// Disabled opts a policy out of response caching when set on the AnnotationAdmissionCache annotation
+const Disabled = "disabled"
+
+// Cache reuses the responses computed for identical admission requests during a short period of time
+type Cache interface {
+	// Key returns the fingerprint of a request processed by the given policies,
+	// it returns false when the response can't be reused
+	Key(webhook string, request *admissionv1.AdmissionRequest, namespaceLabels map[string]string, policies ...kyvernov1.PolicyInterface) (string, bool)
+	// Get returns a copy of the response stored for the key, bound to the given request uid,
+	// and the engine responses it was computed from, they are shared and must not be modified
+	Get(ctx context.Context, webhook string, key string, uid types.UID) (*admissionv1.AdmissionResponse, []*engineapi.EngineResponse, bool)
+	// Add stores the response for the key, with the engine responses needed to emit the
+	// metrics, events and reports of the requests reusing it
+	Add(key string, response *admissionv1.AdmissionResponse, engineResponses ...*engineapi.EngineResponse)
+}
+
+type entry struct {
+	response        *admissionv1.AdmissionResponse
+	engineResponses []*engineapi.EngineResponse
+}
+
+type responseCache struct {
+	responses     *cache.LRUExpireCache
+	ttl           time.Duration
+	metrics       cacheMetrics
+	configuration config.Configuration
+	exceptions    engineapi.PolicyExceptionSelector
+}
+
+// NewCache creates a cache holding up to size responses for ttl,
+// exceptions can be nil when policy exceptions are disabled
+func NewCache(
+	logger logr.Logger,
+	size int,
+	ttl time.Duration,
+	configuration config.Configuration,
+	exceptions engineapi.PolicyExceptionSelector,
+) Cache {
+	return &responseCache{
+		responses:     cache.NewLRUExpireCache(size),
+		ttl:           ttl,
+		metrics:       newCacheMetrics(logger),
+		configuration: configuration,
+		exceptions:    exceptions,
+	}
+}
+
+// fingerprint holds everything the response of a request depends on, the request uid excepted
+type fingerprint struct {
+	Webhook         string                        `json:"webhook"`
+	Policies        []string                      `json:"policies"`
+	Exceptions      []string                      `json:"exceptions,omitempty"`
+	Configuration   string                        `json:"configuration"`
+	NamespaceLabels map[string]string             `json:"namespaceLabels,omitempty"`
+	Request         *admissionv1.AdmissionRequest `json:"request"`
+}
+
+func (c *responseCache) Key(webhook string, request *admissionv1.AdmissionRequest, namespaceLabels map[string]string, policies ...kyvernov1.PolicyInterface) (string, bool) {
+	// deletions and connections are rare and can trigger side effects
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return "", false
+	}
+	if len(policies) == 0 {
+		return "", false
+	}
+	// the policy set revision, a policy update changes its resource version
+	revisions := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		if !Cacheable(policy) {
+			return "", false
+		}
+		revisions = append(revisions, policy.GetNamespace()+"/"+policy.GetName()+"@"+policy.GetResourceVersion())
+	}
+	sort.Strings(revisions)
+	// an exception or configuration update changes the responses of all policies
+	var exceptions []string
+	if c.exceptions != nil {
+		polexs, err := c.exceptions.List(labels.Everything())
+		if err != nil {
+			return "", false
+		}
+		for _, polex := range polexs {
+			exceptions = append(exceptions, polex.GetNamespace()+"/"+polex.GetName()+"@"+polex.GetResourceVersion())
+		}
+		sort.Strings(exceptions)
+	}
+	anonymous := request.DeepCopy()
+	anonymous.UID = ""
+	data, err := json.Marshal(fingerprint{
+		Webhook:         webhook,
+		Policies:        revisions,
+		Exceptions:      exceptions,
+		Configuration:   c.configuration.GetResourceVersion(),
+		NamespaceLabels: namespaceLabels,
+		Request:         anonymous,
+	})
+	if err != nil {
+		return "", false
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), true
+}
+
+func (c *responseCache) Get(ctx context.Context, webhook string, key string, uid types.UID) (*admissionv1.AdmissionResponse, []*engineapi.EngineResponse, bool) {
+	value, ok := c.responses.Get(key)
+	c.metrics.recordLookup(ctx, webhook, ok)
+	if !ok {
+		return nil, nil, false
+	}
+	entry := value.(entry)
+	response := entry.response.DeepCopy()
+	response.UID = uid
+	return response, entry.engineResponses, true
+}
+
+func (c *responseCache) Add(key string, response *admissionv1.AdmissionResponse, engineResponses ...*engineapi.EngineResponse) {
+	c.responses.Add(key, entry{response: response.DeepCopy(), engineResponses: engineResponses}, c.ttl)
+}
+
+// Cacheable returns true if the responses of a policy only depend on the admission request and the policy itself,
+// policies loading external data, verifying images or manifests, or creating update requests are not cacheable
+func Cacheable(policy kyvernov1.PolicyInterface) bool {
+	if policy.GetAnnotations()[kyvernov1.AnnotationAdmissionCache] == Disabled {
+		return false
+	}
+	for _, rule := range autogen.ComputeRules(policy) {
+		if rule.HasGenerate() || rule.IsMutateExisting() || rule.HasVerifyImages() || rule.HasYAMLSignatureVerify() {
+			return false
+		}
+		if loadsExternalData(rule.Context) {
+			return false
+		}
+		for _, foreach := range rule.Validation.ForEachValidation {
+			if loadsExternalData(foreach.Context) || nestedLoadsExternalData(foreach.ForEachValidation) {
+				return false
+			}
+		}
+		for _, foreach := range rule.Mutation.ForEachMutation {
+			if loadsExternalData(foreach.Context) || nestedLoadsExternalData(foreach.ForEachMutation) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func loadsExternalData(entries []kyvernov1.ContextEntry) bool {
+	for _, entry := range entries {
+		if entry.APICall != nil || entry.ConfigMap != nil || entry.ImageRegistry != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedLoadsExternalData looks for external data sources in nested foreach declarations, kept as raw json
+func nestedLoadsExternalData(nested *apiextv1.JSON) bool {
+	if nested == nil {
+		return false
+	}
+	for _, field := range []string{`"apiCall"`, `"configMap"`, `"imageRegistry"`} {
+		if bytes.Contains(nested.Raw, []byte(field)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package responsecache
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	kyvernov1 "github.com/kyverno/kyverno/api/kyverno/v1"
+	kyvernov2alpha1 "github.com/kyverno/kyverno/api/kyverno/v2alpha1"
+	"github.com/kyverno/kyverno/pkg/config"
+	engineapi "github.com/kyverno/kyverno/pkg/engine/api"
+	"gotest.tools/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newPolicy(t *testing.T, resourceVersion string, spec string) kyvernov1.PolicyInterface {
+	policy := &kyvernov1.ClusterPolicy{}
+	policy.SetName("test")
+	policy.SetResourceVersion(resourceVersion)
+	assert.NilError(t, json.Unmarshal([]byte(spec), &policy.Spec))
+	return policy
+}
+
+const validatePolicy = `{
+	"rules": [{
+		"name": "check",
+		"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+		"validate": {"message": "message", "pattern": {"data": {"foo": "?*"}}}
+	}]
+}`
+
+type exceptions []*kyvernov2alpha1.PolicyException
+
+func (e *exceptions) List(labels.Selector) ([]*kyvernov2alpha1.PolicyException, error) {
+	return *e, nil
+}
+
+func Test_Cacheable(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   bool
+	}{{
+		name:   "validate",
+		policy: validatePolicy,
+		want:   true,
+	}, {
+		name: "api call",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"context": [{"name": "pods", "apiCall": {"urlPath": "/api/v1/pods"}}],
+				"validate": {"message": "message", "deny": {}}
+			}]
+		}`,
+	}, {
+		name: "nested foreach config map",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"validate": {"message": "message", "foreach": [{
+					"list": "request.object.data",
+					"foreach": [{"list": "element", "context": [{"name": "cm", "configMap": {"name": "cm", "namespace": "default"}}], "deny": {}}]
+				}]}
+			}]
+		}`,
+	}, {
+		name: "variable",
+		policy: `{
+			"rules": [{
+				"name": "check",
+				"match": {"any": [{"resources": {"kinds": ["ConfigMap"]}}]},
+				"context": [{"name": "name", "variable": {"jmesPath": "request.object.metadata.name"}}],
+				"validate": {"message": "message", "deny": {}}
+			}]
+		}`,
+		want: true,
+	}, {
+		name: "generate",
+		policy: `{
+			"rules": [{
+				"name": "generate",
+				"match": {"any": [{"resources": {"kinds": ["Namespace"]}}]},
+				"generate": {"kind": "ConfigMap", "name": "test", "namespace": "{{ request.object.metadata.name }}", "data": {}}
+			}]
+		}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Cacheable(newPolicy(t, "1", tt.policy)), tt.want)
+		})
+	}
+	optedOut := newPolicy(t, "1", validatePolicy)
+	optedOut.SetAnnotations(map[string]string{kyvernov1.AnnotationAdmissionCache: Disabled})
+	assert.Assert(t, !Cacheable(optedOut))
+}
+
+func Test_Cache(t *testing.T) {
+	ctx := context.Background()
+	configuration := config.NewDefaultConfiguration()
+	polexs := &exceptions{}
+	cache := NewCache(logr.Discard(), 10, time.Minute, configuration, polexs)
+	policy := newPolicy(t, "1", validatePolicy)
+	request := func(uid string, object string, operation admissionv1.Operation) *admissionv1.AdmissionRequest {
+		return &admissionv1.AdmissionRequest{UID: types.UID(uid), Operation: operation, Object: runtime.RawExtension{Raw: []byte(object)}}
+	}
+	key, ok := cache.Key("validate", request("a", `{"data":{"foo":"bar"}}`, admissionv1.Update), nil, policy)
+	assert.Assert(t, ok)
+	_, _, ok = cache.Get(ctx, "validate", key, "b")
+	assert.Assert(t, !ok)
+	engineResponse := &engineapi.EngineResponse{}
+	cache.Add(key, &admissionv1.AdmissionResponse{UID: "a", Allowed: true}, engineResponse)
+
+	// identical request with a different uid
+	other, ok := cache.Key("validate", request("b", `{"data":{"foo":"bar"}}`, admissionv1.Update), nil, policy)
+	assert.Assert(t, ok)
+	assert.Equal(t, other, key)
+	response, engineResponses, ok := cache.Get(ctx, "validate", other, "b")
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, response, &admissionv1.AdmissionResponse{UID: "b", Allowed: true})
+	assert.Equal(t, len(engineResponses), 1)
+	assert.Assert(t, engineResponses[0] == engineResponse)
+
+	// different object, webhook, namespace labels or policy revision
+	for _, changed := range []func() (string, bool){
+		func() (string, bool) {
+			return cache.Key("validate", request("b", `{"data":{"foo":"baz"}}`, admissionv1.Update), nil, policy)
+		},
+		func() (string, bool) {
+			return cache.Key("mutate", request("b", `{"data":{"foo":"bar"}}`, admissionv1.Update), nil, policy)
+		},
+		func() (string, bool) {
+			return cache.Key("validate", request("b", `{"data":{"foo":"bar"}}`, admissionv1.Update), map[string]string{"team": "a"}, policy)
+		},
+		func() (string, bool) {
+			return cache.Key("validate", request("b", `{"data":{"foo":"bar"}}`, admissionv1.Update), nil, newPolicy(t, "2", validatePolicy))
+		},
+	} {
+		changedKey, ok := changed()
+		assert.Assert(t, ok)
+		assert.Assert(t, changedKey != key)
+	}
+
+	// exception or configuration update
+	polex := &kyvernov2alpha1.PolicyException{}
+	polex.SetName("test")
+	polex.SetResourceVersion("1")
+	*polexs = append(*polexs, polex)
+	changedKey, ok := cache.Key("validate", request("b", `{"data":{"foo":"bar"}}`, admissionv1.Update), nil, policy)
+	assert.Assert(t, ok)
+	assert.Assert(t, changedKey != key)
+	*polexs = nil
+	cm := &corev1.ConfigMap{Data: map[string]string{}}
+	cm.SetResourceVersion("2")
+	configuration.Load(cm)
+	changedKey, ok = cache.Key("validate", request("b", `{"data":{"foo":"bar"}}`, admissionv1.Update), nil, policy)
+	assert.Assert(t, ok)
+	assert.Assert(t, changedKey != key)
+
+	// deletions and requests without policies are not cached
+	_, ok = cache.Key("validate", request("c", `{}`, admissionv1.Delete), nil, policy)
+	assert.Assert(t, !ok)
+	_, ok = cache.Key("validate", request("c", `{}`, admissionv1.Update), nil)
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,43 @@
+package responsecache
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/kyverno/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+)
+
+type cacheMetrics struct {
+	lookupsTotal syncint64.Counter
+}
+
+func newCacheMetrics(logger logr.Logger) cacheMetrics {
+	meter := global.MeterProvider().Meter(metrics.MeterName)
+	lookupsTotal, err := meter.SyncInt64().Counter(
+		"kyverno_admission_response_cache_lookups",
+		instrument.WithDescription("can be used to track the number of admission responses looked up in the response cache, by webhook and result (hit, miss)"),
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create instrument, kyverno_admission_response_cache_lookups")
+	}
+	return cacheMetrics{
+		lookupsTotal: lookupsTotal,
+	}
+}
+
+func (m cacheMetrics) recordLookup(ctx context.Context, webhook string, hit bool) {
+	if m.lookupsTotal != nil {
+		result := "miss"
+		if hit {
+			result = "hit"
+		}
+		m.lookupsTotal.Add(ctx, 1,
+			attribute.String("webhook", webhook),
+			attribute.String("result", result),
+		)
+	}
+}
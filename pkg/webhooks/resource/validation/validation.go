@@ -18,7 +18,10 @@ import (
 	"github.com/kyverno/kyverno/pkg/tracing"
 	admissionutils "github.com/kyverno/kyverno/pkg/utils/admission"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
+	kubeutils "github.com/kyverno/kyverno/pkg/utils/kube"
 	reportutils "github.com/kyverno/kyverno/pkg/utils/report"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
+	"github.com/kyverno/kyverno/pkg/webhooks/resource/decisionlog"
 	webhookutils "github.com/kyverno/kyverno/pkg/webhooks/utils"
 	"go.opentelemetry.io/otel/trace"
 	admissionv1 "k8s.io/api/admission/v1"
@@ -31,7 +34,11 @@ type ValidationHandler interface {
 	// HandleValidation handles validating webhook admission request
 	// If there are no errors in validating rule we apply generation rules
 	// patchedResource is the (resource + patches) after applying mutation rules
-	HandleValidation(context.Context, *admissionv1.AdmissionRequest, []kyvernov1.PolicyInterface, *engine.PolicyContext, time.Time) (bool, string, []string)
+	// It also returns the engine responses the decision was made from
+	HandleValidation(context.Context, *admissionv1.AdmissionRequest, []kyvernov1.PolicyInterface, *engine.PolicyContext, time.Time) (bool, string, []string, []*engineapi.EngineResponse)
+	// HandleCached emits the metrics, events and admission report of a request whose response was computed
+	// for an identical request, from the engine responses of that request
+	HandleCached(context.Context, *admissionv1.AdmissionRequest, map[string]string, bool, []*engineapi.EngineResponse)
 }
 
 func NewValidationHandler(
@@ -76,7 +83,7 @@ func (v *validationHandler) HandleValidation(
 	policies []kyvernov1.PolicyInterface,
 	policyContext *engine.PolicyContext,
 	admissionRequestTimestamp time.Time,
-) (bool, string, []string) {
+) (bool, string, []string, []*engineapi.EngineResponse) {
 	resourceName := admissionutils.GetResourceName(request)
 	logger := v.log.WithValues("action", "validate", "resource", resourceName, "operation", request.Operation, "gvk", request.Kind)
 
@@ -90,7 +97,7 @@ func (v *validationHandler) HandleValidation(
 	}
 
 	if deletionTimeStamp != nil && request.Operation == admissionv1.Update {
-		return true, "", nil
+		return true, "", nil, nil
 	}
 
 	var engineResponses []*engineapi.EngineResponse
@@ -113,9 +120,6 @@ func (v *validationHandler) HandleValidation(
 					return
 				}
 
-				go webhookutils.RegisterPolicyResultsMetricValidation(ctx, logger, v.metrics, string(request.Operation), policyContext.Policy(), *engineResponse)
-				go webhookutils.RegisterPolicyExecutionDurationMetricValidate(ctx, logger, v.metrics, string(request.Operation), policyContext.Policy(), *engineResponse)
-
 				engineResponses = append(engineResponses, engineResponse)
 				if !engineResponse.IsSuccessful() {
 					logger.V(2).Info("validation failed", "action", policy.GetSpec().ValidationFailureAction, "policy", policy.GetName(), "failed rules", engineResponse.GetFailedRules())
@@ -129,21 +133,57 @@ func (v *validationHandler) HandleValidation(
 		)
 	}
 
+	decisionlog.Record(ctx, engineResponses...)
 	blocked := webhookutils.BlockRequest(engineResponses, failurePolicy, logger)
-	if deletionTimeStamp == nil {
-		events := webhookutils.GenerateEvents(engineResponses, blocked)
-		v.eventGen.Add(events...)
-	}
+	v.emit(ctx, logger, request, policyContext.NewResource(), policyContext.NamespaceLabels(), deletionTimeStamp != nil, blocked, engineResponses...)
 
 	if blocked {
 		logger.V(4).Info("admission request blocked")
-		return false, webhookutils.GetBlockedMessages(engineResponses), nil
+		return false, webhookutils.GetBlockedMessages(engineResponses), nil, engineResponses
 	}
 
-	go v.handleAudit(ctx, policyContext.NewResource(), request, policyContext.NamespaceLabels(), engineResponses...)
-
 	warnings := webhookutils.GetWarningMessages(engineResponses)
-	return true, "", warnings
+	return true, "", warnings, engineResponses
+}
+
+func (v *validationHandler) HandleCached(
+	ctx context.Context,
+	request *admissionv1.AdmissionRequest,
+	namespaceLabels map[string]string,
+	allowed bool,
+	engineResponses []*engineapi.EngineResponse,
+) {
+	logger := v.log.WithValues("action", "validate", "resource", admissionutils.GetResourceName(request), "operation", request.Operation, "gvk", request.Kind)
+	resource, err := kubeutils.BytesToUnstructured(request.Object.Raw)
+	if err != nil {
+		logger.Error(err, "failed to convert object resource to unstructured format")
+		return
+	}
+	v.emit(ctx, logger, request, *resource, namespaceLabels, resource.GetDeletionTimestamp() != nil, !allowed, engineResponses...)
+}
+
+// emit registers the metrics, events and admission report of the engine responses
+func (v *validationHandler) emit(
+	ctx context.Context,
+	logger logr.Logger,
+	request *admissionv1.AdmissionRequest,
+	resource unstructured.Unstructured,
+	namespaceLabels map[string]string,
+	deleted bool,
+	blocked bool,
+	engineResponses ...*engineapi.EngineResponse,
+) {
+	for _, engineResponse := range engineResponses {
+		go webhookutils.RegisterPolicyResultsMetricValidation(ctx, logger, v.metrics, string(request.Operation), engineResponse.Policy, *engineResponse)
+		go webhookutils.RegisterPolicyExecutionDurationMetricValidate(ctx, logger, v.metrics, string(request.Operation), engineResponse.Policy, *engineResponse)
+	}
+	if !deleted {
+		events := webhookutils.GenerateEvents(engineResponses, blocked)
+		v.eventGen.Add(events...)
+	}
+	if !blocked {
+		go v.handleAudit(ctx, resource, request, namespaceLabels, engineResponses...)
+	}
 }
 
 func (v *validationHandler) buildAuditResponses(
@@ -152,7 +192,14 @@ func (v *validationHandler) buildAuditResponses(
 	request *admissionv1.AdmissionRequest,
 	namespaceLabels map[string]string,
 ) ([]*engineapi.EngineResponse, error) {
-	policies := v.pCache.GetPolicies(policycache.ValidateAudit, request.Kind.Kind, request.Namespace)
+	scopeID := scopeutils.FromContext(ctx)
+	var policies []kyvernov1.PolicyInterface
+	for _, policy := range v.pCache.GetPolicies(policycache.ValidateAudit, request.Kind.Kind, request.Namespace) {
+		// per policy webhooks only process the policies sharing their scope
+		if scopeID == "" || v.pCache.GetScopeID(policy) == scopeID {
+			policies = append(policies, policy)
+		}
+	}
 	policyContext, err := v.pcBuilder.Build(request)
 	if err != nil {
 		return nil, err
@@ -1051,9 +1051,10 @@ func TestValidate_failure_action_overrides(t *testing.T) {
 
 	eng := engine.NewEngine(
 		config.NewDefaultConfiguration(),
+		config.NewDefaultMetricsConfiguration(),
 		nil,
 		registryclient.NewOrDie(),
-		engine.LegacyContextLoaderFactory(nil),
+		engine.LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()),
 		nil,
 	)
 	for i, tc := range testcases {
@@ -1130,9 +1131,10 @@ func Test_RuleSelector(t *testing.T) {
 
 	eng := engine.NewEngine(
 		config.NewDefaultConfiguration(),
+		config.NewDefaultMetricsConfiguration(),
 		nil,
 		registryclient.NewOrDie(),
-		engine.LegacyContextLoaderFactory(nil),
+		engine.LegacyContextLoaderFactory(nil, config.NewDefaultMetricsConfiguration()),
 		nil,
 	)
 	resp := eng.Validate(
@@ -15,18 +15,22 @@ import (
 	"github.com/kyverno/kyverno/pkg/utils"
 	controllerutils "github.com/kyverno/kyverno/pkg/utils/controller"
 	runtimeutils "github.com/kyverno/kyverno/pkg/utils/runtime"
+	scopeutils "github.com/kyverno/kyverno/pkg/utils/scope"
 	"github.com/kyverno/kyverno/pkg/webhooks/handlers"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 )
 
 // DebugModeOptions holds the options to configure debug mode
 type DebugModeOptions struct {
 	// DumpPayload is used to activate/deactivate debug mode.
 	DumpPayload bool
+	// Dump configures the payloads being dumped
+	Dump handlers.DumpOptions
 }
 
 type Server interface {
@@ -71,6 +75,7 @@ func NewServer(
 	resourceHandlers ResourceHandlers,
 	exceptionHandlers ExceptionHandlers,
 	configuration config.Configuration,
+	nsLister corev1listers.NamespaceLister,
 	metricsConfig metrics.MetricsConfigManager,
 	debugModeOpts DebugModeOptions,
 	tlsProvider TlsProvider,
@@ -91,9 +96,9 @@ func NewServer(
 		resourceHandlers.Mutate,
 		func(handler handlers.AdmissionHandler) handlers.HttpHandler {
 			return handler.
-				WithFilter(configuration).
+				WithFilter(configuration, nsLister).
 				WithProtection(toggle.ProtectManagedResources.Enabled()).
-				WithDump(debugModeOpts.DumpPayload).
+				WithDump(debugModeOpts.DumpPayload, debugModeOpts.Dump).
 				WithOperationFilter(admissionv1.Create, admissionv1.Update, admissionv1.Connect).
 				WithMetrics(resourceLogger, metricsConfig.Config(), metrics.WebhookMutating).
 				WithAdmission(resourceLogger.WithName("mutate"))
@@ -106,9 +111,9 @@ func NewServer(
 		resourceHandlers.Validate,
 		func(handler handlers.AdmissionHandler) handlers.HttpHandler {
 			return handler.
-				WithFilter(configuration).
+				WithFilter(configuration, nsLister).
 				WithProtection(toggle.ProtectManagedResources.Enabled()).
-				WithDump(debugModeOpts.DumpPayload).
+				WithDump(debugModeOpts.DumpPayload, debugModeOpts.Dump).
 				WithMetrics(resourceLogger, metricsConfig.Config(), metrics.WebhookValidating).
 				WithAdmission(resourceLogger.WithName("validate"))
 		},
@@ -117,7 +122,7 @@ func NewServer(
 		"POST",
 		config.PolicyMutatingWebhookServicePath,
 		handlers.FromAdmissionFunc("MUTATE", policyHandlers.Mutate).
-			WithDump(debugModeOpts.DumpPayload).
+			WithDump(debugModeOpts.DumpPayload, debugModeOpts.Dump).
 			WithMetrics(policyLogger, metricsConfig.Config(), metrics.WebhookMutating).
 			WithAdmission(policyLogger.WithName("mutate")).
 			ToHandlerFunc(),
@@ -126,7 +131,7 @@ func NewServer(
 		"POST",
 		config.PolicyValidatingWebhookServicePath,
 		handlers.FromAdmissionFunc("VALIDATE", policyHandlers.Validate).
-			WithDump(debugModeOpts.DumpPayload).
+			WithDump(debugModeOpts.DumpPayload, debugModeOpts.Dump).
 			WithSubResourceFilter().
 			WithMetrics(policyLogger, metricsConfig.Config(), metrics.WebhookValidating).
 			WithAdmission(policyLogger.WithName("validate")).
@@ -136,7 +141,7 @@ func NewServer(
 		"POST",
 		config.ExceptionValidatingWebhookServicePath,
 		handlers.FromAdmissionFunc("VALIDATE", exceptionHandlers.Validate).
-			WithDump(debugModeOpts.DumpPayload).
+			WithDump(debugModeOpts.DumpPayload, debugModeOpts.Dump).
 			WithSubResourceFilter().
 			WithMetrics(exceptionLogger, metricsConfig.Config(), metrics.WebhookValidating).
 			WithAdmission(exceptionLogger.WithName("validate")).
@@ -151,6 +156,7 @@ func NewServer(
 	)
 	mux.HandlerFunc("GET", config.LivenessServicePath, handlers.Probe(runtime.IsLive))
 	mux.HandlerFunc("GET", config.ReadinessServicePath, handlers.Probe(runtime.IsReady))
+	mux.HandlerFunc("GET", config.StatusServicePath, handlers.Status(runtime.Status))
 	return &server{
 		server: &http.Server{
 			Addr: ":9443",
@@ -254,16 +260,19 @@ func registerWebhookHandlers(
 	ignore := handlers.FromAdmissionFunc(
 		name,
 		func(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, startTime time.Time) *admissionv1.AdmissionResponse {
-			return handlerFunc(ctx, logger, request, "ignore", startTime)
+			return handlerFunc(scopeutils.NewContext(ctx, httprouter.ParamsFromContext(ctx).ByName("scope")), logger, request, "ignore", startTime)
 		},
 	)
 	fail := handlers.FromAdmissionFunc(
 		name,
 		func(ctx context.Context, logger logr.Logger, request *admissionv1.AdmissionRequest, startTime time.Time) *admissionv1.AdmissionResponse {
-			return handlerFunc(ctx, logger, request, "fail", startTime)
+			return handlerFunc(scopeutils.NewContext(ctx, httprouter.ParamsFromContext(ctx).ByName("scope")), logger, request, "fail", startTime)
 		},
 	)
 	mux.HandlerFunc("POST", basePath, builder(all).ToHandlerFunc())
 	mux.HandlerFunc("POST", basePath+"/ignore", builder(ignore).ToHandlerFunc())
 	mux.HandlerFunc("POST", basePath+"/fail", builder(fail).ToHandlerFunc())
+	// per policy webhooks append the scope of their policies to the path
+	mux.HandlerFunc("POST", basePath+"/ignore/:scope", builder(ignore).ToHandlerFunc())
+	mux.HandlerFunc("POST", basePath+"/fail/:scope", builder(fail).ToHandlerFunc())
 }